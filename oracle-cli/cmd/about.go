@@ -33,7 +33,7 @@ var aboutCmd = &cobra.Command{
 
 		// Create a Bearer string by appending string access token
 		var bearer = "Bearer " + utils.Settings.Settings.GetOracleKey()
-		req, err := http.NewRequest("GET", utils.OracleAddress()+"/about", nil)
+		req, err := http.NewRequest("GET", utils.OracleAddress()+"/about?chain="+chain, nil)
 		// add authorization header to the req
 		req.Header.Add("Authorization", bearer)
 		client := &http.Client{}
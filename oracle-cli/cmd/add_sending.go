@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/cobra"
+	"io/ioutil"
+	"net/http"
+	"oraclecli/models"
+	"oraclecli/utils"
+)
+
+// addSendingCmd represents the addsending command
+var addSendingCmd = &cobra.Command{
+	Use:   "addsending",
+	Short: "Add an account which pays for gas and sends fulfillments",
+	Long: `Use this command to add a sending account to the oracle's keystore. Sending
+accounts pay for gas and send fulfillments, so the VOR proving keys never sign
+a Tx. Leave the private key empty to generate a new one. The account's address
+is printed - send it some ETH before it's used.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := AddSending(cmd, args)
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func AddSending(cmd *cobra.Command, args []string) (err error) {
+	accountName, err := GetUsername()
+	if err != nil {
+		return
+	}
+	var privateKey string
+	fmt.Println("")
+	fmt.Print("Private Key (NOTE: it has to start with 0x), or empty to generate one: ")
+	fmt.Scanf("%s\n", &privateKey)
+
+	requestStruct := models.OracleAddSendingRequestModel{
+		AccountName: accountName,
+		PrivateKey:  privateKey,
+	}
+	requestJSON, err := json.Marshal(requestStruct)
+	if err != nil {
+		fmt.Println("Can't marshal request")
+		return
+	}
+	request := bytes.NewBuffer(requestJSON)
+
+	// Create a Bearer string by appending string access token
+	var bearer = "Bearer " + utils.Settings.Settings.GetOracleKey()
+	req, err := http.NewRequest("POST", fmt.Sprint(utils.OracleAddress(), "/addsending"), request)
+	if err != nil {
+		return
+	}
+	// add authorization header to the req
+	req.Header.Add("Authorization", bearer)
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		fmt.Println("Something went wrong.")
+		return
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	fmt.Println(string(body))
+	return
+}
+
+func init() {
+	rootCmd.AddCommand(addSendingCmd)
+}
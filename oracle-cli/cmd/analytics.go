@@ -62,8 +62,8 @@ oraclecli analytics 1000
 
 		cgPrices := GetXfundPrice()
 
-		url := fmt.Sprintf("%s/analytics?eth=%f&usd=%f&limit=%d&gasprice=0&fees=0&consumer=&sim=0",
-			utils.OracleAddress(), cgPrices.Xfund.Eth, cgPrices.Xfund.Usd, numToAnalyse)
+		url := fmt.Sprintf("%s/analytics?eth=%f&usd=%f&limit=%d&gasprice=0&fees=0&consumer=&sim=0&account=%s&chain=%s",
+			utils.OracleAddress(), cgPrices.Xfund.Eth, cgPrices.Xfund.Usd, numToAnalyse, account, chain)
 
 		client := &http.Client{}
 		// Create a Bearer string by appending string access token
@@ -31,8 +31,8 @@ oraclecli analytics consumers 0x1234AbcD...
 			c = args[0]
 		}
 
-		url := fmt.Sprintf("%s/consumers?eth=%f&usd=%f&consumer=%s",
-			utils.OracleAddress(), cgPrices.Xfund.Eth, cgPrices.Xfund.Usd, c)
+		url := fmt.Sprintf("%s/consumers?eth=%f&usd=%f&consumer=%s&account=%s&chain=%s",
+			utils.OracleAddress(), cgPrices.Xfund.Eth, cgPrices.Xfund.Usd, c, account, chain)
 
 		client := &http.Client{}
 		// Create a Bearer string by appending string access token
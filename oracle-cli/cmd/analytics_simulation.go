@@ -60,8 +60,8 @@ oraclecli analytics sim 200 --if-gas=100 --if-fees=0.05 --consumer=0x232AbC...
 
 		cgPrices := GetXfundPrice()
 
-		url := fmt.Sprintf("%s/analytics?eth=%f&usd=%f&limit=%d&gasprice=%d&fees=%f&sim=1&consumer=%s",
-			utils.OracleAddress(), cgPrices.Xfund.Eth, cgPrices.Xfund.Usd, numToAnalyse, ifGas, ifFees, consumer)
+		url := fmt.Sprintf("%s/analytics?eth=%f&usd=%f&limit=%d&gasprice=%d&fees=%f&sim=1&consumer=%s&account=%s&chain=%s",
+			utils.OracleAddress(), cgPrices.Xfund.Eth, cgPrices.Xfund.Usd, numToAnalyse, ifGas, ifFees, consumer, account, chain)
 
 		client := &http.Client{}
 		// Create a Bearer string by appending string access token
@@ -36,6 +36,8 @@ var changeGranularFeeCmd = &cobra.Command{
 		amount, err := GetFee()
 		c, err := GetConsumerContractAddress()
 		requestStruct := models.OracleChangeGranularFeeRequestModel{
+			Chain:    chain,
+			Account:  account,
 			Amount:   amount,
 			Consumer: c,
 		}
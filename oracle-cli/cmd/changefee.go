@@ -35,7 +35,9 @@ var changefeeCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		amount, err := GetFee()
 		requestStruct := models.OracleChangeFeeRequestModel{
-			Amount: amount,
+			Chain:   chain,
+			Account: account,
+			Amount:  amount,
 		}
 		requestJSON, err := json.Marshal(requestStruct)
 		if err != nil {
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"oraclecli/models"
+	"oraclecli/utils"
+)
+
+var credentialScopes []string
+
+// credentialsCmd represents the credentials command
+var credentialsCmd = &cobra.Command{
+	Use:   "credentials",
+	Short: "Manage scoped API keys",
+	Long: `
+Use the credentials commands to create, list and revoke named API keys which
+are limited to some scopes, so the HTTP/cli key doesn't have to be handed out.
+Scopes are:
+
+read  - requests, analytics, fees, balances, txs, proofs and metrics
+fees  - changefee and changegranularfee
+funds - withdraw
+admin - everything except exporting keys and rotatetoken, which need the
+        HTTP/cli key itself
+
+Credentials can't decrypt the keystore.
+`,
+}
+
+var credentialsCreateCmd = &cobra.Command{
+	Use:   "create [name]",
+	Short: "Create a scoped API key",
+	Long: `
+Create a named API key with the scopes passed with --scopes. The key is only
+shown once.
+
+Example:
+
+oraclecli credentials create dashboard --scopes read
+oraclecli credentials create treasury --scopes read,funds
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := credentialsRequest("POST", "/credentials", models.OracleCreateCredentialRequestModel{
+			Name:   args[0],
+			Scopes: credentialScopes,
+		})
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var credentialsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the scoped API keys",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := credentialsRequest("GET", "/credentials", nil)
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var credentialsRevokeCmd = &cobra.Command{
+	Use:   "revoke [name]",
+	Short: "Revoke a scoped API key",
+	Long: `
+Revoke a named API key. It stops working straight away.
+
+Example:
+
+oraclecli credentials revoke dashboard
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := credentialsRequest("POST", "/credentials/revoke", models.OracleRevokeCredentialRequestModel{
+			Name: args[0],
+		})
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+// credentialsRequest sends a credentials request, with requestStruct as the JSON body if
+// it isn't nil, and prints the response
+func credentialsRequest(method string, path string, requestStruct interface{}) error {
+	var request io.Reader
+	if requestStruct != nil {
+		requestJSON, err := json.Marshal(requestStruct)
+		if err != nil {
+			return errors.New("can't marshal request")
+		}
+		request = bytes.NewBuffer(requestJSON)
+	}
+
+	// Create a Bearer string by appending string access token
+	var bearer = "Bearer " + utils.Settings.Settings.GetOracleKey()
+	req, err := http.NewRequest(method, fmt.Sprint(utils.OracleAddress(), path), request)
+	if err != nil {
+		return err
+	}
+	// add authorization header to the req
+	req.Header.Add("Authorization", bearer)
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(body))
+	return nil
+}
+
+func init() {
+	credentialsCreateCmd.Flags().StringSliceVarP(&credentialScopes, "scopes", "s", []string{"read"}, "comma separated scopes: read, fees, funds, admin")
+	credentialsCmd.AddCommand(credentialsCreateCmd)
+	credentialsCmd.AddCommand(credentialsListCmd)
+	credentialsCmd.AddCommand(credentialsRevokeCmd)
+	rootCmd.AddCommand(credentialsCmd)
+}
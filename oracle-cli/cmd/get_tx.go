@@ -24,7 +24,7 @@ var gettxCmd = &cobra.Command{
 
 		txHash := args[0]
 
-		url := fmt.Sprintf("%s/tx?tx_hash=%s", utils.OracleAddress(), txHash)
+		url := fmt.Sprintf("%s/tx?tx_hash=%s&chain=%s", utils.OracleAddress(), txHash, chain)
 
 		// Create a Bearer string by appending string access token
 		var bearer = "Bearer " + utils.Settings.Settings.GetOracleKey()
@@ -26,6 +26,8 @@ var queryfeesCmd = &cobra.Command{
 		}
 
 		requestStruct := models.OracleQueryFeesModel{
+			Chain:    chain,
+			Account:  account,
 			Consumer: c,
 		}
 		requestJSON, err := json.Marshal(requestStruct)
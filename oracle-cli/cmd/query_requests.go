@@ -30,17 +30,23 @@ The --status | -s flag accepts the following:
  2  = fulfill tx sent
  3  = failed to fulfil
  4  = fulfillment succeeded
+ 5  = fulfillment failed (too many attempts, request too old etc.)
+ 6  = reorged (request or fulfillment block orphaned, being re-checked)
+ 7  = proof invalid (local proof verification failed, not sent)
+ 8  = already fulfilled (coordinator has no pending request, not sent)
 
 Examples:
 $ oraclecli queryrequests --page=2 --limit=20
 $ oraclecli queryrequests --status=3
 $ oraclecli queryrequests --order=asc
+$ oraclecli queryrequests --account=tier2
+$ oraclecli queryrequests --chain=polygon
 `,
 	Run: func(cmd *cobra.Command, args []string) {
 
 		// Create a Bearer string by appending string access token
 		var bearer = "Bearer " + utils.Settings.Settings.GetOracleKey()
-		url := fmt.Sprintf("%s/requests?page=%d&limit=%d&order=%s&status=%d", utils.OracleAddress(), page, limit, order, status)
+		url := fmt.Sprintf("%s/requests?page=%d&limit=%d&order=%s&status=%d&account=%s&chain=%s", utils.OracleAddress(), page, limit, order, status, account, chain)
 		fmt.Println("url", url)
 		req, err := http.NewRequest("GET", url, nil)
 		// add authorization header to the req
@@ -19,7 +19,7 @@ var querywithdrawableCmd = &cobra.Command{
 
 		// Create a Bearer string by appending string access token
 		var bearer = "Bearer " + utils.Settings.Settings.GetOracleKey()
-		req, err := http.NewRequest("GET", utils.OracleAddress()+"/querywithdrawable", nil)
+		req, err := http.NewRequest("GET", utils.OracleAddress()+"/querywithdrawable?account="+account+"&chain="+chain, nil)
 		// add authorization header to the req
 		req.Header.Add("Authorization", bearer)
 		client := &http.Client{}
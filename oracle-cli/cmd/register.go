@@ -32,7 +32,10 @@ import (
 var registerCmd = &cobra.Command{
 	Use:   "register",
 	Short: "Register your new Oracle",
-	Long: `Use this command to register your new oracle
+	Long: `Use this command to register your new oracle.
+
+Pass --stored to register a key which is already in the oracle's keystore, e.g.
+one imported with "oracle keys import", without entering its private key.
 `,
 	Run: func(cmd *cobra.Command, args []string) {
 		err := Register(cmd, args)
@@ -44,7 +47,10 @@ var registerCmd = &cobra.Command{
 
 func Register(cmd *cobra.Command, args []string) (err error) {
 	accountName, err := GetUsername()
-	privateKey, err := GetPrivateKey()
+	var privateKey string
+	if !stored {
+		privateKey, err = GetPrivateKey()
+	}
 	fee, err := GetFee()
 	if err != nil {
 		fmt.Println("Sorry, there is a problem with data you entered =(")
@@ -52,6 +58,7 @@ func Register(cmd *cobra.Command, args []string) (err error) {
 		return
 	}
 	requestStruct := models.OracleRegisterRequestModel{
+		Chain:       chain,
 		AccountName: accountName,
 		PrivateKey:  privateKey,
 		Fee:         fee,
@@ -137,7 +144,10 @@ func GetConsumerContractAddress() (input string, err error) {
 	return
 }
 
+var stored bool
+
 func init() {
+	registerCmd.Flags().BoolVar(&stored, "stored", false, "register a key already in the oracle's keystore")
 	rootCmd.AddCommand(registerCmd)
 
 	// Here you will define your flags and configuration settings.
@@ -16,6 +16,7 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -23,12 +24,19 @@ import (
 	"path/filepath"
 
 	"github.com/spf13/cobra"
+	"oraclecli/models"
 	"oraclecli/utils"
 )
 
 var cfgFile string
 var showVersion bool
 
+// keystore account of the proving key to use. The oracle's default key is used if empty
+var account string
+
+// name or chain ID of the chain to use. The oracle's first chain is used if empty
+var chain string
+
 func initSettings() {
 	settings, err := utils.NewSettingsStore(cfgFile)
 	if err != nil {
@@ -52,7 +60,7 @@ Note:
 	Run: func(cmd *cobra.Command, args []string) {
 		// Create a Bearer string by appending string access token
 		var bearer = "Bearer " + utils.Settings.Settings.GetOracleKey()
-		req, err := http.NewRequest("GET", utils.OracleAddress()+"/status", nil)
+		req, err := http.NewRequest("GET", utils.OracleAddress()+"/readyz", nil)
 		// add authorization header to the req
 		req.Header.Add("Authorization", bearer)
 		client := &http.Client{}
@@ -60,11 +68,31 @@ Note:
 
 		if err != nil {
 			fmt.Println(`Sorry, something went wrong =(`)
+			fmt.Println(err)
 			return
 		}
 		defer resp.Body.Close()
 		body, err := ioutil.ReadAll(resp.Body)
-		fmt.Println("Oracle status: ", string(body))
+
+		var status models.HealthStatus
+		err = json.Unmarshal(body, &status)
+		if err != nil {
+			fmt.Println("Oracle status: ", string(body))
+			return
+		}
+
+		if status.Healthy {
+			fmt.Println("Oracle status:  ready")
+		} else {
+			fmt.Println("Oracle status:  not ready")
+		}
+		for _, check := range status.Checks {
+			result := "OK  "
+			if !check.Healthy {
+				result = "FAIL"
+			}
+			fmt.Printf("  %-12s %s  %4dms  %s\n", check.Name, result, check.DurationMs, check.Message)
+		}
 	},
 }
 
@@ -79,6 +107,10 @@ func Execute() {
 
 	rootCmd.PersistentFlags().StringVarP(&cfgFile, "conf", "c",
 		filepath.Join(homepath, ".oracle-cli_settings.json"), "oraclecli settings file")
+	rootCmd.PersistentFlags().StringVarP(&account, "account", "a", "",
+		"keystore account of the proving key to use, for oracles serving several keys. Defaults to the oracle's default key")
+	rootCmd.PersistentFlags().StringVar(&chain, "chain", "",
+		"name or chain ID of the chain to use, for oracles serving several chains. Defaults to the oracle's first chain")
 
 	cobra.CheckErr(rootCmd.Execute())
 }
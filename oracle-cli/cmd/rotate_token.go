@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/cobra"
+	"io/ioutil"
+	"net/http"
+	"oraclecli/models"
+	"oraclecli/utils"
+)
+
+// rotateTokenCmd represents the rotatetoken command
+var rotateTokenCmd = &cobra.Command{
+	Use:   "rotatetoken",
+	Short: "Replace the oracle's HTTP/cli key",
+	Long: `Use this command to replace the oracle's HTTP/cli key. The keystore is
+re-encrypted under the new key, and the old one stops working straight away.
+The new key is saved in the CLI settings and printed - update the file the
+oracle is started with -k, if any.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := RotateToken(cmd, args)
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func RotateToken(cmd *cobra.Command, args []string) (err error) {
+	// Create a Bearer string by appending string access token
+	var bearer = "Bearer " + utils.Settings.Settings.GetOracleKey()
+	req, err := http.NewRequest("POST", fmt.Sprint(utils.OracleAddress(), "/rotatetoken"), bytes.NewBuffer([]byte("")))
+	if err != nil {
+		return
+	}
+	// add authorization header to the req
+	req.Header.Add("Authorization", bearer)
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		fmt.Println("Something went wrong.")
+		return
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		fmt.Println(string(body))
+		return
+	}
+
+	var response models.OracleRotateTokenResponseModel
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return
+	}
+	fmt.Println("New key:", response.Token)
+	err = utils.Settings.SetOracleKey(response.Token)
+	if err != nil {
+		return
+	}
+	fmt.Println("Saved to the CLI settings")
+	return
+}
+
+func init() {
+	rootCmd.AddCommand(rotateTokenCmd)
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"oraclecli/utils"
+)
+
+var (
+	txState   string
+	txPurpose string
+	txFrom    string
+)
+
+// txsCmd represents the txs command
+var txsCmd = &cobra.Command{
+	Use:   "txs",
+	Short: "get the Txs the oracle has sent",
+	Long: `Query the oracle's Tx ledger. Every Tx is saved before it's broadcast, and
+followed until it's confirmed.
+
+State filters:
+The --state | -s flag accepts the following:
+
+ unbroadcast = saved, but not yet accepted by a node
+ unconfirmed = broadcast, waiting for wait_confirmations blocks
+ confirmed   = mined with enough confirmations
+ reverted    = mined with enough confirmations, but reverted
+ replaced    = another Tx with the same nonce was mined, e.g. a gas bump
+ failed      = rejected by the node
+
+Purpose filters:
+The --purpose flag accepts fulfillment, store_block_hash, register, change_fee,
+change_granular_fee, withdraw, randomness_request or top_up.
+
+Examples:
+$ oraclecli txs --page=2 --limit=20
+$ oraclecli txs --state=unconfirmed
+$ oraclecli txs --purpose=fulfillment --from=0x...
+$ oraclecli txs --chain=polygon
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		query := url.Values{}
+		query.Set("page", fmt.Sprint(page))
+		query.Set("limit", fmt.Sprint(limit))
+		query.Set("order", order)
+		query.Set("state", txState)
+		query.Set("purpose", txPurpose)
+		query.Set("from", txFrom)
+		query.Set("chain", chain)
+
+		// Create a Bearer string by appending string access token
+		var bearer = "Bearer " + utils.Settings.Settings.GetOracleKey()
+		req, err := http.NewRequest("GET", fmt.Sprintf("%s/txs?%s", utils.OracleAddress(), query.Encode()), nil)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		// add authorization header to the req
+		req.Header.Add("Authorization", bearer)
+		client := &http.Client{}
+		resp, err := client.Do(req)
+
+		if err != nil {
+			fmt.Println(`Sorry, something went wrong =(`)
+			fmt.Println(err)
+			return
+		}
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		fmt.Println(string(body))
+	},
+}
+
+func init() {
+	txsCmd.Flags().UintVarP(&page, "page", "p", 1, "page number")
+	txsCmd.Flags().UintVarP(&limit, "limit", "l", 10, "results to return per page")
+	txsCmd.Flags().StringVarP(&txState, "state", "s", "", "tx state")
+	txsCmd.Flags().StringVar(&txPurpose, "purpose", "", "tx purpose")
+	txsCmd.Flags().StringVar(&txFrom, "from", "", "sending account address")
+	txsCmd.Flags().StringVarP(&order, "order", "o", "desc", "order asc | desc")
+	rootCmd.AddCommand(txsCmd)
+}
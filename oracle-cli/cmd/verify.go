@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"oracle/service"
+	"oraclecli/models"
+	"oraclecli/utils"
+)
+
+var (
+	verifyProof     string
+	verifyBlockHash string
+)
+
+// verifyCmd represents the verify command
+var verifyCmd = &cobra.Command{
+	Use:   "verify [fulfill_tx_hash]",
+	Short: "verify a fulfillment proof",
+	Long: `Decodes the proof sent in a fulfillRandomnessRequest Tx, and verifies it against
+the hash of the block the request was made in. Prints the proving public key, seed,
+final seed, gamma and randomness output, along with a PASS/FAIL result.
+
+A raw proof can be verified by passing the proof hex and request block hash instead
+of a Tx hash. This is done locally, and needs neither a running oracle daemon nor
+any chain lookups.
+
+Examples:
+$ oraclecli verify 0x1234...
+$ oraclecli verify --proof 0xabcd... --block-hash 0x5678...
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 && (verifyProof == "" || verifyBlockHash == "") {
+			return errors.New("requires a tx hash, or --proof and --block-hash")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			proofBytes, err := hexutil.Decode(verifyProof)
+			if err != nil {
+				fmt.Println("invalid proof:", err)
+				return
+			}
+			proof, err := service.ProofFromBytes(proofBytes)
+			if err != nil {
+				fmt.Println("invalid proof:", err)
+				return
+			}
+			printProofVerification(models.ProofVerification(service.VerifyProof(proof, common.HexToHash(verifyBlockHash))))
+			return
+		}
+
+		query := url.Values{}
+		query.Set("tx_hash", args[0])
+		query.Set("chain", chain)
+
+		// Create a Bearer string by appending string access token
+		var bearer = "Bearer " + utils.Settings.Settings.GetOracleKey()
+		req, err := http.NewRequest("GET", fmt.Sprintf("%s/verify?%s", utils.OracleAddress(), query.Encode()), nil)
+		// add authorization header to the req
+		req.Header.Add("Authorization", bearer)
+		client := &http.Client{}
+		resp, err := client.Do(req)
+
+		if err != nil {
+			fmt.Println("Something went wrong.")
+			return
+		}
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		if resp.StatusCode != http.StatusOK {
+			fmt.Println(string(body))
+			return
+		}
+
+		var v models.ProofVerification
+		err = json.Unmarshal(body, &v)
+		if err != nil {
+			fmt.Println(string(body))
+			return
+		}
+		printProofVerification(v)
+	},
+}
+
+func printProofVerification(v models.ProofVerification) {
+	if v.FulfillTxHash != "" {
+		fmt.Println("fulfill tx: ", v.FulfillTxHash)
+	}
+	fmt.Println("public key: ", v.PublicKey)
+	fmt.Println("key hash:   ", v.KeyHash)
+	fmt.Println("seed:       ", v.Seed)
+	fmt.Println("block num:  ", v.BlockNumber)
+	fmt.Println("block hash: ", v.BlockHash)
+	fmt.Println("final seed: ", v.FinalSeed)
+	fmt.Println("gamma:      ", v.Gamma)
+	fmt.Println("output:     ", v.Output)
+	if !v.Valid {
+		fmt.Println("error:      ", v.Error)
+		fmt.Println("result:      FAIL")
+		return
+	}
+	fmt.Println("result:      PASS")
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+	verifyCmd.Flags().StringVar(&verifyProof, "proof", "", "raw proof hex, as sent to the VORCoordinator")
+	verifyCmd.Flags().StringVar(&verifyBlockHash, "block-hash", "", "hash of the block the request was made in")
+}
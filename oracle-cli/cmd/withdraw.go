@@ -37,6 +37,8 @@ var withdrawCmd = &cobra.Command{
 		amount, err := GetAmount()
 		address, err := GetAddress()
 		requestStruct := models.OracleWithdrawRequestModel{
+			Chain:   chain,
+			Account: account,
 			Address: address,
 			Amount:  amount,
 		}
@@ -2,4 +2,10 @@ module oraclecli
 
 go 1.16
 
-require github.com/spf13/cobra v1.1.3
+require (
+	github.com/ethereum/go-ethereum v1.10.26
+	github.com/spf13/cobra v1.1.3
+	oracle v0.0.0-00010101000000-000000000000
+)
+
+replace oracle => ../oracle
@@ -1,25 +1,78 @@
 package models
 
 type OracleWithdrawRequestModel struct {
+	Chain   string `json:"chain"`
+	Account string `json:"account"`
 	Address string `json:"address"`
 	Amount  int64  `json:"amount"`
 }
 
 type OracleChangeFeeRequestModel struct {
-	Amount int64 `json:"amount"`
+	Chain   string `json:"chain"`
+	Account string `json:"account"`
+	Amount  int64  `json:"amount"`
 }
 
 type OracleChangeGranularFeeRequestModel struct {
+	Chain    string `json:"chain"`
+	Account  string `json:"account"`
 	Consumer string `json:"consumer"`
 	Amount   int64  `json:"amount"`
 }
 
 type OracleRegisterRequestModel struct {
+	Chain       string `json:"chain"`
 	AccountName string `json:"account_name"`
 	PrivateKey  string `json:"private_key"`
 	Fee         int64  `json:"fee"`
 }
 
+type OracleAddSendingRequestModel struct {
+	AccountName string `json:"account_name"`
+	PrivateKey  string `json:"private_key"`
+}
+
 type OracleQueryFeesModel struct {
+	Chain    string `json:"chain"`
+	Account  string `json:"account"`
 	Consumer string `json:"consumer"`
 }
+
+type ProofVerification struct {
+	FulfillTxHash string `json:"fulfill_tx_hash,omitempty"`
+	PublicKey     string `json:"public_key"`
+	KeyHash       string `json:"key_hash"`
+	Seed          string `json:"seed"`
+	BlockNumber   uint64 `json:"block_num"`
+	BlockHash     string `json:"block_hash"`
+	FinalSeed     string `json:"final_seed"`
+	Gamma         string `json:"gamma"`
+	Output        string `json:"output"`
+	Valid         bool   `json:"valid"`
+	Error         string `json:"error,omitempty"`
+}
+
+type HealthCheck struct {
+	Name       string `json:"name"`
+	Healthy    bool   `json:"healthy"`
+	Message    string `json:"message,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+type HealthStatus struct {
+	Healthy bool          `json:"healthy"`
+	Checks  []HealthCheck `json:"checks"`
+}
+
+type OracleRotateTokenResponseModel struct {
+	Token string `json:"token"`
+}
+
+type OracleCreateCredentialRequestModel struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+type OracleRevokeCredentialRequestModel struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,137 @@
+package alerts
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"oracle/config"
+	"sync"
+	"time"
+)
+
+const (
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+	SeverityResolved = "resolved"
+)
+
+// default time before an unresolved alert is re-sent
+const defaultCooldown = time.Hour
+
+type Alert struct {
+	// Key identifies the condition being alerted on, and is used for deduplication
+	Key      string    `json:"key"`
+	Severity string    `json:"severity"`
+	Title    string    `json:"title"`
+	Message  string    `json:"message"`
+	Time     time.Time `json:"time"`
+	// name of the chain the alert is for, if the oracle serves several
+	Chain string `json:"chain,omitempty"`
+}
+
+// Sink delivers alerts to an external service
+type Sink interface {
+	Send(ctx context.Context, alert Alert) error
+}
+
+// Alerter sends alerts to its sinks. An alert is only re-sent once its cooldown has passed,
+// so a condition checked every cycle doesn't flood the sinks. Resolving an alert sends a
+// resolved notification, and allows it to fire again immediately if the condition recurs.
+type Alerter struct {
+	sinks    []Sink
+	chain    string
+	cooldown time.Duration
+	logger   *logrus.Logger
+	mu       sync.Mutex
+	lastSent map[string]time.Time
+	now      func() time.Time
+}
+
+func NewAlerter(sinks []Sink, cooldown time.Duration, logger *logrus.Logger) *Alerter {
+	if cooldown <= 0 {
+		cooldown = defaultCooldown
+	}
+	return &Alerter{
+		sinks:    sinks,
+		cooldown: cooldown,
+		logger:   logger,
+		lastSent: make(map[string]time.Time),
+		now:      time.Now,
+	}
+}
+
+// NewAlerterFromConfig creates an Alerter sending to the webhooks in the alerts config.
+// With no webhooks configured, alerts are dropped.
+func NewAlerterFromConfig(conf *config.Alerts, logger *logrus.Logger) *Alerter {
+	if conf == nil {
+		return NewAlerter(nil, 0, logger)
+	}
+	var sinks []Sink
+	for _, webhook := range conf.Webhooks {
+		sinks = append(sinks, NewWebhookSink(webhook.URL, webhook.Format))
+	}
+	return NewAlerter(sinks, time.Duration(conf.Cooldown)*time.Second, logger)
+}
+
+// SetChain tags the alerts sent with the name of the chain they're for
+func (a *Alerter) SetChain(chain string) {
+	if a == nil {
+		return
+	}
+	a.chain = chain
+}
+
+// Fire sends an alert, unless one with the same key was sent within the cooldown.
+// It returns true if the alert was sent.
+func (a *Alerter) Fire(alert Alert) bool {
+	if a == nil {
+		return false
+	}
+	a.mu.Lock()
+	now := a.now()
+	if last, ok := a.lastSent[alert.Key]; ok && now.Sub(last) < a.cooldown {
+		a.mu.Unlock()
+		return false
+	}
+	a.lastSent[alert.Key] = now
+	a.mu.Unlock()
+
+	alert.Time = now
+	a.send(alert)
+	return true
+}
+
+// Resolve clears an alert. If it had fired, a resolved notification is sent.
+func (a *Alerter) Resolve(key string, title string, message string) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	_, active := a.lastSent[key]
+	delete(a.lastSent, key)
+	a.mu.Unlock()
+
+	if active {
+		a.send(Alert{
+			Key:      key,
+			Severity: SeverityResolved,
+			Title:    title,
+			Message:  message,
+			Time:     a.now(),
+		})
+	}
+}
+
+func (a *Alerter) send(alert Alert) {
+	alert.Chain = a.chain
+	for _, sink := range a.sinks {
+		err := sink.Send(context.Background(), alert)
+		if err != nil && a.logger != nil {
+			a.logger.WithFields(logrus.Fields{
+				"package":  "alerts",
+				"function": "send",
+				"action":   "send alert",
+				"key":      alert.Key,
+			}).Error(err.Error())
+		}
+	}
+}
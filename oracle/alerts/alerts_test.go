@@ -0,0 +1,131 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type webhookRecorder struct {
+	mu       sync.Mutex
+	payloads []map[string]interface{}
+}
+
+func (r *webhookRecorder) handler(w http.ResponseWriter, req *http.Request) {
+	var payload map[string]interface{}
+	_ = json.NewDecoder(req.Body).Decode(&payload)
+	r.mu.Lock()
+	r.payloads = append(r.payloads, payload)
+	r.mu.Unlock()
+	w.WriteHeader(http.StatusOK)
+}
+
+func newTestAlerter(t *testing.T, format string, cooldown time.Duration) (*Alerter, *webhookRecorder, *time.Time) {
+	recorder := &webhookRecorder{}
+	server := httptest.NewServer(http.HandlerFunc(recorder.handler))
+	t.Cleanup(server.Close)
+
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	alerter := NewAlerter([]Sink{NewWebhookSink(server.URL, format)}, cooldown, nil)
+	alerter.now = func() time.Time { return now }
+	return alerter, recorder, &now
+}
+
+func lowBalanceAlert() Alert {
+	return Alert{
+		Key:      "low_eth_balance",
+		Severity: SeverityCritical,
+		Title:    "Low ETH balance",
+		Message:  "0.01 ETH",
+	}
+}
+
+func TestAlerter_JSONPayload(t *testing.T) {
+	alerter, recorder, _ := newTestAlerter(t, FormatJSON, time.Hour)
+
+	assert.True(t, alerter.Fire(lowBalanceAlert()))
+	assert.Len(t, recorder.payloads, 1)
+	assert.Equal(t, "low_eth_balance", recorder.payloads[0]["key"])
+	assert.Equal(t, "critical", recorder.payloads[0]["severity"])
+	assert.Equal(t, "Low ETH balance", recorder.payloads[0]["title"])
+	assert.Equal(t, "0.01 ETH", recorder.payloads[0]["message"])
+	assert.Equal(t, "2021-06-01T12:00:00Z", recorder.payloads[0]["time"])
+}
+
+func TestAlerter_SlackAndDiscordPayloads(t *testing.T) {
+	alerter, recorder, _ := newTestAlerter(t, FormatSlack, time.Hour)
+	alerter.Fire(lowBalanceAlert())
+	assert.Equal(t, map[string]interface{}{"text": "[CRITICAL] Low ETH balance\n0.01 ETH"}, recorder.payloads[0])
+
+	alerter, recorder, _ = newTestAlerter(t, FormatDiscord, time.Hour)
+	alerter.Fire(lowBalanceAlert())
+	assert.Equal(t, map[string]interface{}{"content": "[CRITICAL] Low ETH balance\n0.01 ETH"}, recorder.payloads[0])
+}
+
+func TestAlerter_Chain(t *testing.T) {
+	alerter, recorder, _ := newTestAlerter(t, FormatJSON, time.Hour)
+	alerter.SetChain("polygon")
+	alerter.Fire(lowBalanceAlert())
+	assert.Equal(t, "polygon", recorder.payloads[0]["chain"])
+
+	alerter, recorder, _ = newTestAlerter(t, FormatSlack, time.Hour)
+	alerter.SetChain("polygon")
+	alerter.Fire(lowBalanceAlert())
+	assert.Equal(t, map[string]interface{}{"text": "[CRITICAL] Low ETH balance (polygon)\n0.01 ETH"}, recorder.payloads[0])
+}
+
+func TestAlerter_Cooldown(t *testing.T) {
+	alerter, recorder, now := newTestAlerter(t, FormatJSON, time.Hour)
+
+	assert.True(t, alerter.Fire(lowBalanceAlert()))
+	// duplicate within the cooldown
+	*now = now.Add(30 * time.Minute)
+	assert.False(t, alerter.Fire(lowBalanceAlert()))
+	// a different alert isn't affected
+	assert.True(t, alerter.Fire(Alert{Key: "head_lag", Severity: SeverityWarning}))
+	assert.Len(t, recorder.payloads, 2)
+
+	// still unresolved after the cooldown - re-sent
+	*now = now.Add(31 * time.Minute)
+	assert.True(t, alerter.Fire(lowBalanceAlert()))
+	assert.Len(t, recorder.payloads, 3)
+}
+
+func TestAlerter_Resolve(t *testing.T) {
+	alerter, recorder, _ := newTestAlerter(t, FormatJSON, time.Hour)
+
+	// not active - nothing sent
+	alerter.Resolve("low_eth_balance", "ETH balance restored", "1 ETH")
+	assert.Len(t, recorder.payloads, 0)
+
+	alerter.Fire(lowBalanceAlert())
+	alerter.Resolve("low_eth_balance", "ETH balance restored", "1 ETH")
+	assert.Len(t, recorder.payloads, 2)
+	assert.Equal(t, "resolved", recorder.payloads[1]["severity"])
+
+	// fires again straight away if the condition recurs
+	assert.True(t, alerter.Fire(lowBalanceAlert()))
+	assert.Len(t, recorder.payloads, 3)
+}
+
+func TestWebhookSink_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	err := NewWebhookSink(server.URL, FormatJSON).Send(context.Background(), lowBalanceAlert())
+	assert.Error(t, err)
+}
+
+func TestAlerter_NoSinks(t *testing.T) {
+	var alerter *Alerter
+	assert.False(t, alerter.Fire(lowBalanceAlert()))
+	alerter = NewAlerterFromConfig(nil, nil)
+	assert.True(t, alerter.Fire(lowBalanceAlert()))
+}
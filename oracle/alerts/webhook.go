@@ -0,0 +1,76 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	FormatJSON    = "json"
+	FormatSlack   = "slack"
+	FormatDiscord = "discord"
+)
+
+const webhookTimeout = 10 * time.Second
+
+// WebhookSink POSTs alerts to a URL. The payload is the Alert as JSON by default, or a
+// Slack or Discord compatible message.
+type WebhookSink struct {
+	url    string
+	format string
+	client *http.Client
+}
+
+func NewWebhookSink(url string, format string) *WebhookSink {
+	return &WebhookSink{
+		url:    url,
+		format: strings.ToLower(format),
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+func (w *WebhookSink) Send(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(w.payload(alert))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+func (w *WebhookSink) payload(alert Alert) interface{} {
+	switch w.format {
+	case FormatSlack:
+		return map[string]string{"text": formatText(alert)}
+	case FormatDiscord:
+		return map[string]string{"content": formatText(alert)}
+	default:
+		return alert
+	}
+}
+
+func formatText(alert Alert) string {
+	title := alert.Title
+	if alert.Chain != "" {
+		title = fmt.Sprintf("%s (%s)", title, alert.Chain)
+	}
+	return fmt.Sprintf("[%s] %s\n%s", strings.ToUpper(alert.Severity), title, alert.Message)
+}
@@ -33,6 +33,19 @@ func auth(keystore *keystorage.Keystorage) (err error) {
 	return
 }
 
+// unlock checks the keystore token passed with -k, or asks for it if it isn't passed or
+// is wrong
+func unlock(keystore *keystorage.Keystorage) error {
+	decryptPassword := ""
+	if options.PasswordFile != "" {
+		decryptPassword = getPasswordFromFileOrFlag(options.PasswordFile)
+	}
+	if decryptPassword != "" && keystore.CheckToken(decryptPassword) == nil {
+		return nil
+	}
+	return auth(keystore)
+}
+
 func getPasswordFromFileOrFlag(flagValue string) string {
 	file, err := os.Open(flagValue)
 	password := ""
@@ -0,0 +1,152 @@
+package chaincall
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+	"oracle/config"
+	"oracle/contracts/block_hash_store"
+	"oracle/contracts/vor_coordinator"
+	"oracle/ethrpc"
+	"sync"
+)
+
+// Connection owns the daemon's Eth client, chain ID and VOR contract bindings. One is
+// created at start up for each chain and shared by the chain's service and listener, so
+// there is a single set of RPC connections, and tests can swap in an ethrpc.SimulatedClient.
+type Connection struct {
+	Chain                 *config.Chain
+	Backend               ethrpc.Backend
+	ChainID               *big.Int
+	VORCoordinatorAddress common.Address
+	VORCoordinator        *vor_coordinator.VorCoordinator
+	BlockHashStoreAddress common.Address
+	BlockHashStore        *block_hash_store.BlockHashStore
+
+	// one per sending account, shared by every caller sending from it
+	nonceManagers   map[common.Address]*NonceManager
+	nonceManagersMu sync.Mutex
+
+	// saves Txs to the ledger before they're broadcast. Nil sends them straight to the backend
+	txManager *TxManager
+}
+
+func NewConnection(backend ethrpc.Backend, chainID *big.Int, vorCoordinatorStringAddress string, blockHashStoreStringAddress string) (*Connection, error) {
+	vorCoordinatorAddress := common.HexToAddress(vorCoordinatorStringAddress)
+	vorCoordinator, err := vor_coordinator.NewVorCoordinator(vorCoordinatorAddress, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	blockHashStoreAddress := common.HexToAddress(blockHashStoreStringAddress)
+	blockHashStore, err := block_hash_store.NewBlockHashStore(blockHashStoreAddress, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Connection{
+		Chain: &config.Chain{
+			VORCoordinatorContractAddress: vorCoordinatorStringAddress,
+			BlockHashStoreContractAddress: blockHashStoreStringAddress,
+			NetworkID:                     chainID.Int64(),
+		},
+		Backend:               backend,
+		ChainID:               chainID,
+		VORCoordinatorAddress: vorCoordinatorAddress,
+		VORCoordinator:        vorCoordinator,
+		BlockHashStoreAddress: blockHashStoreAddress,
+		BlockHashStore:        blockHashStore,
+		nonceManagers:         make(map[common.Address]*NonceManager),
+	}, nil
+}
+
+// Dial connects to the first configured chain
+func Dial(conf *config.Config) (*Connection, error) {
+	return DialChain(conf.GetChains()[0])
+}
+
+// DialChain connects to a chain's Eth providers. If the chain's network_id isn't set, the
+// chain ID is read from the provider.
+func DialChain(chain *config.Chain) (*Connection, error) {
+	client, err := ethrpc.Dial(chain.EthHTTPHost, chain.EthBroadcastHost, big.NewInt(chain.NetworkID))
+	if err != nil {
+		return nil, err
+	}
+
+	chainID := big.NewInt(chain.NetworkID)
+	if chain.NetworkID == 0 {
+		chainID, err = client.ChainID(context.Background())
+		if err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+
+	conn, err := NewConnection(client, chainID, chain.VORCoordinatorContractAddress, chain.BlockHashStoreContractAddress)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	conn.Chain = chain
+	return conn, nil
+}
+
+// Name returns the chain's configured name, or its chain ID if it isn't named
+func (c *Connection) Name() string {
+	if c.Chain != nil && c.Chain.Name != "" {
+		return c.Chain.Name
+	}
+	return c.ChainID.String()
+}
+
+// NonceManager returns the nonce manager for an account. Callers signing with the same
+// account share it, so their transactions don't use the same nonces.
+func (c *Connection) NonceManager(address common.Address) *NonceManager {
+	c.nonceManagersMu.Lock()
+	defer c.nonceManagersMu.Unlock()
+	nonceManager, ok := c.nonceManagers[address]
+	if !ok {
+		nonceManager = NewNonceManager(c.Backend, address)
+		c.nonceManagers[address] = nonceManager
+	}
+	return nonceManager
+}
+
+// SetTxManager saves each Tx sent through the connection to store before broadcasting it
+func (c *Connection) SetTxManager(store TxStore) *TxManager {
+	c.txManager = NewTxManager(c.Backend, store)
+	return c.txManager
+}
+
+// TxManager returns the connection's Tx manager, or nil if Txs aren't saved
+func (c *Connection) TxManager() *TxManager {
+	return c.txManager
+}
+
+// SendTransaction broadcasts a signed Tx, saving it to the ledger first if the connection
+// has a Tx manager
+func (c *Connection) SendTransaction(ctx context.Context, from common.Address, purpose string, tx *types.Transaction) error {
+	if c.txManager == nil {
+		return c.Backend.SendTransaction(ctx, tx)
+	}
+	return c.txManager.Send(ctx, from, purpose, tx)
+}
+
+// ReplaceTransaction broadcasts a signed Tx replacing old, e.g. with a higher gas price
+func (c *Connection) ReplaceTransaction(ctx context.Context, from common.Address, old *types.Transaction, tx *types.Transaction) error {
+	if c.txManager == nil {
+		return c.Backend.SendTransaction(ctx, tx)
+	}
+	return c.txManager.Replace(ctx, from, old, tx)
+}
+
+// Close closes the backend's connections
+func (c *Connection) Close() {
+	switch closer := c.Backend.(type) {
+	case interface{ Close() }:
+		closer.Close()
+	case interface{ Close() error }:
+		_ = closer.Close()
+	}
+}
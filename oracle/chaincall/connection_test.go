@@ -0,0 +1,81 @@
+package chaincall_test
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"oracle/chaincall"
+	"oracle/ethrpc"
+	"testing"
+)
+
+func newSimulatedConnection(t *testing.T) (*chaincall.Connection, []byte) {
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	balance := new(big.Int).Mul(big.NewInt(10), big.NewInt(1e18))
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{
+		crypto.PubkeyToAddress(privateKey.PublicKey): {Balance: balance},
+	}, 10000000)
+
+	client := ethrpc.NewSimulatedClient(backend)
+	chainID, err := client.ChainID(context.Background())
+	assert.NoError(t, err)
+
+	conn, err := chaincall.NewConnection(client, chainID,
+		"0xCfEB869F69431e42cdB54A4F4f105C19C080A601", "0x254dffcd3277C0b1660F6d42EFbB754edaBAbC2B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(conn.Close)
+	return conn, []byte(hexutil.Encode(crypto.FromECDSA(privateKey)))
+}
+
+func TestConnection_SimulatedBackend(t *testing.T) {
+	conn, privateKey := newSimulatedConnection(t)
+
+	caller, err := chaincall.NewVORCoordinatorCaller(conn, privateKey)
+	assert.NoError(t, err)
+
+	balance, err := caller.GetOracleEthBalance()
+	assert.NoError(t, err)
+	assert.Equal(t, new(big.Int).Mul(big.NewInt(10), big.NewInt(1e18)), balance)
+
+	blockNum, err := conn.Backend.BlockNumber(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), blockNum)
+
+	_, err = caller.GetBlockHash(blockNum)
+	assert.NoError(t, err)
+}
+
+func TestConnection_SharedByCallers(t *testing.T) {
+	conn, privateKey := newSimulatedConnection(t)
+
+	oracleCaller, err := chaincall.NewVORCoordinatorCaller(conn, privateKey)
+	assert.NoError(t, err)
+
+	otherKey, _ := crypto.GenerateKey()
+	otherCaller, err := chaincall.NewVORCoordinatorCaller(conn, []byte(hexutil.Encode(crypto.FromECDSA(otherKey))))
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, oracleCaller.OracleAddress(), otherCaller.OracleAddress())
+	otherBalance, err := otherCaller.GetOracleEthBalance()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, otherBalance.Sign())
+}
+
+func TestConnection_NonceManager(t *testing.T) {
+	conn, _ := newSimulatedConnection(t)
+	address := common.HexToAddress("0x04FBC34DCf60c88e701a8B3161154451e33Eef75")
+	other := common.HexToAddress("0xCfEB869F69431e42cdB54A4F4f105C19C080A601")
+
+	assert.Same(t, conn.NonceManager(address), conn.NonceManager(address))
+	assert.NotSame(t, conn.NonceManager(address), conn.NonceManager(other))
+}
@@ -0,0 +1,136 @@
+package chaincall
+
+import (
+	"errors"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+	"oracle/config"
+)
+
+const (
+	defaultGasBumpPercent = 12.5
+	// geth rejects replacement Txs which don't raise the price by at least 10%
+	minGasBumpPercent = 10
+)
+
+// ErrGasBumpCeiling is returned when a Tx can't be replaced because the bumped gas price
+// would exceed the configured ceiling.
+var ErrGasBumpCeiling = errors.New("gas bump ceiling reached")
+
+// gasBumpPercent returns the configured gas bump, no lower than the minimum a node accepts.
+func gasBumpPercent() float64 {
+	percent := config.Conf.GasBumpPercent
+	if percent == 0 {
+		return defaultGasBumpPercent
+	}
+	if percent < minGasBumpPercent {
+		return minGasBumpPercent
+	}
+	return percent
+}
+
+// gasBumpCeiling returns the most a replacement Tx can pay per gas. Falls back to the normal
+// max fee, so by default a bump can't push the price above what a new Tx would pay.
+func gasBumpCeiling() *big.Int {
+	if config.Conf.GasBumpCeiling > 0 {
+		return gweiCap(config.Conf.GasBumpCeiling)
+	}
+	return maxFeePerGas()
+}
+
+// BumpGasPrice increases a gas price by percent, rounding up.
+func BumpGasPrice(price *big.Int, percent float64) *big.Int {
+	permille := big.NewInt(1000 + int64(percent*10))
+	bumped := new(big.Int).Mul(price, permille)
+	bumped.Add(bumped, big.NewInt(999))
+	return bumped.Div(bumped, big.NewInt(1000))
+}
+
+// replacementPrice bumps the old price, using the current market price instead if that's
+// higher. The result is capped at the ceiling, but ErrGasBumpCeiling is returned if capping
+// takes it below the minimum bump, since the node wouldn't accept it.
+func replacementPrice(old *big.Int, market *big.Int, percent float64, ceiling *big.Int) (*big.Int, error) {
+	price := math.BigMax(BumpGasPrice(old, percent), market)
+	if ceiling != nil && price.Cmp(ceiling) > 0 {
+		price = new(big.Int).Set(ceiling)
+	}
+	if price.Cmp(BumpGasPrice(old, minGasBumpPercent)) < 0 {
+		return nil, ErrGasBumpCeiling
+	}
+	return price, nil
+}
+
+// ReplaceTransaction re-sends a pending Tx with the same nonce, gas limit and data at a
+// bumped gas price, so that it replaces the original in the Tx pool. The Tx type is kept.
+func (d *VORCoordinatorCaller) ReplaceTransaction(tx *types.Transaction) (*types.Transaction, error) {
+	// the replacement must come from the same account
+	from, err := d.senderOf(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	// current market pricing, in case it has moved by more than the bump
+	market := *from.transactOpts
+	err = d.setGasPrice(&market)
+	if err != nil {
+		return nil, err
+	}
+
+	percent := gasBumpPercent()
+	ceiling := gasBumpCeiling()
+
+	var replacement *types.Transaction
+	if tx.Type() == types.DynamicFeeTxType {
+		marketFeeCap, marketTipCap := market.GasFeeCap, market.GasTipCap
+		if marketFeeCap == nil {
+			// base fee has gone away - price the replacement from the old Tx only
+			marketFeeCap, marketTipCap = big.NewInt(0), big.NewInt(0)
+		}
+		feeCap, err := replacementPrice(tx.GasFeeCap(), marketFeeCap, percent, ceiling)
+		if err != nil {
+			return nil, err
+		}
+		tipCap, err := replacementPrice(tx.GasTipCap(), marketTipCap, percent, feeCap)
+		if err != nil {
+			return nil, err
+		}
+		replacement = types.NewTx(&types.DynamicFeeTx{
+			ChainID:   tx.ChainId(),
+			Nonce:     tx.Nonce(),
+			GasTipCap: tipCap,
+			GasFeeCap: feeCap,
+			Gas:       tx.Gas(),
+			To:        tx.To(),
+			Value:     tx.Value(),
+			Data:      tx.Data(),
+		})
+	} else {
+		marketPrice := market.GasPrice
+		if marketPrice == nil {
+			marketPrice = market.GasFeeCap
+		}
+		gasPrice, err := replacementPrice(tx.GasPrice(), marketPrice, percent, ceiling)
+		if err != nil {
+			return nil, err
+		}
+		replacement = types.NewTx(&types.LegacyTx{
+			Nonce:    tx.Nonce(),
+			GasPrice: gasPrice,
+			Gas:      tx.Gas(),
+			To:       tx.To(),
+			Value:    tx.Value(),
+			Data:     tx.Data(),
+		})
+	}
+
+	signedTx, err := from.transactOpts.Signer(from.transactOpts.From, replacement)
+	if err != nil {
+		return nil, err
+	}
+	err = d.conn.ReplaceTransaction(d.context, from.address(), tx, signedTx)
+	if err != nil {
+		return nil, err
+	}
+	return signedTx, nil
+}
@@ -0,0 +1,108 @@
+package chaincall
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"math/big"
+	"oracle/config"
+	"oracle/ethrpc"
+)
+
+// gweiCap converts a gwei value from the config into wei. Zero or negative means no cap.
+func gweiCap(gwei int64) *big.Int {
+	if gwei <= 0 {
+		return nil
+	}
+	return big.NewInt(0).Mul(big.NewInt(gwei), big.NewInt(params.GWei))
+}
+
+// maxFeePerGas returns the configured cap for a type-2 Tx's GasFeeCap. Falls back to
+// max_gas_price, so existing configs keep the same upper limit after switching Tx types.
+func maxFeePerGas() *big.Int {
+	if config.Conf.MaxFeePerGas > 0 {
+		return gweiCap(config.Conf.MaxFeePerGas)
+	}
+	return gweiCap(config.Conf.MaxGasPrice)
+}
+
+// DynamicFeeCaps calculates the GasFeeCap and GasTipCap for a type-2 Tx. The fee cap
+// allows for the base fee doubling before the Tx is mined, as geth does by default. Both
+// are capped by the max values, if set, and the tip never exceeds the fee cap.
+func DynamicFeeCaps(baseFee, suggestedTip, maxFeeCap, maxTipCap *big.Int) (feeCap *big.Int, tipCap *big.Int) {
+	tipCap = new(big.Int).Set(suggestedTip)
+	if maxTipCap != nil && tipCap.Cmp(maxTipCap) > 0 {
+		tipCap.Set(maxTipCap)
+	}
+
+	feeCap = new(big.Int).Add(new(big.Int).Mul(baseFee, big.NewInt(2)), tipCap)
+	if maxFeeCap != nil && feeCap.Cmp(maxFeeCap) > 0 {
+		feeCap.Set(maxFeeCap)
+	}
+
+	if tipCap.Cmp(feeCap) > 0 {
+		tipCap.Set(feeCap)
+	}
+	return feeCap, tipCap
+}
+
+// EffectiveGasPrice returns the price per gas actually paid by a mined Tx. For type-2 Txs
+// this depends on the base fee of the block it was mined in. Legacy Txs pay their GasPrice.
+func EffectiveGasPrice(tx *types.Transaction, baseFee *big.Int) *big.Int {
+	if tx.Type() != types.DynamicFeeTxType || baseFee == nil {
+		return tx.GasPrice()
+	}
+	return math.BigMin(new(big.Int).Add(tx.GasTipCap(), baseFee), tx.GasFeeCap())
+}
+
+// setGasPrice sets type-2 fee caps on the opts if the chain has a base fee, and the
+// legacy GasPrice otherwise.
+func (d *VORCoordinatorCaller) setGasPrice(opts *bind.TransactOpts) error {
+	return setGasPrice(d.context, d.client, opts)
+}
+
+func setGasPrice(ctx context.Context, client ethrpc.Backend, opts *bind.TransactOpts) error {
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if head.BaseFee != nil {
+		suggestedTip, err := client.SuggestGasTipCap(ctx)
+		if err != nil {
+			return err
+		}
+		opts.GasPrice = nil
+		opts.GasFeeCap, opts.GasTipCap = DynamicFeeCaps(head.BaseFee, suggestedTip, maxFeePerGas(), gweiCap(config.Conf.MaxPriorityFeePerGas))
+		return nil
+	}
+
+	// pre-London chain - legacy pricing
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return err
+	}
+	opts.GasFeeCap, opts.GasTipCap = nil, nil
+	opts.GasPrice = gasPrice
+
+	maxGasPrice := gweiCap(config.Conf.MaxGasPrice)
+	if maxGasPrice != nil && gasPrice.Cmp(maxGasPrice) > 0 {
+		opts.GasPrice = maxGasPrice
+	}
+	return nil
+}
+
+// GetEffectiveGasPrice returns the price per gas paid by a mined Tx, using the base fee of
+// the block in its receipt.
+func (d *VORCoordinatorCaller) GetEffectiveGasPrice(tx *types.Transaction, receipt *types.Receipt) (*big.Int, error) {
+	if tx.Type() != types.DynamicFeeTxType {
+		return tx.GasPrice(), nil
+	}
+	header, err := d.client.HeaderByHash(d.context, receipt.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+	return EffectiveGasPrice(tx, header.BaseFee), nil
+}
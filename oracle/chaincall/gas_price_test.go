@@ -0,0 +1,62 @@
+package chaincall_test
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"oracle/chaincall"
+	"testing"
+)
+
+func gwei(n int64) *big.Int {
+	return big.NewInt(0).Mul(big.NewInt(n), big.NewInt(1e9))
+}
+
+func TestDynamicFeeCaps_Uncapped(t *testing.T) {
+	feeCap, tipCap := chaincall.DynamicFeeCaps(gwei(50), gwei(2), nil, nil)
+	assert.Equal(t, gwei(102), feeCap)
+	assert.Equal(t, gwei(2), tipCap)
+}
+
+func TestDynamicFeeCaps_TipCapped(t *testing.T) {
+	feeCap, tipCap := chaincall.DynamicFeeCaps(gwei(50), gwei(10), nil, gwei(3))
+	assert.Equal(t, gwei(103), feeCap)
+	assert.Equal(t, gwei(3), tipCap)
+}
+
+func TestDynamicFeeCaps_FeeCapped(t *testing.T) {
+	feeCap, tipCap := chaincall.DynamicFeeCaps(gwei(100), gwei(2), gwei(150), nil)
+	assert.Equal(t, gwei(150), feeCap)
+	assert.Equal(t, gwei(2), tipCap)
+
+	// tip can't be more than the fee cap
+	feeCap, tipCap = chaincall.DynamicFeeCaps(gwei(1), gwei(20), gwei(10), nil)
+	assert.Equal(t, gwei(10), feeCap)
+	assert.Equal(t, gwei(10), tipCap)
+}
+
+func TestEffectiveGasPrice(t *testing.T) {
+	to := common.HexToAddress("0x04FBC34DCf60c88e701a8B3161154451e33Eef75")
+	dynamicTx := types.NewTx(&types.DynamicFeeTx{
+		To:        &to,
+		GasFeeCap: gwei(100),
+		GasTipCap: gwei(2),
+	})
+	assert.Equal(t, gwei(52), chaincall.EffectiveGasPrice(dynamicTx, gwei(50)))
+	// base fee above the fee cap's headroom - pays the cap
+	assert.Equal(t, gwei(100), chaincall.EffectiveGasPrice(dynamicTx, gwei(99)))
+
+	legacyTx := types.NewTx(&types.LegacyTx{
+		To:       &to,
+		GasPrice: gwei(30),
+	})
+	assert.Equal(t, gwei(30), chaincall.EffectiveGasPrice(legacyTx, gwei(50)))
+}
+
+func TestBumpGasPrice(t *testing.T) {
+	assert.Equal(t, big.NewInt(1125), chaincall.BumpGasPrice(big.NewInt(1000), 12.5))
+	// rounds up, so the bump is never below the percentage the node requires
+	assert.Equal(t, big.NewInt(12), chaincall.BumpGasPrice(big.NewInt(10), 12.5))
+	assert.Equal(t, gwei(110), chaincall.BumpGasPrice(gwei(100), 10))
+}
@@ -8,17 +8,17 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"log"
 	"math/big"
 	"oracle/config"
 	"oracle/contracts/mock_erc20"
+	"oracle/ethrpc"
 	"oracle/utils/walletworker"
 )
 
 type MockERC20Caller struct {
 	contractAddress  common.Address
-	client           *ethclient.Client
+	client           ethrpc.Backend
 	instance         *mock_erc20.MockErc20
 	transactOpts     *bind.TransactOpts
 	callOpts         *bind.CallOpts
@@ -28,12 +28,9 @@ type MockERC20Caller struct {
 	oracleAddress    string
 }
 
-func NewMockERC20Caller(contractStringAddress string, ethHostAddress string, chainID *big.Int, oraclePrivateKey []byte) (*MockERC20Caller, error) {
-	client, err := ethclient.Dial(ethHostAddress)
+func NewMockERC20Caller(conn *Connection, contractStringAddress string, oraclePrivateKey []byte) (*MockERC20Caller, error) {
+	client := conn.Backend
 	ctx := context.Background()
-	if err != nil {
-		return nil, err
-	}
 	contractAddress := common.HexToAddress(contractStringAddress)
 	instance, err := mock_erc20.NewMockErc20(contractAddress, client)
 	if err != nil {
@@ -55,7 +52,7 @@ func NewMockERC20Caller(contractStringAddress string, ethHostAddress string, cha
 
 	oracleAddressObj, oracleAddress := walletworker.GenerateAddress(ECDSAoraclePublicKey)
 
-	transactOpts, err := bind.NewKeyedTransactorWithChainID(oraclePrivateKeyECDSA, chainID)
+	transactOpts, err := bind.NewKeyedTransactorWithChainID(oraclePrivateKeyECDSA, conn.ChainID)
 	if err != nil {
 		return nil, err
 	}
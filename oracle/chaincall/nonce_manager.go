@@ -0,0 +1,120 @@
+package chaincall
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+// PendingNonceReader is the subset of the ethclient API used by the NonceManager
+type PendingNonceReader interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// NonceManager hands out sequential nonces for an account locally, so that concurrent
+// transactions don't each have to ask the node for the pending nonce, and don't collide
+// when they do. It re-reads the pending nonce from the chain on first use, after Reset,
+// on Sync, and when a node rejects a nonce sent with Send.
+type NonceManager struct {
+	client  PendingNonceReader
+	address common.Address
+	nonce   uint64
+	synced  bool
+	mu      sync.Mutex
+}
+
+func NewNonceManager(client PendingNonceReader, address common.Address) *NonceManager {
+	return &NonceManager{
+		client:  client,
+		address: address,
+	}
+}
+
+// Next returns the next unused nonce for the account
+func (n *NonceManager) Next(ctx context.Context) (*big.Int, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if !n.synced {
+		err := n.sync(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	nonce := n.nonce
+	n.nonce++
+	return new(big.Int).SetUint64(nonce), nil
+}
+
+// Send calls send with the account's next nonce, and holds the lock until it returns, so each
+// Tx from the account is signed and broadcast before the next gets a nonce. The nonce is only
+// used up if send succeeds, and is handed out again otherwise. If the node rejected it as
+// too low or too high, it's re-read from the chain first.
+func (n *NonceManager) Send(ctx context.Context, send func(nonce *big.Int) error) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if !n.synced {
+		err := n.sync(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := send(new(big.Int).SetUint64(n.nonce))
+	if err != nil {
+		if isNonceError(err) {
+			n.synced = false
+		}
+		return err
+	}
+	n.nonce++
+	return nil
+}
+
+// Sync resets the local nonce to the account's pending nonce on chain. It should be called
+// when no transactions are being signed - the pending nonce is then only lower than the local
+// one if a nonce was skipped (for example a failed broadcast), and using it fills the gap.
+func (n *NonceManager) Sync(ctx context.Context) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.sync(ctx)
+}
+
+// Current returns the next nonce the manager will hand out, and false if it will be re-read
+// from the chain first
+func (n *NonceManager) Current() (uint64, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.nonce, n.synced
+}
+
+// Reset forces the nonce to be re-read from the chain the next time one is requested.
+// Call it when a transaction fails to broadcast, since its nonce was never used.
+func (n *NonceManager) Reset() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.synced = false
+}
+
+func (n *NonceManager) sync(ctx context.Context) error {
+	nonce, err := n.client.PendingNonceAt(ctx, n.address)
+	if err != nil {
+		return err
+	}
+	n.nonce = nonce
+	n.synced = true
+	return nil
+}
+
+// isNonceError returns true if a node rejected a Tx because its nonce isn't the account's next
+func isNonceError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "nonce too low") || strings.Contains(msg, "nonce too high") ||
+		strings.Contains(msg, "replacement transaction underpriced") ||
+		// simulated backend
+		strings.Contains(msg, "invalid transaction nonce")
+}
@@ -0,0 +1,154 @@
+package chaincall_test
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"oracle/chaincall"
+	"sync"
+	"testing"
+)
+
+type pendingNonceStub struct {
+	nonce uint64
+	calls int
+	err   error
+}
+
+func (p *pendingNonceStub) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	p.calls++
+	return p.nonce, p.err
+}
+
+func TestNonceManager_Next(t *testing.T) {
+	stub := &pendingNonceStub{nonce: 7}
+	nonceManager := chaincall.NewNonceManager(stub, common.HexToAddress("0x04FBC34DCf60c88e701a8B3161154451e33Eef75"))
+
+	for i := uint64(7); i < 10; i++ {
+		nonce, err := nonceManager.Next(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, i, nonce.Uint64())
+	}
+	// only the first nonce is read from the chain
+	assert.Equal(t, 1, stub.calls)
+}
+
+func TestNonceManager_Concurrent(t *testing.T) {
+	stub := &pendingNonceStub{nonce: 0}
+	nonceManager := chaincall.NewNonceManager(stub, common.HexToAddress("0x04FBC34DCf60c88e701a8B3161154451e33Eef75"))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	seen := make(map[uint64]bool)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nonce, err := nonceManager.Next(context.Background())
+			assert.NoError(t, err)
+			mu.Lock()
+			seen[nonce.Uint64()] = true
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, seen, 50)
+	for i := uint64(0); i < 50; i++ {
+		assert.True(t, seen[i])
+	}
+}
+
+func TestNonceManager_ResetFillsGap(t *testing.T) {
+	stub := &pendingNonceStub{nonce: 3}
+	nonceManager := chaincall.NewNonceManager(stub, common.HexToAddress("0x04FBC34DCf60c88e701a8B3161154451e33Eef75"))
+
+	_, _ = nonceManager.Next(context.Background())
+	skipped, _ := nonceManager.Next(context.Background())
+	assert.Equal(t, uint64(4), skipped.Uint64())
+
+	// nonce 4 failed to broadcast, so the chain's pending nonce is still 4
+	stub.nonce = 4
+	nonceManager.Reset()
+
+	nonce, err := nonceManager.Next(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), nonce.Uint64())
+}
+
+func TestNonceManager_SyncError(t *testing.T) {
+	stub := &pendingNonceStub{err: errors.New("connection refused")}
+	nonceManager := chaincall.NewNonceManager(stub, common.HexToAddress("0x04FBC34DCf60c88e701a8B3161154451e33Eef75"))
+
+	_, err := nonceManager.Next(context.Background())
+	assert.Error(t, err)
+
+	stub.err = nil
+	stub.nonce = 12
+	err = nonceManager.Sync(context.Background())
+	assert.NoError(t, err)
+	nonce, err := nonceManager.Next(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(12), nonce.Uint64())
+}
+
+func TestNonceManager_Send(t *testing.T) {
+	stub := &pendingNonceStub{nonce: 5}
+	nonceManager := chaincall.NewNonceManager(stub, common.HexToAddress("0x04FBC34DCf60c88e701a8B3161154451e33Eef75"))
+
+	var sent []uint64
+	send := func(nonce *big.Int) error {
+		sent = append(sent, nonce.Uint64())
+		return nil
+	}
+	assert.NoError(t, nonceManager.Send(context.Background(), send))
+
+	// a Tx which isn't broadcast doesn't use up its nonce
+	err := nonceManager.Send(context.Background(), func(nonce *big.Int) error {
+		return errors.New("insufficient funds for gas * price + value")
+	})
+	assert.Error(t, err)
+	assert.NoError(t, nonceManager.Send(context.Background(), send))
+	assert.Equal(t, []uint64{5, 6}, sent)
+	assert.Equal(t, 1, stub.calls)
+
+	// a rejected nonce is re-read from the chain
+	stub.nonce = 9
+	err = nonceManager.Send(context.Background(), func(nonce *big.Int) error {
+		return errors.New("nonce too low")
+	})
+	assert.Error(t, err)
+	assert.NoError(t, nonceManager.Send(context.Background(), send))
+	assert.Equal(t, []uint64{5, 6, 9}, sent)
+	assert.Equal(t, 2, stub.calls)
+}
+
+func TestNonceManager_SendConcurrent(t *testing.T) {
+	stub := &pendingNonceStub{nonce: 0}
+	nonceManager := chaincall.NewNonceManager(stub, common.HexToAddress("0x04FBC34DCf60c88e701a8B3161154451e33Eef75"))
+
+	// Txs are broadcast in nonce order, even when some fail
+	var sent []uint64
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_ = nonceManager.Send(context.Background(), func(nonce *big.Int) error {
+				if i%4 == 0 {
+					return errors.New("transaction underpriced")
+				}
+				sent = append(sent, nonce.Uint64())
+				return nil
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Len(t, sent, 15)
+	for i, nonce := range sent {
+		assert.Equal(t, uint64(i), nonce)
+	}
+}
@@ -0,0 +1,136 @@
+package chaincall
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"math/big"
+	"oracle/contracts/vor_coordinator"
+	"strings"
+)
+
+var (
+	// Error(string)
+	errorSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+	// Panic(uint256)
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+)
+
+// panicReasons are the Solidity compiler's built in panic codes
+var panicReasons = map[uint64]string{
+	0x00: "generic compiler panic",
+	0x01: "assert failed",
+	0x11: "arithmetic overflow or underflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid storage byte array encoding",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to uninitialised function",
+}
+
+// DecodeRevertReason decodes the data returned by a reverted call. Error(string) and
+// Panic(uint256) are decoded, along with any custom errors in the VorCoordinator ABI.
+func DecodeRevertReason(data []byte) (string, error) {
+	if len(data) == 0 {
+		return "reverted without a reason", nil
+	}
+	if len(data) < 4 {
+		return "", fmt.Errorf("invalid revert data %s", hexutil.Encode(data))
+	}
+
+	switch {
+	case bytes.Equal(data[:4], errorSelector):
+		return abi.UnpackRevert(data)
+	case bytes.Equal(data[:4], panicSelector):
+		if len(data) < 36 {
+			return "", fmt.Errorf("invalid panic data %s", hexutil.Encode(data))
+		}
+		code := new(big.Int).SetBytes(data[4:36])
+		reason := "unknown panic"
+		if knownReason, ok := panicReasons[code.Uint64()]; ok && code.IsUint64() {
+			reason = knownReason
+		}
+		return fmt.Sprintf("panic 0x%x: %s", code, reason), nil
+	}
+
+	contractAbi, err := abi.JSON(strings.NewReader(vor_coordinator.VorCoordinatorABI))
+	if err != nil {
+		return "", err
+	}
+	for name, abiErr := range contractAbi.Errors {
+		if !bytes.Equal(data[:4], abiErr.ID[:4]) {
+			continue
+		}
+		args, err := abiErr.Unpack(data)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s%v", name, args), nil
+	}
+
+	return "", fmt.Errorf("unknown revert data %s", hexutil.Encode(data))
+}
+
+// revertData extracts the revert data from an eth_call error, if the node returned any
+func revertData(err error) ([]byte, bool) {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil, false
+	}
+	hexData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return nil, false
+	}
+	data, decodeErr := hexutil.Decode(hexData)
+	if decodeErr != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// GetRevertReason replays a failed Tx with eth_call against the state at the start of the
+// block it was mined in, and decodes why it reverted. A Tx which succeeds when replayed
+// but used all its gas ran out of gas.
+func (d *VORCoordinatorCaller) GetRevertReason(tx *types.Transaction, receipt *types.Receipt) (string, error) {
+	sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return "", err
+	}
+
+	msg := ethereum.CallMsg{
+		From:  sender,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}
+
+	var blockNum *big.Int
+	if receipt.BlockNumber != nil && receipt.BlockNumber.Sign() > 0 {
+		blockNum = new(big.Int).Sub(receipt.BlockNumber, common.Big1)
+	}
+
+	_, err = d.client.CallContract(d.context, msg, blockNum)
+	if err == nil {
+		if receipt.GasUsed >= tx.Gas() {
+			return "out of gas", nil
+		}
+		// state changed by an earlier Tx in the same block, for example a competing fulfillment
+		return "transaction reverted, but succeeds when replayed", nil
+	}
+
+	data, ok := revertData(err)
+	if !ok {
+		// no revert data - the node's error message is the best reason available
+		return err.Error(), nil
+	}
+	return DecodeRevertReason(data)
+}
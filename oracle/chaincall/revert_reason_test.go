@@ -0,0 +1,44 @@
+package chaincall_test
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"oracle/chaincall"
+	"testing"
+)
+
+func TestDecodeRevertReason_Error(t *testing.T) {
+	stringType, _ := abi.NewType("string", "", nil)
+	packed, err := abi.Arguments{{Type: stringType}}.Pack("randomness request already fulfilled")
+	assert.NoError(t, err)
+	data := append(hexutil.MustDecode("0x08c379a0"), packed...)
+
+	reason, err := chaincall.DecodeRevertReason(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "randomness request already fulfilled", reason)
+}
+
+func TestDecodeRevertReason_Panic(t *testing.T) {
+	data := append(hexutil.MustDecode("0x4e487b71"), common.LeftPadBytes([]byte{0x11}, 32)...)
+	reason, err := chaincall.DecodeRevertReason(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "panic 0x11: arithmetic overflow or underflow", reason)
+
+	data = append(hexutil.MustDecode("0x4e487b71"), common.LeftPadBytes([]byte{0x99}, 32)...)
+	reason, err = chaincall.DecodeRevertReason(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "panic 0x99: unknown panic", reason)
+}
+
+func TestDecodeRevertReason_NoData(t *testing.T) {
+	reason, err := chaincall.DecodeRevertReason(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "reverted without a reason", reason)
+}
+
+func TestDecodeRevertReason_Unknown(t *testing.T) {
+	_, err := chaincall.DecodeRevertReason(hexutil.MustDecode("0xdeadbeef"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,380 @@
+package chaincall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"math/big"
+	"oracle/models/database"
+	"sync"
+)
+
+// ErrNoSenders is returned when a pool has no accounts to send a Tx from
+var ErrNoSenders = errors.New("no sending accounts")
+
+const (
+	// StrategyRoundRobin sends from each account in turn
+	StrategyRoundRobin = "round_robin"
+	// StrategyLowestNonce sends from the account with the fewest pending Txs
+	StrategyLowestNonce = "lowest_nonce"
+)
+
+// sender is an account Txs are sent from
+type sender struct {
+	signer       Signer
+	transactOpts *bind.TransactOpts
+	nonceManager *NonceManager
+
+	// read from the chain by Refresh
+	balance        *big.Int
+	confirmedNonce uint64
+	refreshed      bool
+	// the last top up, until it's mined
+	topUp *types.Transaction
+}
+
+func newSender(ctx context.Context, conn *Connection, signer Signer) *sender {
+	return &sender{
+		signer:       signer,
+		transactOpts: newTransactOpts(ctx, signer, conn.ChainID),
+		nonceManager: conn.NonceManager(signer.Address()),
+	}
+}
+
+func (s *sender) address() common.Address {
+	return s.signer.Address()
+}
+
+// pending returns the number of the account's Txs which have been sent but not mined, as of
+// the last refresh
+func (s *sender) pending() uint64 {
+	nonce, synced := s.nonceManager.Current()
+	if !synced || !s.refreshed || nonce < s.confirmedNonce {
+		return 0
+	}
+	return nonce - s.confirmedNonce
+}
+
+// empty returns true if the account is known to have no ETH to pay for gas
+func (s *sender) empty() bool {
+	return s.refreshed && s.balance.Sign() == 0
+}
+
+// SendingAccount is the state of one of a pool's accounts, as of the last refresh
+type SendingAccount struct {
+	Address common.Address
+	// ETH balance in wei. Nil until the pool has been refreshed
+	Balance *big.Int
+	// next nonce the account will send with
+	Nonce uint64
+	// Txs sent but not yet mined
+	Pending uint64
+}
+
+// SenderPool is the set of accounts which pay for gas and send fulfillments. It's shared by
+// the callers for each proving key, so the VOR keys themselves never sign a Tx. Txs are
+// spread over the accounts round robin, or sent from the account with the fewest pending
+// Txs. Accounts which have run out of ETH are skipped while others can still pay.
+type SenderPool struct {
+	conn     *Connection
+	context  context.Context
+	senders  []*sender
+	next     int
+	strategy string
+
+	// tops up accounts whose balance falls below topUpThreshold
+	treasury       *sender
+	topUpThreshold *big.Int
+	topUpAmount    *big.Int
+
+	mu sync.Mutex
+}
+
+// NewSenderPool creates a pool sending from each of the signers' accounts
+func NewSenderPool(conn *Connection, signers ...Signer) (*SenderPool, error) {
+	pool := &SenderPool{
+		conn:     conn,
+		context:  context.Background(),
+		strategy: StrategyRoundRobin,
+	}
+	for _, signer := range signers {
+		err := pool.Add(signer)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pool, nil
+}
+
+// SetStrategy sets how the pool picks the account to send from. Empty is round robin.
+func (p *SenderPool) SetStrategy(strategy string) error {
+	switch strategy {
+	case "":
+		strategy = StrategyRoundRobin
+	case StrategyRoundRobin, StrategyLowestNonce:
+	default:
+		return fmt.Errorf("unknown sending strategy %q", strategy)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.strategy = strategy
+	return nil
+}
+
+// SetTreasury tops up the pool's accounts from the treasury's account. When an account's
+// balance falls below threshold, amount wei is sent to it.
+func (p *SenderPool) SetTreasury(treasury Signer, threshold *big.Int, amount *big.Int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.treasury = newSender(p.context, p.conn, treasury)
+	p.topUpThreshold = threshold
+	p.topUpAmount = amount
+}
+
+// Add starts sending from the signer's account. Adding an account already in the pool
+// does nothing.
+func (p *SenderPool) Add(signer Signer) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, s := range p.senders {
+		if s.address() == signer.Address() {
+			return nil
+		}
+	}
+	s := newSender(p.context, p.conn, signer)
+	err := s.nonceManager.Sync(p.context)
+	if err != nil {
+		return err
+	}
+	p.senders = append(p.senders, s)
+	return nil
+}
+
+// Len returns the number of accounts in the pool. A nil pool is empty.
+func (p *SenderPool) Len() int {
+	if p == nil {
+		return 0
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.senders)
+}
+
+// Addresses returns the pool's accounts
+func (p *SenderPool) Addresses() []common.Address {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	addresses := make([]common.Address, len(p.senders))
+	for i, s := range p.senders {
+		addresses[i] = s.address()
+	}
+	return addresses
+}
+
+// Accounts returns the balance and nonces of each of the pool's accounts
+func (p *SenderPool) Accounts() []SendingAccount {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	accounts := make([]SendingAccount, len(p.senders))
+	for i, s := range p.senders {
+		nonce, _ := s.nonceManager.Current()
+		accounts[i] = SendingAccount{
+			Address: s.address(),
+			Balance: s.balance,
+			Nonce:   nonce,
+			Pending: s.pending(),
+		}
+	}
+	return accounts
+}
+
+// Refresh reads the balance and confirmed nonce of each account in the pool
+func (p *SenderPool) Refresh(ctx context.Context) error {
+	if p == nil {
+		return nil
+	}
+	for _, s := range p.snapshot() {
+		balance, err := p.conn.Backend.BalanceAt(ctx, s.address(), nil)
+		if err != nil {
+			return err
+		}
+		nonce, err := p.conn.Backend.NonceAt(ctx, s.address(), nil)
+		if err != nil {
+			return err
+		}
+		p.mu.Lock()
+		s.balance = balance
+		s.confirmedNonce = nonce
+		s.refreshed = true
+		p.mu.Unlock()
+	}
+	return nil
+}
+
+// TopUp sends ETH from the treasury to each account whose balance was below the threshold
+// at the last refresh. An account isn't topped up again until its last top up is mined, and
+// its balance is still below the threshold after it.
+func (p *SenderPool) TopUp(ctx context.Context) ([]*types.Transaction, error) {
+	if p == nil {
+		return nil, nil
+	}
+	p.mu.Lock()
+	treasury, threshold, amount := p.treasury, p.topUpThreshold, p.topUpAmount
+	p.mu.Unlock()
+	if treasury == nil {
+		return nil, nil
+	}
+
+	var txs []*types.Transaction
+	for _, s := range p.snapshot() {
+		p.mu.Lock()
+		low := s.refreshed && s.balance.Cmp(threshold) < 0
+		topUp := s.topUp
+		p.mu.Unlock()
+		if !low {
+			continue
+		}
+		if topUp != nil {
+			_, err := p.conn.Backend.TransactionReceipt(ctx, topUp.Hash())
+			if errors.Is(err, ethereum.NotFound) {
+				continue
+			}
+			if err != nil {
+				return txs, err
+			}
+
+			// the refreshed balance may predate the top up, so re-read it
+			balance, err := p.conn.Backend.BalanceAt(ctx, s.address(), nil)
+			if err != nil {
+				return txs, err
+			}
+			p.mu.Lock()
+			s.balance = balance
+			s.topUp = nil
+			p.mu.Unlock()
+			if balance.Cmp(threshold) >= 0 {
+				continue
+			}
+		}
+
+		tx, err := p.transfer(ctx, treasury, s.address(), amount)
+		if err != nil {
+			return txs, fmt.Errorf("top up %s: %w", s.address().Hex(), err)
+		}
+		p.mu.Lock()
+		s.topUp = tx
+		p.mu.Unlock()
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+// transfer sends value wei from one of the oracle's accounts
+func (p *SenderPool) transfer(ctx context.Context, from *sender, to common.Address, value *big.Int) (*types.Transaction, error) {
+	var signed *types.Transaction
+	err := from.nonceManager.Send(ctx, func(nonce *big.Int) error {
+		opts := *from.transactOpts
+		err := setGasPrice(ctx, p.conn.Backend, &opts)
+		if err != nil {
+			return err
+		}
+
+		var tx *types.Transaction
+		if opts.GasFeeCap != nil {
+			tx = types.NewTx(&types.DynamicFeeTx{
+				ChainID:   p.conn.ChainID,
+				Nonce:     nonce.Uint64(),
+				GasTipCap: opts.GasTipCap,
+				GasFeeCap: opts.GasFeeCap,
+				Gas:       params.TxGas,
+				To:        &to,
+				Value:     value,
+			})
+		} else {
+			tx = types.NewTx(&types.LegacyTx{
+				Nonce:    nonce.Uint64(),
+				GasPrice: opts.GasPrice,
+				Gas:      params.TxGas,
+				To:       &to,
+				Value:    value,
+			})
+		}
+
+		signed, err = from.signer.SignTx(ctx, tx, p.conn.ChainID)
+		if err != nil {
+			return err
+		}
+		return p.conn.SendTransaction(ctx, from.address(), database.ETH_TX_PURPOSE_TOP_UP, signed)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// snapshot returns the pool's accounts, so they can be iterated without holding the lock
+func (p *SenderPool) snapshot() []*sender {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	senders := make([]*sender, len(p.senders))
+	copy(senders, p.senders)
+	return senders
+}
+
+// pick returns the account to send the next Tx from
+func (p *SenderPool) pick() (*sender, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.senders) == 0 {
+		return nil, ErrNoSenders
+	}
+
+	// candidates in round robin order, skipping empty accounts unless they all are
+	var candidates []int
+	for i := range p.senders {
+		index := (p.next + i) % len(p.senders)
+		if !p.senders[index].empty() {
+			candidates = append(candidates, index)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = append(candidates, p.next%len(p.senders))
+	}
+
+	chosen := candidates[0]
+	if p.strategy == StrategyLowestNonce {
+		for _, index := range candidates[1:] {
+			if p.senders[index].pending() < p.senders[chosen].pending() {
+				chosen = index
+			}
+		}
+	}
+	p.next = (chosen + 1) % len(p.senders)
+	return p.senders[chosen], nil
+}
+
+// byAddress returns the pool's account with the given address, if there is one
+func (p *SenderPool) byAddress(address common.Address) (*sender, bool) {
+	if p == nil {
+		return nil, false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, s := range p.senders {
+		if s.address() == address {
+			return s, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,115 @@
+package chaincall_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"oracle/chaincall"
+	"oracle/ethrpc"
+	"testing"
+)
+
+func newTestAccount(t *testing.T) (*ecdsa.PrivateKey, common.Address, chaincall.Signer) {
+	privateKey, _ := crypto.GenerateKey()
+	signer, err := chaincall.NewKeySigner(hexutil.Encode(crypto.FromECDSA(privateKey)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return privateKey, signer.Address(), signer
+}
+
+func ether(amount float64) *big.Int {
+	wei, _ := new(big.Float).Mul(big.NewFloat(amount), big.NewFloat(params.Ether)).Int(nil)
+	return wei
+}
+
+func TestSenderPool_LowestNonceAndTopUp(t *testing.T) {
+	_, gas1, signer1 := newTestAccount(t)
+	_, gas2, signer2 := newTestAccount(t)
+	_, gas3, signer3 := newTestAccount(t)
+	_, treasury, treasurySigner := newTestAccount(t)
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{
+		gas1:     {Balance: ether(10)},
+		gas2:     {Balance: ether(0.05)},
+		treasury: {Balance: ether(10)},
+	}, 10000000)
+	client := ethrpc.NewSimulatedClient(backend)
+	chainID, _ := client.ChainID(context.Background())
+	conn, err := chaincall.NewConnection(client, chainID,
+		"0xCfEB869F69431e42cdB54A4F4f105C19C080A601", "0x254dffcd3277C0b1660F6d42EFbB754edaBAbC2B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(conn.Close)
+
+	pool, err := chaincall.NewSenderPool(conn, signer1, signer2, signer3)
+	assert.NoError(t, err)
+	assert.Error(t, pool.SetStrategy("random"))
+	assert.NoError(t, pool.SetStrategy(chaincall.StrategyLowestNonce))
+	pool.SetTreasury(treasurySigner, ether(0.1), ether(1))
+	assert.NoError(t, pool.Refresh(context.Background()))
+
+	accounts := pool.Accounts()
+	assert.Len(t, accounts, 3)
+	assert.Equal(t, ether(10), accounts[0].Balance)
+	assert.Equal(t, ether(0.05), accounts[1].Balance)
+	assert.Equal(t, int64(0), accounts[2].Balance.Int64())
+
+	provingKey, _ := crypto.GenerateKey()
+	caller, err := chaincall.NewVORCoordinatorCallerWithSenders(conn, []byte(hexutil.Encode(crypto.FromECDSA(provingKey))), nil, pool)
+	assert.NoError(t, err)
+	senderOf := func(tx *types.Transaction) common.Address {
+		sender, err := types.Sender(types.LatestSignerForChainID(chainID), tx)
+		assert.NoError(t, err)
+		return sender
+	}
+
+	// gas3 has no ETH, so it's skipped. Otherwise the account with the fewest pending Txs sends
+	var senders []common.Address
+	for i := 0; i < 3; i++ {
+		tx, err := caller.StoreBlockHash(0)
+		assert.NoError(t, err)
+		senders = append(senders, senderOf(tx))
+	}
+	assert.Equal(t, []common.Address{gas1, gas2, gas1}, senders)
+	accounts = pool.Accounts()
+	assert.Equal(t, uint64(2), accounts[0].Pending)
+	assert.Equal(t, uint64(1), accounts[1].Pending)
+
+	// gas2 and gas3 are below the threshold
+	txs, err := pool.TopUp(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, txs, 2)
+	assert.Equal(t, gas2, *txs[0].To())
+	assert.Equal(t, gas3, *txs[1].To())
+	assert.Equal(t, treasury, senderOf(txs[0]))
+	assert.Equal(t, ether(1), txs[1].Value())
+
+	// not topped up again while the top ups are pending
+	txs, err = pool.TopUp(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, txs)
+
+	// nor once they're mined, even though the pool hasn't been refreshed since
+	backend.Commit()
+	txs, err = pool.TopUp(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, txs)
+	assert.Equal(t, ether(1), pool.Accounts()[2].Balance)
+
+	assert.NoError(t, pool.Refresh(context.Background()))
+	accounts = pool.Accounts()
+	assert.Equal(t, ether(1), accounts[2].Balance)
+	assert.Equal(t, uint64(0), accounts[0].Pending)
+	txs, err = pool.TopUp(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, txs)
+}
@@ -0,0 +1,70 @@
+package chaincall
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"math/big"
+	"oracle/config"
+	"oracle/utils"
+)
+
+// Signer signs the oracle's transactions. The VOR proving key is always held by the oracle,
+// since proofs need its secret scalar, but the account paying for gas doesn't have to be.
+type Signer interface {
+	// Address returns the account transactions are signed for
+	Address() common.Address
+	// SignTx signs a transaction for the given chain
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// KeySigner signs with a private key held in memory
+type KeySigner struct {
+	privateKey *ecdsa.PrivateKey
+	address    common.Address
+}
+
+func NewKeySigner(privateKeyHex string) (*KeySigner, error) {
+	privateKey, err := crypto.HexToECDSA(utils.RemoveHexPrefix(privateKeyHex))
+	if err != nil {
+		return nil, err
+	}
+	return &KeySigner{
+		privateKey: privateKey,
+		address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+	}, nil
+}
+
+func (s *KeySigner) Address() common.Address {
+	return s.address
+}
+
+func (s *KeySigner) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.privateKey)
+}
+
+// NewSignerFromConfig returns the configured external signer, or nil if transactions should
+// be signed with the proving key
+func NewSignerFromConfig(conf *config.Signer) (Signer, error) {
+	if conf == nil || conf.URL == "" {
+		return nil, nil
+	}
+	return NewExternalSigner(conf.URL, conf.Address, conf.Method)
+}
+
+// newTransactOpts creates transact opts which sign with the signer
+func newTransactOpts(ctx context.Context, signer Signer, chainID *big.Int) *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From: signer.Address(),
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != signer.Address() {
+				return nil, bind.ErrNotAuthorized
+			}
+			return signer.SignTx(ctx, tx, chainID)
+		},
+		Context: ctx,
+	}
+}
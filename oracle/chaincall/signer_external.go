@@ -0,0 +1,138 @@
+package chaincall
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"math/big"
+)
+
+// Clef serves account_signTransaction, and geth and most other nodes eth_signTransaction.
+// Both take the same arguments and return the same result.
+const defaultExternalSignerMethod = "eth_signTransaction"
+
+// ExternalSigner asks a remote signer, such as Clef or a node with an unlocked account, to
+// sign transactions over JSON-RPC. Signed transactions are checked against the request, so
+// the signer can't substitute a different transaction.
+type ExternalSigner struct {
+	client  *rpc.Client
+	address common.Address
+	method  string
+}
+
+// signTransactionArgs are the eth_signTransaction parameters
+type signTransactionArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	Value                hexutil.Big     `json:"value"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Data                 hexutil.Bytes   `json:"data"`
+	ChainID              *hexutil.Big    `json:"chainId,omitempty"`
+}
+
+// signTransactionResult is the eth_signTransaction result. tx is also returned, but only
+// the raw encoding is used.
+type signTransactionResult struct {
+	Raw hexutil.Bytes `json:"raw"`
+}
+
+// NewExternalSigner connects to a signer at url, which signs for address. An empty method
+// uses eth_signTransaction.
+func NewExternalSigner(url string, address string, method string) (*ExternalSigner, error) {
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("external signer address %q is not a hex address", address)
+	}
+	if method == "" {
+		method = defaultExternalSignerMethod
+	}
+	client, err := rpc.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+	return &ExternalSigner{
+		client:  client,
+		address: common.HexToAddress(address),
+		method:  method,
+	}, nil
+}
+
+func (s *ExternalSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *ExternalSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	args := signTransactionArgs{
+		From:    s.address,
+		To:      tx.To(),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   hexutil.Big(*tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Data:    tx.Data(),
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if tx.Type() == types.DynamicFeeTxType {
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	} else {
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	}
+
+	var result signTransactionResult
+	err := s.client.CallContext(ctx, &result, s.method, args)
+	if err != nil {
+		return nil, fmt.Errorf("external signer: %w", err)
+	}
+
+	signed := new(types.Transaction)
+	err = signed.UnmarshalBinary(result.Raw)
+	if err != nil {
+		return nil, fmt.Errorf("external signer returned an invalid transaction: %w", err)
+	}
+	err = s.checkSigned(tx, signed, chainID)
+	if err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// checkSigned checks the signed transaction is the one requested, signed by the signer's account
+func (s *ExternalSigner) checkSigned(tx *types.Transaction, signed *types.Transaction, chainID *big.Int) error {
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil {
+		return fmt.Errorf("external signer returned an invalid signature: %w", err)
+	}
+	if sender != s.address {
+		return fmt.Errorf("external signer signed with %s, expected %s", sender.Hex(), s.address.Hex())
+	}
+	if signed.Type() != tx.Type() ||
+		signed.Nonce() != tx.Nonce() ||
+		signed.Gas() != tx.Gas() ||
+		signed.Value().Cmp(tx.Value()) != 0 ||
+		signed.GasFeeCap().Cmp(tx.GasFeeCap()) != 0 ||
+		signed.GasTipCap().Cmp(tx.GasTipCap()) != 0 ||
+		!bytes.Equal(signed.Data(), tx.Data()) ||
+		!sameRecipient(signed.To(), tx.To()) {
+		return fmt.Errorf("external signer returned a different transaction to the one requested")
+	}
+	return nil
+}
+
+func sameRecipient(a *common.Address, b *common.Address) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// Close closes the connection to the signer
+func (s *ExternalSigner) Close() {
+	s.client.Close()
+}
@@ -0,0 +1,274 @@
+package chaincall_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"net/http/httptest"
+	"oracle/chaincall"
+	"oracle/ethrpc"
+	"testing"
+)
+
+type stubSignArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Value                hexutil.Big     `json:"value"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Data                 hexutil.Bytes   `json:"data"`
+	ChainID              *hexutil.Big    `json:"chainId"`
+}
+
+// stubSigner serves eth_signTransaction like Clef or geth, signing with its own key
+type stubSigner struct {
+	privateKey *ecdsa.PrivateKey
+	// sign a different nonce to the one requested
+	tamper bool
+}
+
+func (s *stubSigner) SignTransaction(args stubSignArgs) (map[string]interface{}, error) {
+	nonce := uint64(args.Nonce)
+	if s.tamper {
+		nonce++
+	}
+	var tx *types.Transaction
+	if args.MaxFeePerGas != nil {
+		tx = types.NewTx(&types.DynamicFeeTx{
+			ChainID:   args.ChainID.ToInt(),
+			Nonce:     nonce,
+			GasTipCap: args.MaxPriorityFeePerGas.ToInt(),
+			GasFeeCap: args.MaxFeePerGas.ToInt(),
+			Gas:       uint64(args.Gas),
+			To:        args.To,
+			Value:     args.Value.ToInt(),
+			Data:      args.Data,
+		})
+	} else {
+		tx = types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: args.GasPrice.ToInt(),
+			Gas:      uint64(args.Gas),
+			To:       args.To,
+			Value:    args.Value.ToInt(),
+			Data:     args.Data,
+		})
+	}
+	signed, err := types.SignTx(tx, types.LatestSignerForChainID(args.ChainID.ToInt()), s.privateKey)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"raw": hexutil.Bytes(raw), "tx": signed}, nil
+}
+
+func newStubSigner(t *testing.T, namespace string, signer *stubSigner) string {
+	server := rpc.NewServer()
+	err := server.RegisterName(namespace, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	t.Cleanup(server.Stop)
+	return httpServer.URL
+}
+
+func testTx() *types.Transaction {
+	to := common.HexToAddress("0xCfEB869F69431e42cdB54A4F4f105C19C080A601")
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(1337),
+		Nonce:     3,
+		GasTipCap: big.NewInt(1e9),
+		GasFeeCap: big.NewInt(2e9),
+		Gas:       100000,
+		To:        &to,
+		Value:     big.NewInt(0),
+		Data:      []byte{0x01, 0x02},
+	})
+}
+
+func TestKeySigner(t *testing.T) {
+	privateKey, _ := crypto.GenerateKey()
+	signer, err := chaincall.NewKeySigner(hexutil.Encode(crypto.FromECDSA(privateKey)))
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(privateKey.PublicKey), signer.Address())
+
+	signed, err := signer.SignTx(context.Background(), testTx(), big.NewInt(1337))
+	assert.NoError(t, err)
+	sender, err := types.Sender(types.LatestSignerForChainID(big.NewInt(1337)), signed)
+	assert.NoError(t, err)
+	assert.Equal(t, signer.Address(), sender)
+}
+
+func TestExternalSigner(t *testing.T) {
+	privateKey, _ := crypto.GenerateKey()
+	address := crypto.PubkeyToAddress(privateKey.PublicKey)
+	url := newStubSigner(t, "eth", &stubSigner{privateKey: privateKey})
+
+	signer, err := chaincall.NewExternalSigner(url, address.Hex(), "")
+	assert.NoError(t, err)
+	defer signer.Close()
+
+	tx := testTx()
+	signed, err := signer.SignTx(context.Background(), tx, big.NewInt(1337))
+	assert.NoError(t, err)
+	assert.Equal(t, tx.Nonce(), signed.Nonce())
+	assert.Equal(t, tx.Data(), signed.Data())
+	sender, err := types.Sender(types.LatestSignerForChainID(big.NewInt(1337)), signed)
+	assert.NoError(t, err)
+	assert.Equal(t, address, sender)
+}
+
+func TestExternalSigner_ClefMethod(t *testing.T) {
+	privateKey, _ := crypto.GenerateKey()
+	address := crypto.PubkeyToAddress(privateKey.PublicKey)
+	url := newStubSigner(t, "account", &stubSigner{privateKey: privateKey})
+
+	signer, err := chaincall.NewExternalSigner(url, address.Hex(), "account_signTransaction")
+	assert.NoError(t, err)
+	defer signer.Close()
+
+	_, err = signer.SignTx(context.Background(), testTx(), big.NewInt(1337))
+	assert.NoError(t, err)
+}
+
+func TestExternalSigner_RejectsWrongTx(t *testing.T) {
+	privateKey, _ := crypto.GenerateKey()
+	address := crypto.PubkeyToAddress(privateKey.PublicKey)
+
+	// signed, but not the transaction requested
+	url := newStubSigner(t, "eth", &stubSigner{privateKey: privateKey, tamper: true})
+	signer, err := chaincall.NewExternalSigner(url, address.Hex(), "")
+	assert.NoError(t, err)
+	defer signer.Close()
+	_, err = signer.SignTx(context.Background(), testTx(), big.NewInt(1337))
+	assert.Error(t, err)
+
+	// signed by a different account
+	otherKey, _ := crypto.GenerateKey()
+	url = newStubSigner(t, "eth", &stubSigner{privateKey: otherKey})
+	signer, err = chaincall.NewExternalSigner(url, address.Hex(), "")
+	assert.NoError(t, err)
+	defer signer.Close()
+	_, err = signer.SignTx(context.Background(), testTx(), big.NewInt(1337))
+	assert.Error(t, err)
+}
+
+func TestVORCoordinatorCaller_ExternalSigner(t *testing.T) {
+	gasKey, _ := crypto.GenerateKey()
+	gasAddress := crypto.PubkeyToAddress(gasKey.PublicKey)
+	balance := new(big.Int).Mul(big.NewInt(10), big.NewInt(1e18))
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{gasAddress: {Balance: balance}}, 10000000)
+	client := ethrpc.NewSimulatedClient(backend)
+	chainID, _ := client.ChainID(context.Background())
+	conn, err := chaincall.NewConnection(client, chainID,
+		"0xCfEB869F69431e42cdB54A4F4f105C19C080A601", "0x254dffcd3277C0b1660F6d42EFbB754edaBAbC2B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(conn.Close)
+
+	url := newStubSigner(t, "eth", &stubSigner{privateKey: gasKey})
+	signer, err := chaincall.NewExternalSigner(url, gasAddress.Hex(), "")
+	assert.NoError(t, err)
+	defer signer.Close()
+
+	// the proving key has no ETH. Txs are paid for by the signer's account
+	provingKey, _ := crypto.GenerateKey()
+	caller, err := chaincall.NewVORCoordinatorCallerWithSigner(conn, []byte(hexutil.Encode(crypto.FromECDSA(provingKey))), signer)
+	assert.NoError(t, err)
+	assert.Equal(t, gasAddress.Hex(), caller.OracleAddress())
+
+	tx, err := caller.StoreBlockHash(0)
+	assert.NoError(t, err)
+	backend.Commit()
+
+	receipt, err := caller.GetTxReceipt(tx.Hash().Hex())
+	assert.NoError(t, err)
+	assert.NotNil(t, receipt)
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), tx)
+	assert.NoError(t, err)
+	assert.Equal(t, gasAddress, sender)
+}
+
+func TestVORCoordinatorCaller_SendingAccounts(t *testing.T) {
+	providerKey, _ := crypto.GenerateKey()
+	provider := crypto.PubkeyToAddress(providerKey.PublicKey)
+	gasKey1, _ := crypto.GenerateKey()
+	gasKey2, _ := crypto.GenerateKey()
+	gas1 := crypto.PubkeyToAddress(gasKey1.PublicKey)
+	gas2 := crypto.PubkeyToAddress(gasKey2.PublicKey)
+	balance := new(big.Int).Mul(big.NewInt(10), big.NewInt(1e18))
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{
+		provider: {Balance: balance},
+		gas1:     {Balance: balance},
+		gas2:     {Balance: balance},
+	}, 10000000)
+	client := ethrpc.NewSimulatedClient(backend)
+	chainID, _ := client.ChainID(context.Background())
+	conn, err := chaincall.NewConnection(client, chainID,
+		"0xCfEB869F69431e42cdB54A4F4f105C19C080A601", "0x254dffcd3277C0b1660F6d42EFbB754edaBAbC2B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(conn.Close)
+
+	providerSigner, _ := chaincall.NewKeySigner(hexutil.Encode(crypto.FromECDSA(providerKey)))
+	gasSigner1, _ := chaincall.NewKeySigner(hexutil.Encode(crypto.FromECDSA(gasKey1)))
+	gasSigner2, _ := chaincall.NewKeySigner(hexutil.Encode(crypto.FromECDSA(gasKey2)))
+	pool, err := chaincall.NewSenderPool(conn, gasSigner1, gasSigner2, gasSigner1)
+	assert.NoError(t, err)
+	assert.Equal(t, []common.Address{gas1, gas2}, pool.Addresses())
+
+	// the proving key has no ETH, and never signs a Tx
+	provingKey, _ := crypto.GenerateKey()
+	caller, err := chaincall.NewVORCoordinatorCallerWithSenders(conn, []byte(hexutil.Encode(crypto.FromECDSA(provingKey))), providerSigner, pool)
+	assert.NoError(t, err)
+	assert.Equal(t, provider.Hex(), caller.OracleAddress())
+	assert.Equal(t, []string{gas1.Hex(), gas2.Hex()}, caller.SendingAddresses())
+
+	senderOf := func(tx *types.Transaction) common.Address {
+		sender, err := types.Sender(types.LatestSignerForChainID(chainID), tx)
+		assert.NoError(t, err)
+		return sender
+	}
+
+	// registration names the provider, but is paid for by a sending account
+	tx, err := caller.RegisterProvingKey(big.NewInt(1), provider)
+	assert.NoError(t, err)
+	assert.Equal(t, gas1, senderOf(tx))
+	assert.Contains(t, string(tx.Data()), string(common.LeftPadBytes(provider.Bytes(), 32)))
+
+	// fulfillments and block hashes are spread over the pool
+	tx, err = caller.FulfillRandomnessRequest([]byte{0x01}, 100000)
+	assert.NoError(t, err)
+	assert.Equal(t, gas2, senderOf(tx))
+	tx, err = caller.StoreBlockHash(0)
+	assert.NoError(t, err)
+	assert.Equal(t, gas1, senderOf(tx))
+
+	// only the provider can change the fee
+	tx, err = caller.ChangeFee(big.NewInt(2))
+	assert.NoError(t, err)
+	assert.Equal(t, provider, senderOf(tx))
+	backend.Commit()
+
+	receipt, err := caller.GetTxReceipt(tx.Hash().Hex())
+	assert.NoError(t, err)
+	assert.NotNil(t, receipt)
+}
@@ -0,0 +1,66 @@
+package chaincall
+
+import (
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"oracle/config"
+	"oracle/contracts/vor_coordinator"
+	"strings"
+)
+
+// gas added on top of the estimate, since the consumer's callback may cost a little more
+// by the time the Tx is mined
+const gasEstimateMarginPercent = 20
+
+// RevertError is returned when a simulated Tx would revert on chain
+type RevertError struct {
+	Reason string
+}
+
+func (e *RevertError) Error() string {
+	return "simulation reverted: " + e.Reason
+}
+
+// SimulateFulfillRandomnessRequest dry runs fulfillRandomnessRequest with eth_estimateGas,
+// which executes the call against the pending state. If it would revert, a *RevertError
+// with the decoded reason is returned. Otherwise, the returned gas limit is the estimate
+// plus a margin, capped at the configured gas_limit.
+func (d *VORCoordinatorCaller) SimulateFulfillRandomnessRequest(proof []byte) (uint64, error) {
+	contractAbi, err := abi.JSON(strings.NewReader(vor_coordinator.VorCoordinatorABI))
+	if err != nil {
+		return 0, err
+	}
+	input, err := contractAbi.Pack("fulfillRandomnessRequest", proof)
+	if err != nil {
+		return 0, err
+	}
+
+	estimate, err := d.client.EstimateGas(d.context, ethereum.CallMsg{
+		From: common.HexToAddress(d.SendingAddresses()[0]),
+		To:   &d.vorCoordinatorContractAddress,
+		Data: input,
+	})
+	if err != nil {
+		data, ok := revertData(err)
+		if !ok {
+			return 0, err
+		}
+		reason, decodeErr := DecodeRevertReason(data)
+		if decodeErr != nil {
+			reason = decodeErr.Error()
+		}
+		return 0, &RevertError{Reason: reason}
+	}
+
+	gasLimit := estimate * (100 + gasEstimateMarginPercent) / 100
+	maxGasLimit := uint64(config.Conf.GasLimit)
+	if maxGasLimit > 0 && gasLimit > maxGasLimit {
+		if estimate > maxGasLimit {
+			return 0, fmt.Errorf("estimated gas %d exceeds gas_limit %d", estimate, maxGasLimit)
+		}
+		gasLimit = maxGasLimit
+	}
+	return gasLimit, nil
+}
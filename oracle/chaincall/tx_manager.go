@@ -0,0 +1,250 @@
+package chaincall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"oracle/ethrpc"
+	"oracle/models/database"
+	"strings"
+)
+
+// errTxNotSent is returned by broadcast when a Tx may not have reached the node
+var errTxNotSent = errors.New("tx not sent")
+
+// TxStore is the subset of the DB API used to persist the Tx ledger
+type TxStore interface {
+	InsertEthTx(tx *database.EthTx) error
+	UpdateEthTx(tx *database.EthTx) error
+	GetEthTxByHash(txHash string) (database.EthTx, error)
+	GetPendingEthTxs() ([]database.EthTx, error)
+}
+
+// TxManager saves each of the oracle's Txs to a ledger before broadcasting it, so that a
+// crash between signing and broadcasting doesn't lose track of it. Pending Txs are
+// rebroadcast after a restart, or if a node drops them, and followed until they have
+// enough confirmations.
+type TxManager struct {
+	backend ethrpc.Backend
+	store   TxStore
+}
+
+func NewTxManager(backend ethrpc.Backend, store TxStore) *TxManager {
+	return &TxManager{
+		backend: backend,
+		store:   store,
+	}
+}
+
+// Send saves a signed Tx to the ledger, then broadcasts it. The Tx isn't broadcast if it
+// can't be saved. An error is only returned if the node rejected the Tx - if it may not have
+// reached the node, for example because the connection dropped, it's left pending for Track to
+// rebroadcast, and its nonce counts as used.
+func (m *TxManager) Send(ctx context.Context, from common.Address, purpose string, tx *types.Transaction) error {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	row := &database.EthTx{
+		FromAddress: from.Hex(),
+		Nonce:       tx.Nonce(),
+		TxHash:      tx.Hash().Hex(),
+		Raw:         raw,
+		Purpose:     purpose,
+		State:       database.ETH_TX_STATE_UNBROADCAST,
+	}
+	err = m.store.InsertEthTx(row)
+	if err != nil {
+		return err
+	}
+	err = m.broadcast(ctx, row, tx)
+	if errors.Is(err, errTxNotSent) {
+		return nil
+	}
+	return err
+}
+
+// Replace sends a Tx replacing one already in the ledger, such as a gas bump, with the
+// same purpose
+func (m *TxManager) Replace(ctx context.Context, from common.Address, old *types.Transaction, tx *types.Transaction) error {
+	purpose := ""
+	row, err := m.store.GetEthTxByHash(old.Hash().Hex())
+	if err == nil {
+		purpose = row.Purpose
+	}
+	return m.Send(ctx, from, purpose, tx)
+}
+
+// broadcast sends a ledger Tx to the node. A Tx which is rejected the first time it's sent
+// is failed, since its nonce is given to the next Tx. One which may not have reached the node
+// stays in its current state, and errTxNotSent is returned.
+func (m *TxManager) broadcast(ctx context.Context, row *database.EthTx, tx *types.Transaction) error {
+	err := m.backend.SendTransaction(ctx, tx)
+	row.Broadcasts++
+	if err != nil && !isAlreadyKnown(err) {
+		row.Error = err.Error()
+		if !isTxRejected(err) {
+			_ = m.store.UpdateEthTx(row)
+			return fmt.Errorf("%w: %s", errTxNotSent, err.Error())
+		}
+		if row.State == database.ETH_TX_STATE_UNBROADCAST {
+			row.State = database.ETH_TX_STATE_FAILED
+		}
+		_ = m.store.UpdateEthTx(row)
+		return err
+	}
+	row.State = database.ETH_TX_STATE_UNCONFIRMED
+	row.Error = ""
+	return m.store.UpdateEthTx(row)
+}
+
+// Resume rebroadcasts the Txs which were pending when the oracle stopped. Call it before
+// any new Txs are sent, so their nonces aren't reused. Returns the number rebroadcast.
+func (m *TxManager) Resume(ctx context.Context) (int, error) {
+	rows, err := m.store.GetPendingEthTxs()
+	if err != nil {
+		return 0, err
+	}
+	rebroadcast := 0
+	for i := range rows {
+		tx, err := decodeTx(rows[i].Raw)
+		if err != nil {
+			return rebroadcast, err
+		}
+		// already mined or replaced Txs are rejected, and resolved by Track
+		if m.broadcast(ctx, &rows[i], tx) == nil {
+			rebroadcast++
+		}
+	}
+	return rebroadcast, nil
+}
+
+// Track checks each unconfirmed Tx against the chain. Txs mined at least confirmations
+// blocks deep are confirmed or reverted, those whose nonce was used by another Tx are
+// replaced, and those the node has dropped are rebroadcast. Returns the Txs whose state
+// changed.
+func (m *TxManager) Track(ctx context.Context, headBlockNum uint64, confirmations uint64) ([]database.EthTx, error) {
+	rows, err := m.store.GetPendingEthTxs()
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []database.EthTx
+	for i := range rows {
+		row := &rows[i]
+		// still being sent
+		if row.State == database.ETH_TX_STATE_UNBROADCAST && row.Broadcasts == 0 {
+			continue
+		}
+		txHash := common.HexToHash(row.TxHash)
+
+		receipt, err := m.backend.TransactionReceipt(ctx, txHash)
+		if err == nil {
+			// a broadcast which timed out may still have reached the node
+			row.State = database.ETH_TX_STATE_UNCONFIRMED
+			row.BlockNumber = receipt.BlockNumber.Uint64()
+			row.GasUsed = receipt.GasUsed
+			if headBlockNum >= row.BlockNumber+confirmations {
+				row.State = database.ETH_TX_STATE_CONFIRMED
+				if receipt.Status == types.ReceiptStatusFailed {
+					row.State = database.ETH_TX_STATE_REVERTED
+				}
+				changed = append(changed, *row)
+			}
+			err = m.store.UpdateEthTx(row)
+			if err != nil {
+				return changed, err
+			}
+			continue
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return changed, err
+		}
+
+		// not mined. Either another Tx used the nonce, or it's still in, or was dropped from,
+		// the Tx pool
+		row.BlockNumber = 0
+		nonce, err := m.backend.NonceAt(ctx, common.HexToAddress(row.FromAddress), nil)
+		if err != nil {
+			return changed, err
+		}
+		if nonce > row.Nonce {
+			row.State = database.ETH_TX_STATE_REPLACED
+			changed = append(changed, *row)
+			err = m.store.UpdateEthTx(row)
+			if err != nil {
+				return changed, err
+			}
+			continue
+		}
+		_, _, err = m.backend.TransactionByHash(ctx, txHash)
+		if err == nil && row.State == database.ETH_TX_STATE_UNBROADCAST {
+			row.State = database.ETH_TX_STATE_UNCONFIRMED
+			row.Error = ""
+			err = m.store.UpdateEthTx(row)
+			if err != nil {
+				return changed, err
+			}
+			continue
+		}
+		if errors.Is(err, ethereum.NotFound) {
+			tx, err := decodeTx(row.Raw)
+			if err != nil {
+				return changed, err
+			}
+			_ = m.broadcast(ctx, row, tx)
+		}
+	}
+	return changed, nil
+}
+
+// decodeTx decodes the signed Tx saved in the ledger
+func decodeTx(raw []byte) (*types.Transaction, error) {
+	tx := new(types.Transaction)
+	err := tx.UnmarshalBinary(raw)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// txRejectedReasons are the errors a node gives for a Tx which can never be mined as signed
+var txRejectedReasons = []string{
+	"nonce too low",
+	"nonce too high",
+	"insufficient funds",
+	"underpriced",
+	"intrinsic gas too low",
+	"exceeds block gas limit",
+	"less than block base fee",
+	"higher than max fee per gas",
+	"exceeds the configured cap",
+	"oversized data",
+	"invalid sender",
+	// simulated backend
+	"invalid transaction nonce",
+}
+
+// isTxRejected returns true if a node answered that it won't accept a Tx, rather than the Tx
+// failing to reach a node
+func isTxRejected(err error) bool {
+	if !ethrpc.IsTransportError(err) {
+		return true
+	}
+	// the simulated backend's rejections aren't JSON-RPC errors
+	msg := strings.ToLower(err.Error())
+	for _, reason := range txRejectedReasons {
+		if strings.Contains(msg, reason) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAlreadyKnown returns true if a node rejected a Tx because it already has it
+func isAlreadyKnown(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "already known")
+}
@@ -0,0 +1,213 @@
+package chaincall_test
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+	"oracle/chaincall"
+	"oracle/ethrpc"
+	"oracle/models/database"
+	"sync"
+	"testing"
+)
+
+// memTxStore is an in memory Tx ledger
+type memTxStore struct {
+	txs []database.EthTx
+	mu  sync.Mutex
+}
+
+func (s *memTxStore) InsertEthTx(tx *database.EthTx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tx.ID = uint(len(s.txs) + 1)
+	s.txs = append(s.txs, *tx)
+	return nil
+}
+
+func (s *memTxStore) UpdateEthTx(tx *database.EthTx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.txs[tx.ID-1] = *tx
+	return nil
+}
+
+func (s *memTxStore) GetEthTxByHash(txHash string) (database.EthTx, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, tx := range s.txs {
+		if tx.TxHash == txHash {
+			return tx, nil
+		}
+	}
+	return database.EthTx{}, errors.New("record not found")
+}
+
+func (s *memTxStore) GetPendingEthTxs() ([]database.EthTx, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var txs []database.EthTx
+	for _, tx := range s.txs {
+		if tx.State == database.ETH_TX_STATE_UNBROADCAST || tx.State == database.ETH_TX_STATE_UNCONFIRMED {
+			txs = append(txs, tx)
+		}
+	}
+	return txs, nil
+}
+
+func (s *memTxStore) get(t *testing.T, txHash common.Hash) database.EthTx {
+	tx, err := s.GetEthTxByHash(txHash.Hex())
+	assert.NoError(t, err)
+	return tx
+}
+
+func TestTxManager_Ledger(t *testing.T) {
+	privateKey, gas, signer := newTestAccount(t)
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{gas: {Balance: ether(10)}}, 10000000)
+	client := ethrpc.NewSimulatedClient(backend)
+	chainID, _ := client.ChainID(context.Background())
+	conn, err := chaincall.NewConnection(client, chainID,
+		"0xCfEB869F69431e42cdB54A4F4f105C19C080A601", "0x254dffcd3277C0b1660F6d42EFbB754edaBAbC2B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(conn.Close)
+
+	store := &memTxStore{}
+	txManager := conn.SetTxManager(store)
+	pool, err := chaincall.NewSenderPool(conn, signer)
+	assert.NoError(t, err)
+	provingKey, _ := crypto.GenerateKey()
+	caller, err := chaincall.NewVORCoordinatorCallerWithSenders(conn, []byte(hexutil.Encode(crypto.FromECDSA(provingKey))), nil, pool)
+	assert.NoError(t, err)
+
+	// saved with its signed bytes, and broadcast
+	tx, err := caller.StoreBlockHash(0)
+	assert.NoError(t, err)
+	row := store.get(t, tx.Hash())
+	assert.Equal(t, database.ETH_TX_STATE_UNCONFIRMED, row.GetState())
+	assert.Equal(t, database.ETH_TX_PURPOSE_STORE_BLOCK_HASH, row.GetPurpose())
+	assert.Equal(t, gas.Hex(), row.GetFromAddress())
+	assert.Equal(t, uint64(0), row.GetNonce())
+	raw, _ := tx.MarshalBinary()
+	assert.Equal(t, raw, row.GetRaw())
+
+	// confirmed once it's a block deep
+	backend.Commit()
+	changed, err := txManager.Track(context.Background(), 1, 1)
+	assert.NoError(t, err)
+	assert.Empty(t, changed)
+	assert.Equal(t, uint64(1), store.get(t, tx.Hash()).GetBlockNumber())
+	backend.Commit()
+	changed, err = txManager.Track(context.Background(), 2, 1)
+	assert.NoError(t, err)
+	assert.Len(t, changed, 1)
+	assert.Equal(t, database.ETH_TX_STATE_CONFIRMED, store.get(t, tx.Hash()).GetState())
+	assert.NotZero(t, store.get(t, tx.Hash()).GetGasUsed())
+
+	// signed and saved, but the oracle stopped before broadcasting it
+	to := common.HexToAddress("0xCfEB869F69431e42cdB54A4F4f105C19C080A601")
+	unsent, _ := types.SignTx(types.NewTx(&types.LegacyTx{
+		Nonce:    1,
+		GasPrice: ether(0.000001),
+		Gas:      params.TxGas,
+		To:       &to,
+		Value:    ether(1),
+	}), types.LatestSignerForChainID(chainID), privateKey)
+	raw, _ = unsent.MarshalBinary()
+	assert.NoError(t, store.InsertEthTx(&database.EthTx{FromAddress: gas.Hex(), Nonce: 1, TxHash: unsent.Hash().Hex(),
+		Raw: raw, Purpose: database.ETH_TX_PURPOSE_TOP_UP, State: database.ETH_TX_STATE_UNBROADCAST}))
+
+	rebroadcast, err := txManager.Resume(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, rebroadcast)
+	assert.Equal(t, database.ETH_TX_STATE_UNCONFIRMED, store.get(t, unsent.Hash()).GetState())
+	backend.Commit()
+	changed, err = txManager.Track(context.Background(), 3, 0)
+	assert.NoError(t, err)
+	assert.Len(t, changed, 1)
+	assert.Equal(t, database.ETH_TX_STATE_CONFIRMED, store.get(t, unsent.Hash()).GetState())
+
+	// the nonce manager hasn't seen nonce 1 used, so the node rejects the next Tx
+	_, err = caller.StoreBlockHash(0)
+	assert.Error(t, err)
+	failed, err := store.GetPendingEthTxs()
+	assert.NoError(t, err)
+	assert.Empty(t, failed)
+	assert.Equal(t, database.ETH_TX_STATE_FAILED, store.txs[len(store.txs)-1].GetState())
+	assert.NotEmpty(t, store.txs[len(store.txs)-1].GetError())
+
+	// after the failure the nonce is re-read, and the next Tx succeeds
+	tx, err = caller.StoreBlockHash(0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), tx.Nonce())
+}
+
+// droppingBackend fails to send Txs, as if the connection to the node dropped
+type droppingBackend struct {
+	ethrpc.Backend
+	dropping bool
+}
+
+func (b *droppingBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if b.dropping {
+		return errors.New("read tcp: connection reset by peer")
+	}
+	return b.Backend.SendTransaction(ctx, tx)
+}
+
+func TestTxManager_TransportError(t *testing.T) {
+	privateKey, gas, _ := newTestAccount(t)
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{gas: {Balance: ether(10)}}, 10000000)
+	client := ethrpc.NewSimulatedClient(backend)
+	chainID, _ := client.ChainID(context.Background())
+	dropping := &droppingBackend{Backend: client, dropping: true}
+	store := &memTxStore{}
+	txManager := chaincall.NewTxManager(dropping, store)
+
+	to := common.HexToAddress("0xCfEB869F69431e42cdB54A4F4f105C19C080A601")
+	signTx := func(nonce uint64, value float64) *types.Transaction {
+		tx, _ := types.SignTx(types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: ether(0.000001),
+			Gas:      params.TxGas,
+			To:       &to,
+			Value:    ether(value),
+		}), types.LatestSignerForChainID(chainID), privateKey)
+		return tx
+	}
+
+	// the outcome is unknown, so the Tx stays pending rather than failing
+	tx := signTx(0, 1)
+	err := txManager.Send(context.Background(), gas, database.ETH_TX_PURPOSE_TOP_UP, tx)
+	assert.NoError(t, err)
+	row := store.get(t, tx.Hash())
+	assert.Equal(t, database.ETH_TX_STATE_UNBROADCAST, row.GetState())
+	assert.Equal(t, 1, int(row.Broadcasts))
+	assert.NotEmpty(t, row.GetError())
+
+	// rebroadcast once the node can be reached
+	dropping.dropping = false
+	changed, err := txManager.Track(context.Background(), 0, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, changed)
+	assert.Equal(t, database.ETH_TX_STATE_UNCONFIRMED, store.get(t, tx.Hash()).GetState())
+	backend.Commit()
+	changed, err = txManager.Track(context.Background(), 1, 0)
+	assert.NoError(t, err)
+	assert.Len(t, changed, 1)
+	assert.Equal(t, database.ETH_TX_STATE_CONFIRMED, store.get(t, tx.Hash()).GetState())
+
+	// rejected by the node, so the Tx is failed
+	rejected := signTx(0, 2)
+	err = txManager.Send(context.Background(), gas, database.ETH_TX_PURPOSE_TOP_UP, rejected)
+	assert.Error(t, err)
+	assert.Equal(t, database.ETH_TX_STATE_FAILED, store.get(t, rejected.Hash()).GetState())
+}
@@ -9,25 +9,29 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/ethereum/go-ethereum/params"
 	"log"
 	"math/big"
 	"oracle/config"
 	"oracle/contracts/block_hash_store"
 	"oracle/contracts/vor_coordinator"
+	"oracle/ethrpc"
+	"oracle/models/database"
 	"oracle/utils"
-	"oracle/utils/walletworker"
 )
 
 type VORCoordinatorCaller struct {
+	conn                          *Connection
 	vorCoordinatorContractAddress common.Address
 	blockHashStoreContractAddress common.Address
-	client                        *ethclient.Client
+	client                        ethrpc.Backend
 	vorCoordinatorInstance        *vor_coordinator.VorCoordinator
 	blockHashStoreInstance        *block_hash_store.BlockHashStore
-	transactOpts                  *bind.TransactOpts
 	callOpts                      *bind.CallOpts
+	chainID                       *big.Int
+	// the key's registered provider, which sends fee changes and withdrawals
+	provider *sender
+	// pay for gas and send fulfillments. The provider sends them if the pool is empty
+	senders *SenderPool
 
 	context          context.Context
 	publicProvingKey [2]*big.Int
@@ -36,29 +40,30 @@ type VORCoordinatorCaller struct {
 	oracleAddress    string
 }
 
-func NewVORCoordinatorCaller(vorCoordinatorStringAddress string,
-	blockHashStoreStringAddress string,
-	ethHostAddress string,
-	chainID *big.Int,
-	oraclePrivateKey []byte) (*VORCoordinatorCaller, error) {
-	client, err := ethclient.Dial(ethHostAddress)
-	ctx := context.Background()
-	if err != nil {
-		return nil, err
-	}
-
-	vorCoordinatorContractAddress := common.HexToAddress(vorCoordinatorStringAddress)
-	vorCoordinatorInstance, err := vor_coordinator.NewVorCoordinator(vorCoordinatorContractAddress, client)
+// NewVORCoordinatorCaller creates a caller which sends Txs with oraclePrivateKey, using the
+// shared connection
+func NewVORCoordinatorCaller(conn *Connection, oraclePrivateKey []byte) (*VORCoordinatorCaller, error) {
+	signer, err := NewKeySigner(string(oraclePrivateKey))
 	if err != nil {
 		return nil, err
 	}
+	return NewVORCoordinatorCallerWithSigner(conn, oraclePrivateKey, signer)
+}
 
-	blockHashContractAddress := common.HexToAddress(blockHashStoreStringAddress)
-	blockHashStoreInstance, err := block_hash_store.NewBlockHashStore(blockHashContractAddress, client)
+// NewVORCoordinatorCallerWithSigner creates a caller for the proving key oraclePrivateKey,
+// whose Txs are signed by signer. The signer's account pays for gas, and is registered as
+// the key's provider.
+func NewVORCoordinatorCallerWithSigner(conn *Connection, oraclePrivateKey []byte, signer Signer) (*VORCoordinatorCaller, error) {
+	return NewVORCoordinatorCallerWithSenders(conn, oraclePrivateKey, signer, nil)
+}
 
-	if err != nil {
-		return nil, err
-	}
+// NewVORCoordinatorCallerWithSenders creates a caller for the proving key oraclePrivateKey.
+// provider is the key's registered provider, and signs fee changes and withdrawals. If it's
+// nil, the proving key is its own provider. Fulfillments and block hashes are sent from the
+// senders pool, or by the provider if the pool is empty.
+func NewVORCoordinatorCallerWithSenders(conn *Connection, oraclePrivateKey []byte, provider Signer, senders *SenderPool) (*VORCoordinatorCaller, error) {
+	ctx := context.Background()
+	client := conn.Backend
 
 	oraclePrivateKeyECDSA, err := crypto.HexToECDSA(utils.RemoveHexPrefix(string(oraclePrivateKey)))
 	if err != nil {
@@ -74,144 +79,186 @@ func NewVORCoordinatorCaller(vorCoordinatorStringAddress string,
 		log.Print(ECDSAoraclePublicKey)
 		return nil, err
 	}
-	_, oracleAddress := walletworker.GenerateAddress(ECDSAoraclePublicKey)
 
-	transactOpts, err := bind.NewKeyedTransactorWithChainID(oraclePrivateKeyECDSA, chainID)
-	if err != nil {
-		return nil, err
+	if provider == nil {
+		provider, err = NewKeySigner(string(oraclePrivateKey))
+		if err != nil {
+			return nil, err
+		}
 	}
-
-	nonce, err := client.PendingNonceAt(ctx, common.HexToAddress(oracleAddress))
+	providerSender := newSender(ctx, conn, provider)
+	err = providerSender.nonceManager.Sync(ctx)
 	if err != nil {
 		return nil, err
 	}
-	transactOpts.Nonce = big.NewInt(int64(nonce))
-	transactOpts.Value = big.NewInt(0)
 
-	transactOpts.GasPrice = nil
-	transactOpts.GasLimit = uint64(config.Conf.GasLimit) // in units
-	transactOpts.Context = ctx
-
-	callOpts := &bind.CallOpts{From: common.HexToAddress(oracleAddress), Context: ctx}
+	callOpts := &bind.CallOpts{From: provider.Address(), Context: ctx}
 
 	return &VORCoordinatorCaller{
+		conn:                          conn,
 		client:                        client,
-		vorCoordinatorContractAddress: vorCoordinatorContractAddress,
-		vorCoordinatorInstance:        vorCoordinatorInstance,
-		blockHashStoreContractAddress: blockHashContractAddress,
-		blockHashStoreInstance:        blockHashStoreInstance,
-		transactOpts:                  transactOpts,
+		vorCoordinatorContractAddress: conn.VORCoordinatorAddress,
+		vorCoordinatorInstance:        conn.VORCoordinator,
+		blockHashStoreContractAddress: conn.BlockHashStoreAddress,
+		blockHashStoreInstance:        conn.BlockHashStore,
 		callOpts:                      callOpts,
+		chainID:                       conn.ChainID,
+		provider:                      providerSender,
+		senders:                       senders,
 		context:                       ctx,
 		publicProvingKey:              [2]*big.Int{ECDSAoraclePublicKey.X, ECDSAoraclePublicKey.Y},
 		oraclePrivateKey:              string(oraclePrivateKey),
 		oraclePublicKey:               hexutil.Encode(crypto.FromECDSAPub(oraclePublicKey.(*ecdsa.PublicKey))),
-		oracleAddress:                 oracleAddress,
+		oracleAddress:                 provider.Address().Hex(),
 	}, err
 }
 
-func (d *VORCoordinatorCaller) RenewTransactOpts() (err error) {
-	nonce, err := d.client.PendingNonceAt(d.context, common.HexToAddress(d.oracleAddress))
+// transactOpts returns a copy of an account's transact opts with the nonce and fresh gas
+// pricing
+func (d *VORCoordinatorCaller) transactOpts(from *sender, nonce *big.Int) (*bind.TransactOpts, error) {
+	opts := *from.transactOpts
+	opts.Nonce = nonce
+	opts.Value = big.NewInt(0)
+	opts.GasLimit = uint64(config.Conf.GasLimit) // in units
+
+	err := d.setGasPrice(&opts)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return &opts, nil
+}
 
-	d.transactOpts.Nonce = big.NewInt(int64(nonce))
-
-	gasPrice, err := d.client.SuggestGasPrice(d.context)
+// transact signs a transaction from an account using fresh transact opts, and sends it
+// through the connection so it's saved to the Tx ledger first. The account's nonce manager
+// is held until it's broadcast, so Txs sent concurrently from the account, such as a
+// withdrawal during a batch of fulfillments, can't get the same nonce.
+func (d *VORCoordinatorCaller) transact(from *sender, purpose string, send func(opts *bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	var tx *types.Transaction
+	err := from.nonceManager.Send(d.context, func(nonce *big.Int) error {
+		opts, err := d.transactOpts(from, nonce)
+		if err != nil {
+			return err
+		}
+		opts.NoSend = true
+		tx, err = send(opts)
+		if err != nil {
+			return err
+		}
+		return d.conn.SendTransaction(d.context, from.address(), purpose, tx)
+	})
 	if err != nil {
-		return
+		return nil, err
 	}
-	d.transactOpts.GasPrice = gasPrice
+	return tx, nil
+}
 
-	if config.Conf.MaxGasPrice > 0 {
+// sendingAccount returns the account to send the next fulfillment from
+func (d *VORCoordinatorCaller) sendingAccount() (*sender, error) {
+	if d.senders.Len() == 0 {
+		return d.provider, nil
+	}
+	return d.senders.pick()
+}
 
-		maxGasPrice := big.NewInt(0).Mul(big.NewInt(config.Conf.MaxGasPrice), big.NewInt(params.GWei))
-		if gasPrice.Cmp(maxGasPrice) > 0 {
-			d.transactOpts.GasPrice = maxGasPrice
-		}
+// senderOf returns the provider or pooled account which signed tx
+func (d *VORCoordinatorCaller) senderOf(tx *types.Transaction) (*sender, error) {
+	from, err := types.Sender(types.LatestSignerForChainID(d.chainID), tx)
+	if err != nil {
+		return nil, err
+	}
+	if from == d.provider.address() {
+		return d.provider, nil
 	}
+	if s, ok := d.senders.byAddress(from); ok {
+		return s, nil
+	}
+	return nil, fmt.Errorf("tx %s was sent by %s, which isn't one of the oracle's accounts", tx.Hash().Hex(), from.Hex())
+}
 
-	return
+// sendFromPool sends a transaction from the next sending account
+func (d *VORCoordinatorCaller) sendFromPool(purpose string, send func(opts *bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	from, err := d.sendingAccount()
+	if err != nil {
+		return nil, err
+	}
+	return d.transact(from, purpose, send)
 }
 
 func (d *VORCoordinatorCaller) HashOfKey() ([32]byte, error) {
 	return d.vorCoordinatorInstance.HashOfKey(d.callOpts, d.publicProvingKey)
 }
 
+// KeyHash returns the hash of the oracle's public proving key, computed the same way as the
+// VORCoordinator's hashOfKey, without calling the contract
+func (d *VORCoordinatorCaller) KeyHash() [32]byte {
+	return crypto.Keccak256Hash(
+		common.LeftPadBytes(d.publicProvingKey[0].Bytes(), 32),
+		common.LeftPadBytes(d.publicProvingKey[1].Bytes(), 32),
+	)
+}
+
 //func (d *VORCoordinatorCallerr) HashOfKeyLocally() ([]byte, error) {
 //	utils.Keccak256(d.publicProvingKey)
 //	crypto.Keccak256()
 //}
-//func (d *VORCoordinatorCaller) GetProviderAddress(bindOpts bind.CallOpts, keyHash string) (common.Address, error) {
-//	return d.vorCoordinatorInstance.GetProviderAddress(&bindOpts, [32]byte(keyHash))
-//}
+
+// GetProviderAddress returns the provider registered for the proving key, or the zero
+// address if it isn't registered
+func (d *VORCoordinatorCaller) GetProviderAddress(ctx context.Context) (common.Address, error) {
+	opts := *d.callOpts
+	opts.Context = ctx
+	return d.vorCoordinatorInstance.GetProviderAddress(&opts, d.KeyHash())
+}
 
 func (d *VORCoordinatorCaller) Withdraw(recipientAddress string, amount *big.Int) (*types.Transaction, error) {
-	err := d.RenewTransactOpts()
-	if err != nil {
-		return nil, err
-	}
-	defer d.RenewTransactOpts()
 	recipientAddr := common.HexToAddress(recipientAddress)
-	return d.vorCoordinatorInstance.Withdraw(d.transactOpts, recipientAddr, amount)
+	return d.transact(d.provider, database.ETH_TX_PURPOSE_WITHDRAW, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return d.vorCoordinatorInstance.Withdraw(opts, recipientAddr, amount)
+	})
 }
 
-func (d *VORCoordinatorCaller) RegisterProvingKey(fee *big.Int) (*types.Transaction, error) {
-	err := d.RenewTransactOpts()
-	if err != nil {
-		return nil, err
-	}
-	defer d.RenewTransactOpts()
-	fmt.Println(d.publicProvingKey)
-	transaction, err := d.vorCoordinatorInstance.RegisterProvingKey(d.transactOpts, fee, common.HexToAddress(d.oracleAddress), d.publicProvingKey)
-	return transaction, err
+// RegisterProvingKey registers the proving key with provider as its oracle. The provider
+// receives the key's fees, and is the only account which can change them. Anyone can send
+// the registration, so it's paid for by a sending account.
+func (d *VORCoordinatorCaller) RegisterProvingKey(fee *big.Int, provider common.Address) (*types.Transaction, error) {
+	return d.sendFromPool(database.ETH_TX_PURPOSE_REGISTER, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return d.vorCoordinatorInstance.RegisterProvingKey(opts, fee, provider, d.publicProvingKey)
+	})
 }
 
 func (d *VORCoordinatorCaller) RandomnessRequest(keyHash [32]byte, consumerSeed *big.Int, feePaid *big.Int) (*types.Transaction, error) {
-	err := d.RenewTransactOpts()
-	if err != nil {
-		return nil, err
-	}
-	defer d.RenewTransactOpts()
-	transaction, err := d.vorCoordinatorInstance.RandomnessRequest(d.transactOpts, keyHash, consumerSeed, feePaid)
-	return transaction, err
+	return d.transact(d.provider, database.ETH_TX_PURPOSE_RANDOMNESS_REQUEST, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return d.vorCoordinatorInstance.RandomnessRequest(opts, keyHash, consumerSeed, feePaid)
+	})
 }
 
 func (d *VORCoordinatorCaller) ChangeFee(fee *big.Int) (*types.Transaction, error) {
-	err := d.RenewTransactOpts()
-	if err != nil {
-		return nil, err
-	}
-	defer d.RenewTransactOpts()
-	return d.vorCoordinatorInstance.ChangeFee(d.transactOpts, d.publicProvingKey, fee)
+	return d.transact(d.provider, database.ETH_TX_PURPOSE_CHANGE_FEE, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return d.vorCoordinatorInstance.ChangeFee(opts, d.publicProvingKey, fee)
+	})
 }
 
 func (d *VORCoordinatorCaller) ChangeGranularFee(_consumer common.Address, fee *big.Int) (*types.Transaction, error) {
-	err := d.RenewTransactOpts()
-	if err != nil {
-		return nil, err
-	}
-	defer d.RenewTransactOpts()
-	return d.vorCoordinatorInstance.ChangeGranularFee(d.transactOpts, d.publicProvingKey, fee, _consumer)
+	return d.transact(d.provider, database.ETH_TX_PURPOSE_CHANGE_GRANULAR_FEE, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return d.vorCoordinatorInstance.ChangeGranularFee(opts, d.publicProvingKey, fee, _consumer)
+	})
 }
 
-func (d *VORCoordinatorCaller) FulfillRandomnessRequest(proof []byte) (*types.Transaction, error) {
-	err := d.RenewTransactOpts()
-	if err != nil {
-		return nil, err
-	}
-	defer d.RenewTransactOpts()
-	return d.vorCoordinatorInstance.FulfillRandomnessRequest(d.transactOpts, proof)
+// FulfillRandomnessRequest sends the proof from the next sending account. A gasLimit of zero
+// uses the configured gas_limit.
+func (d *VORCoordinatorCaller) FulfillRandomnessRequest(proof []byte, gasLimit uint64) (*types.Transaction, error) {
+	return d.sendFromPool(database.ETH_TX_PURPOSE_FULFILLMENT, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		if gasLimit > 0 {
+			opts.GasLimit = gasLimit
+		}
+		return d.vorCoordinatorInstance.FulfillRandomnessRequest(opts, proof)
+	})
 }
 
 func (d *VORCoordinatorCaller) StoreBlockHash(blockNum uint64) (*types.Transaction, error) {
-	err := d.RenewTransactOpts()
-	if err != nil {
-		return nil, err
-	}
-	defer d.RenewTransactOpts()
-	return d.blockHashStoreInstance.Store(d.transactOpts, big.NewInt(0).SetUint64(blockNum))
+	return d.sendFromPool(database.ETH_TX_PURPOSE_STORE_BLOCK_HASH, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return d.blockHashStoreInstance.Store(opts, big.NewInt(0).SetUint64(blockNum))
+	})
 }
 
 func (d *VORCoordinatorCaller) QueryWithdrawableTokens() (*big.Int, error) {
@@ -227,6 +274,23 @@ func (d *VORCoordinatorCaller) QueryFees(consumer string) (*big.Int, error) {
 	}
 }
 
+// OracleAddress returns the address of the key's provider, which is paid its fees
+func (d *VORCoordinatorCaller) OracleAddress() string {
+	return d.oracleAddress
+}
+
+// SendingAddresses returns the accounts fulfillments are sent from
+func (d *VORCoordinatorCaller) SendingAddresses() []string {
+	if d.senders.Len() == 0 {
+		return []string{d.oracleAddress}
+	}
+	var addresses []string
+	for _, address := range d.senders.Addresses() {
+		addresses = append(addresses, address.Hex())
+	}
+	return addresses
+}
+
 func (d *VORCoordinatorCaller) GetOracleEthBalance() (*big.Int, error) {
 	return d.client.BalanceAt(d.context, common.HexToAddress(d.oracleAddress), nil)
 }
@@ -241,6 +305,15 @@ func (d *VORCoordinatorCaller) GetTxReceipt(txHashStr string) (*types.Receipt, e
 	return d.client.TransactionReceipt(context.Background(), txHash)
 }
 
+// GetBlockHash returns the hash of a block on the canonical chain
+func (d *VORCoordinatorCaller) GetBlockHash(blockNum uint64) (common.Hash, error) {
+	header, err := d.client.HeaderByNumber(d.context, new(big.Int).SetUint64(blockNum))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return header.Hash(), nil
+}
+
 func (d *VORCoordinatorCaller) GetBlockHashFromBlockStore(blockNum uint64) (bool, common.Hash, error) {
 	hash, err := d.blockHashStoreInstance.GetBlockhash(d.callOpts, big.NewInt(0).SetUint64(blockNum))
 	h := common.BytesToHash(hash[:])
@@ -251,3 +324,24 @@ func (d *VORCoordinatorCaller) GetBlockHashFromBlockStore(blockNum uint64) (bool
 
 	return true, h, nil
 }
+
+// GetChainID returns the chain ID reported by the Eth provider
+func (d *VORCoordinatorCaller) GetChainID(ctx context.Context) (*big.Int, error) {
+	return d.client.ChainID(ctx)
+}
+
+// IsProvingKeyRegistered checks the VORCoordinator has a provider registered for the
+// oracle's proving key, and that it is the caller's provider
+func (d *VORCoordinatorCaller) IsProvingKeyRegistered(ctx context.Context) (bool, error) {
+	opts := *d.callOpts
+	opts.Context = ctx
+	keyHash, err := d.vorCoordinatorInstance.HashOfKey(&opts, d.publicProvingKey)
+	if err != nil {
+		return false, err
+	}
+	provider, err := d.vorCoordinatorInstance.GetProviderAddress(&opts, keyHash)
+	if err != nil {
+		return false, err
+	}
+	return provider == common.HexToAddress(d.oracleAddress), nil
+}
@@ -16,6 +16,7 @@ import (
 	"testing"
 )
 
+var Conn *chaincall.Connection
 var VORCoordinator *chaincall.VORCoordinatorCaller
 var VORD20Caller *chaincall.VORD20Caller
 var MockERC20Caller *chaincall.MockERC20Caller
@@ -33,22 +34,26 @@ func Init(configAddress string, pass string) (err error) {
 		return err
 	}
 	Keystore.CheckToken(pass)
+	Conn, err = chaincall.Dial(Config)
+	if err != nil {
+		return err
+	}
 	VORCoordinator, err = chaincall.NewVORCoordinatorCaller(VORCoordinatorCallerTestValues())
 	VORD20Caller, err = chaincall.NewVORD20Caller(VORD20CallerTestValues())
 	MockERC20Caller, err = chaincall.NewMockERC20Caller(MockERC20CallerTestValues())
 	return err
 }
 
-func VORCoordinatorCallerTestValues() (string, string, string, *big.Int, []byte) {
-	return Config.VORCoordinatorContractAddress, Config.BlockHashStoreContractAddress, Config.EthHTTPHost, big.NewInt(Config.NetworkID), []byte(Keystore.GetByUsername(Config.Keystorage.Account).Private)
+func VORCoordinatorCallerTestValues() (*chaincall.Connection, []byte) {
+	return Conn, []byte(Keystore.GetByUsername(Config.Keystorage.Account).Private)
 }
 
-func MockERC20CallerTestValues() (string, string, *big.Int, []byte) {
-	return Config.MockContractAddress, Config.EthHTTPHost, big.NewInt(Config.NetworkID), []byte(Keystore.GetByUsername(Config.Keystorage.Account).Private)
+func MockERC20CallerTestValues() (*chaincall.Connection, string, []byte) {
+	return Conn, Config.MockContractAddress, []byte(Keystore.GetByUsername(Config.Keystorage.Account).Private)
 }
 
-func VORD20CallerTestValues() (string, string, *big.Int, []byte) {
-	return Config.ContractCallerAddress, Config.EthHTTPHost, big.NewInt(Config.NetworkID), []byte(Keystore.GetByUsername(Config.Keystorage.Account).Private)
+func VORD20CallerTestValues() (*chaincall.Connection, string, []byte) {
+	return Conn, Config.ContractCallerAddress, []byte(Keystore.GetByUsername(Config.Keystorage.Account).Private)
 }
 
 func TestVORCoordinatorCaller_HashOfKey(t *testing.T) {
@@ -89,7 +94,7 @@ func TestVORCoordinatorCaller_ChangeFee(t *testing.T) {
 		t.Error(err)
 	}
 
-	_, _ = VORCoordinator.RegisterProvingKey(big.NewInt(1))
+	_, _ = VORCoordinator.RegisterProvingKey(big.NewInt(1), common.HexToAddress(VORCoordinator.OracleAddress()))
 
 	TransactOut, err := VORCoordinator.ChangeFee(big.NewInt(2))
 	if err != nil {
@@ -107,7 +112,7 @@ func TestVORCoordinatorCaller_RegisterProvingKey(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	TransactOut, err := VORCoordinator.RegisterProvingKey(big.NewInt(1))
+	TransactOut, err := VORCoordinator.RegisterProvingKey(big.NewInt(1), common.HexToAddress(VORCoordinator.OracleAddress()))
 	//debug.PrintStack()
 	t.Log(TransactOut)
 	if err != nil {
@@ -142,7 +147,7 @@ func TestVORCoordinatorCaller_FulfillRandomnessRequest(t *testing.T) {
 		t.Error(err)
 	}
 
-	TransactOut, err := VORCoordinator.FulfillRandomnessRequest([]byte("hfdjkhgldfjk"))
+	TransactOut, err := VORCoordinator.FulfillRandomnessRequest([]byte("hfdjkhgldfjk"), 0)
 	//debug.PrintStack()
 	t.Log(TransactOut)
 	assert.Equal(t, "VM Exception while processing transaction: revert wrong proof length", err.Error())
@@ -9,17 +9,17 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"log"
 	"math/big"
 	"oracle/config"
 	"oracle/contracts/vor_randomness_request_mock"
+	"oracle/ethrpc"
 	"oracle/utils/walletworker"
 )
 
 type VORRandomnessRequestMockCaller struct {
 	contractAddress common.Address
-	client          *ethclient.Client
+	client          ethrpc.Backend
 	instance        *vor_randomness_request_mock.VorRandomnessRequestMock
 	transactOpts    *bind.TransactOpts
 	callOpts        *bind.CallOpts
@@ -30,11 +30,8 @@ type VORRandomnessRequestMockCaller struct {
 	oracleAddress    string
 }
 
-func NewVORRandomnessRequestMockCaller(contractStringAddress string, ethHostAddress string, chainID *big.Int, oraclePrivateKey []byte) (*VORRandomnessRequestMockCaller, error) {
-	client, err := ethclient.Dial(ethHostAddress)
-	if err != nil {
-		return nil, err
-	}
+func NewVORRandomnessRequestMockCaller(conn *Connection, contractStringAddress string, oraclePrivateKey []byte) (*VORRandomnessRequestMockCaller, error) {
+	client := conn.Backend
 	fmt.Println("contractStringAddress: ", contractStringAddress)
 	contractAddress := common.HexToAddress(contractStringAddress)
 	instance, err := vor_randomness_request_mock.NewVorRandomnessRequestMock(contractAddress, client)
@@ -59,7 +56,7 @@ func NewVORRandomnessRequestMockCaller(contractStringAddress string, ethHostAddr
 	_, oracleAddress := walletworker.GenerateAddress(ECDSAoraclePublicKey)
 	log.Print("Address: ", oracleAddress)
 
-	transactOpts, err := bind.NewKeyedTransactorWithChainID(oraclePrivateKeyECDSA, chainID)
+	transactOpts, err := bind.NewKeyedTransactorWithChainID(oraclePrivateKeyECDSA, conn.ChainID)
 	if err != nil {
 		return nil, err
 	}
@@ -17,8 +17,8 @@ func InitVORRandomnessRequestMockCaller() (err error) {
 	return err
 }
 
-func VORRandomnessRequestMockCallerTestValues() (string, string, *big.Int, []byte) {
-	return Config.MockContractAddress, Config.EthHTTPHost, big.NewInt(Config.NetworkID), []byte(Keystore.GetFirst().Private)
+func VORRandomnessRequestMockCallerTestValues() (*chaincall.Connection, string, []byte) {
+	return Conn, Config.MockContractAddress, []byte(Keystore.GetFirst().Private)
 }
 
 func TestVORRandomnessRequestMockCaller_RandomnessRequest(t *testing.T) {
@@ -9,17 +9,17 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"log"
 	"math/big"
 	"oracle/config"
 	"oracle/contracts/vord_20"
+	"oracle/ethrpc"
 	"oracle/utils/walletworker"
 )
 
 type VORD20Caller struct {
 	contractAddress common.Address
-	client          *ethclient.Client
+	client          ethrpc.Backend
 	instance        *vord_20.Vord20
 	transactOpts    *bind.TransactOpts
 	callOpts        *bind.CallOpts
@@ -30,12 +30,9 @@ type VORD20Caller struct {
 	oracleAddress    string
 }
 
-func NewVORD20Caller(contractStringAddress string, ethHostAddress string, chainID *big.Int, oraclePrivateKey []byte) (*VORD20Caller, error) {
-	client, err := ethclient.Dial(ethHostAddress)
+func NewVORD20Caller(conn *Connection, contractStringAddress string, oraclePrivateKey []byte) (*VORD20Caller, error) {
+	client := conn.Backend
 	ctx := context.Background()
-	if err != nil {
-		return nil, err
-	}
 	fmt.Println("contractStringAddress: ", contractStringAddress)
 	contractAddress := common.HexToAddress(contractStringAddress)
 	instance, err := vord_20.NewVord20(contractAddress, client)
@@ -60,7 +57,7 @@ func NewVORD20Caller(contractStringAddress string, ethHostAddress string, chainI
 	oracleAddressObj, oracleAddress := walletworker.GenerateAddress(ECDSAoraclePublicKey)
 	log.Print("Address: ", oracleAddress)
 
-	transactOpts, err := bind.NewKeyedTransactorWithChainID(oraclePrivateKeyECDSA, chainID)
+	transactOpts, err := bind.NewKeyedTransactorWithChainID(oraclePrivateKeyECDSA, conn.ChainID)
 	if err != nil {
 		return nil, err
 	}
@@ -7,9 +7,53 @@ import (
 	"os"
 )
 
+// Hosts is a list of RPC endpoints. In config.json, it can be either a single URL or a list.
+type Hosts []string
+
+func (h *Hosts) UnmarshalJSON(data []byte) error {
+	var host string
+	if err := json.Unmarshal(data, &host); err == nil {
+		*h = Hosts{}
+		if host != "" {
+			*h = Hosts{host}
+		}
+		return nil
+	}
+	var hosts []string
+	if err := json.Unmarshal(data, &hosts); err != nil {
+		return err
+	}
+	*h = hosts
+	return nil
+}
+
+// Chain is a network served by the oracle, with its own contracts and Eth providers
+type Chain struct {
+	// used to select the chain in API and CLI calls. Defaults to the network ID
+	Name                          string `json:"name"`
+	VORCoordinatorContractAddress string `json:"contract_address"`
+	BlockHashStoreContractAddress string `json:"blockhash_store_address"`
+	EthHTTPHost                   Hosts  `json:"eth_http_host"`
+	EthBroadcastHost              Hosts  `json:"eth_broadcast_host"`
+	EthWSHost                     string `json:"eth_ws_host"`
+	NetworkID                     int64  `json:"network_id"`
+	FirstBlockNumber              uint64 `json:"first_block"`
+}
+
 type Keystorage struct {
 	File    string `json:"file"`
 	Account string `json:"account"`
+	// accounts whose proving keys are served. Every key in the keystore is served if empty
+	Accounts []string `json:"accounts"`
+	// sending account registered as the provider of new proving keys. It's paid their fees,
+	// and sends fee changes and withdrawals. Each key is its own provider if empty
+	ProviderAccount string `json:"provider_account"`
+	// sending accounts which pay for gas and send fulfillments. Every sending key in the
+	// keystore is used if empty
+	SendingAccounts []string `json:"sending_accounts"`
+	// derive keystore encryption keys with cheap scrypt parameters. Only for tests - keys
+	// encrypted this way are easy to brute force
+	InsecureFastScrypt bool `json:"insecure_fast_scrypt"`
 }
 
 type Serve struct {
@@ -27,16 +71,55 @@ type Database struct {
 	Dialect  string `json:"dialect"`
 }
 
+type Webhook struct {
+	URL    string `json:"url"`
+	Format string `json:"format"`
+}
+
+type Alerts struct {
+	Webhooks            []Webhook `json:"webhooks"`
+	Cooldown            int64     `json:"cooldown"`
+	MinEthBalance       float64   `json:"min_eth_balance"`
+	MaxHeadLag          int64     `json:"max_head_lag"`
+	RPCFailureThreshold int       `json:"rpc_failure_threshold"`
+}
+
+// Signer is an external signer, such as Clef, which signs the oracle's transactions instead
+// of the proving key
+type Signer struct {
+	URL     string `json:"url"`
+	Address string `json:"address"`
+	// JSON-RPC method called to sign. Defaults to eth_signTransaction
+	Method string `json:"method"`
+}
+
+// Sending configures the pool of sending accounts which pay for fulfillments
+type Sending struct {
+	// round_robin, or lowest_nonce to send from the account with the fewest pending Txs.
+	// Defaults to round_robin
+	Strategy string `json:"strategy"`
+	// sending account which tops up the others. It doesn't send fulfillments itself
+	TreasuryAccount string `json:"treasury_account"`
+	// accounts are topped up when their balance falls below this many ETH. Defaults to 0.1
+	TopUpThreshold float64 `json:"top_up_threshold"`
+	// ETH sent by each top up. Defaults to 0.5
+	TopUpAmount float64 `json:"top_up_amount"`
+}
+
 var Conf = &Config{
 	FirstBlockNumber:  1,
 	GasLimit:          500000,
 	MaxGasPrice:       150,
 	WaitConfirmations: 10,
+	GasBumpPercent:    12.5,
+	GasBumpWaitBlocks: 5,
+	MaxGasBumps:       3,
 	Serve: &Serve{
 		Host: "0.0.0.0",
 		Port: 8445,
 	},
-	CheckDuration: 15,
+	CheckDuration:    15,
+	HeartbeatTimeout: 300,
 	Keystorage: &Keystorage{
 		File: "./keystore.json",
 	},
@@ -51,18 +134,59 @@ type Config struct {
 	BlockHashStoreContractAddress string      `json:"blockhash_store_address"`
 	ContractCallerAddress         string      `json:"contract_caller_address"`
 	MockContractAddress           string      `json:"mock_contract_address"`
-	EthHTTPHost                   string      `json:"eth_http_host"`
+	EthHTTPHost                   Hosts       `json:"eth_http_host"`
+	EthBroadcastHost              Hosts       `json:"eth_broadcast_host"`
+	RPCTimeout                    int32       `json:"rpc_timeout"`
+	RPCHealthCheckInterval        int32       `json:"rpc_health_check_interval"`
+	RPCMaxHeadLag                 uint64      `json:"rpc_max_head_lag"`
 	EthWSHost                     string      `json:"eth_ws_host"`
 	NetworkID                     int64       `json:"network_id"`
 	FirstBlockNumber              uint64      `json:"first_block"`
 	CheckDuration                 int32       `json:"check_duration"`
+	HeartbeatTimeout              int32       `json:"heartbeat_timeout"`
 	Serve                         *Serve      `json:"serve"`
 	LogLevel                      string      `json:"log_level"`
 	GasLimit                      int64       `json:"gas_limit"`
 	MaxGasPrice                   int64       `json:"max_gas_price"`
+	MaxFeePerGas                  int64       `json:"max_fee_per_gas"`
+	MaxPriorityFeePerGas          int64       `json:"max_priority_fee_per_gas"`
+	GasBumpPercent                float64     `json:"gas_bump_percent"`
+	GasBumpCeiling                int64       `json:"gas_bump_ceiling"`
+	GasBumpWaitBlocks             uint64      `json:"gas_bump_wait_blocks"`
+	MaxGasBumps                   int         `json:"max_gas_bumps"`
 	WaitConfirmations             uint64      `json:"wait_confirmations"`
+	ReorgDepth                    uint64      `json:"reorg_depth"`
+	BlockRangeLimit               uint64      `json:"block_range_limit"`
+	FulfillmentWorkers            int         `json:"fulfillment_workers"`
 	Keystorage                    *Keystorage `json:"keystorage"`
 	Database                      *Database   `json:"database"`
+	Alerts                        *Alerts     `json:"alerts"`
+	Chains                        []*Chain    `json:"chains"`
+	Signer                        *Signer     `json:"signer"`
+	Sending                       *Sending    `json:"sending"`
+}
+
+// InsecureFastScrypt returns true if new keystores should use the cheap test scrypt
+// parameters
+func (c *Config) InsecureFastScrypt() bool {
+	return c.Keystorage != nil && c.Keystorage.InsecureFastScrypt
+}
+
+// GetChains returns the chains the oracle serves. If the chains list isn't set, the
+// top level contract and host settings are used as a single chain.
+func (c *Config) GetChains() []*Chain {
+	if len(c.Chains) > 0 {
+		return c.Chains
+	}
+	return []*Chain{{
+		VORCoordinatorContractAddress: c.VORCoordinatorContractAddress,
+		BlockHashStoreContractAddress: c.BlockHashStoreContractAddress,
+		EthHTTPHost:                   c.EthHTTPHost,
+		EthBroadcastHost:              c.EthBroadcastHost,
+		EthWSHost:                     c.EthWSHost,
+		NetworkID:                     c.NetworkID,
+		FirstBlockNumber:              c.FirstBlockNumber,
+	}}
 }
 
 func NewConfig(filePath string) (*Config, error) {
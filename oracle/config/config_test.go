@@ -1,6 +1,8 @@
 package config_test
 
 import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
 	"oracle/config"
 	"os"
 	"path/filepath"
@@ -16,3 +18,46 @@ func TestNewConfig(t *testing.T) {
 	}
 	t.Log(*configuration)
 }
+
+func TestHosts_UnmarshalJSON(t *testing.T) {
+	var conf config.Config
+	err := json.Unmarshal([]byte(`{"eth_http_host": "http://127.0.0.1:8545"}`), &conf)
+	assert.NoError(t, err)
+	assert.Equal(t, config.Hosts{"http://127.0.0.1:8545"}, conf.EthHTTPHost)
+
+	err = json.Unmarshal([]byte(`{"eth_http_host": ["http://127.0.0.1:8545", "https://backup:8545"]}`), &conf)
+	assert.NoError(t, err)
+	assert.Equal(t, config.Hosts{"http://127.0.0.1:8545", "https://backup:8545"}, conf.EthHTTPHost)
+
+	err = json.Unmarshal([]byte(`{"eth_http_host": 8545}`), &conf)
+	assert.Error(t, err)
+}
+
+func TestConfig_GetChains(t *testing.T) {
+	var conf config.Config
+	err := json.Unmarshal([]byte(`{
+		"contract_address": "0x6d5Fa0AA0D8F5A7F8a3f3ee81F7b7cBC4e49E6A4",
+		"eth_http_host": "http://127.0.0.1:8545",
+		"network_id": 1,
+		"first_block": 100
+	}`), &conf)
+	assert.NoError(t, err)
+	chains := conf.GetChains()
+	assert.Len(t, chains, 1)
+	assert.Equal(t, "0x6d5Fa0AA0D8F5A7F8a3f3ee81F7b7cBC4e49E6A4", chains[0].VORCoordinatorContractAddress)
+	assert.Equal(t, config.Hosts{"http://127.0.0.1:8545"}, chains[0].EthHTTPHost)
+	assert.Equal(t, int64(1), chains[0].NetworkID)
+	assert.Equal(t, uint64(100), chains[0].FirstBlockNumber)
+
+	err = json.Unmarshal([]byte(`{"chains": [
+		{"name": "mainnet", "network_id": 1, "eth_http_host": ["http://eth:8545", "http://eth-backup:8545"]},
+		{"name": "polygon", "network_id": 137, "eth_http_host": "http://polygon:8545"}
+	]}`), &conf)
+	assert.NoError(t, err)
+	chains = conf.GetChains()
+	assert.Len(t, chains, 2)
+	assert.Equal(t, "mainnet", chains[0].Name)
+	assert.Equal(t, config.Hosts{"http://eth:8545", "http://eth-backup:8545"}, chains[0].EthHTTPHost)
+	assert.Equal(t, "polygon", chains[1].Name)
+	assert.Equal(t, int64(137), chains[1].NetworkID)
+}
@@ -3,12 +3,27 @@ package api
 import (
 	"github.com/labstack/echo/v4"
 	"net/http"
+	"oracle/service"
 )
 
+// About describes the chain named by the chain param, or every chain if it isn't set
 func (d *Oracle) About(c echo.Context) error {
-	info, err := d.service.About()
-	if err != nil {
-		return c.String(http.StatusInternalServerError, info)
+	services := d.services
+	if chain := c.QueryParam("chain"); chain != "" {
+		svc, err := d.serviceFor(chain)
+		if err != nil {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+		services = []*service.Service{svc}
 	}
-	return c.String(http.StatusOK, info)
+
+	var about string
+	for _, svc := range services {
+		info, err := svc.About()
+		if err != nil {
+			return c.String(http.StatusInternalServerError, about+info)
+		}
+		about += info
+	}
+	return c.String(http.StatusOK, about)
 }
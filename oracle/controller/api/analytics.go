@@ -14,8 +14,13 @@ func (d *Oracle) Analytics(c echo.Context) error {
 	fees, _ := strconv.ParseFloat(c.QueryParam("fees"), 64)
 	simulation, _ := strconv.Atoi(c.QueryParam("sim"))
 	consumer := c.QueryParam("consumer")
+	account := c.QueryParam("account")
 
-	analytics, err := d.service.Analytics(xFundEth, xFundUsd, fees, limit, int64(gasPrice), simulation, consumer)
+	svc, err := d.serviceFor(c.QueryParam("chain"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	analytics, err := svc.Analytics(xFundEth, xFundUsd, fees, limit, int64(gasPrice), simulation, consumer, account)
 
 	if err != nil {
 		return c.JSONPretty(http.StatusInternalServerError, analytics, "  ")
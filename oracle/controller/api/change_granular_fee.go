@@ -24,7 +24,11 @@ func (d *Oracle) ChangeGranularFee(c echo.Context) error {
 
 	address := common.HexToAddress(requestModel.Consumer)
 
-	transactionInfo, err := d.service.ChangeGranularFee(address, requestModel.Amount)
+	svc, err := d.serviceFor(requestModel.Chain)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	transactionInfo, err := svc.ChangeGranularFee(requestModel.Account, address, requestModel.Amount)
 	if err != nil {
 		return c.String(http.StatusInternalServerError, err.Error())
 	}
@@ -10,7 +10,11 @@ import (
 func (d *Oracle) ChangeFee(c echo.Context) error {
 	var requestModel api.OracleChangeFeeRequestModel
 	json.NewDecoder(c.Request().Body).Decode(&requestModel)
-	transactionInfo, err := d.service.ChangeFee(requestModel.Amount)
+	svc, err := d.serviceFor(requestModel.Chain)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	transactionInfo, err := svc.ChangeFee(requestModel.Account, requestModel.Amount)
 	if err != nil {
 		return c.String(http.StatusInternalServerError, err.Error())
 	}
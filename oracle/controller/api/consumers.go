@@ -10,7 +10,12 @@ func (d *Oracle) Consumers(c echo.Context) error {
 	xFundEth, _ := strconv.ParseFloat(c.QueryParam("eth"), 64)
 	xFundUsd, _ := strconv.ParseFloat(c.QueryParam("usd"), 64)
 	consumer := c.QueryParam("consumer")
-	analyticsData, err := d.service.Consumers(xFundEth, xFundUsd, consumer)
+	account := c.QueryParam("account")
+	svc, err := d.serviceFor(c.QueryParam("chain"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	analyticsData, err := svc.Consumers(xFundEth, xFundUsd, consumer, account)
 
 	if err != nil {
 		return c.JSONPretty(http.StatusInternalServerError, analyticsData, "  ")
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+	"net/http"
+	"oracle/models/api"
+	"oracle/models/database"
+)
+
+// credentialContextKey is where ValidateKey saves the request's credential
+const credentialContextKey = "credential"
+
+// ValidateKey is the key auth middleware's validator. It accepts the keystore token and API
+// credentials, and saves the key's credential for RequireScope.
+func (d *Oracle) ValidateKey(key string, c echo.Context) (bool, error) {
+	// the keystore and credentials are shared by every chain
+	credential, ok, err := d.services[0].Authenticate(key)
+	if err != nil || !ok {
+		return false, err
+	}
+	c.Set(credentialContextKey, credential)
+	return true, nil
+}
+
+// RequireScope only lets requests through if their key has the scope
+func RequireScope(scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			credential, ok := c.Get(credentialContextKey).(database.ApiCredential)
+			if !ok || !credential.HasScope(scope) {
+				return c.String(http.StatusForbidden, fmt.Sprintf("the key doesn't have the %s scope", scope))
+			}
+			return next(c)
+		}
+	}
+}
+
+// CreateCredential adds a named API key limited to some scopes, and returns the key
+func (d *Oracle) CreateCredential(c echo.Context) error {
+	var requestModel api.OracleCreateCredentialRequestModel
+	json.NewDecoder(c.Request().Body).Decode(&requestModel)
+	key, err := d.services[0].CreateCredential(requestModel.Name, requestModel.Scopes)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, api.OracleCreateCredentialResponseModel{
+		Name:   requestModel.Name,
+		Scopes: requestModel.Scopes,
+		Key:    key,
+	})
+}
+
+// Credentials lists the API credentials. Their keys aren't stored, so aren't returned.
+func (d *Oracle) Credentials(c echo.Context) error {
+	credentials, err := d.services[0].Credentials()
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+	response := []api.ApiCredentialModel{}
+	for _, credential := range credentials {
+		response = append(response, api.ApiCredentialModel{
+			Name:      credential.GetName(),
+			Scopes:    credential.GetScopes(),
+			CreatedAt: credential.CreatedAt,
+		})
+	}
+	return c.JSONPretty(http.StatusOK, response, "  ")
+}
+
+// RevokeCredential deletes an API credential. Its key stops working straight away.
+func (d *Oracle) RevokeCredential(c echo.Context) error {
+	var requestModel api.OracleRevokeCredentialRequestModel
+	json.NewDecoder(c.Request().Body).Decode(&requestModel)
+	err := d.services[0].RevokeCredential(requestModel.Name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.String(http.StatusNotFound, fmt.Sprintf("no credential named %s", requestModel.Name))
+	}
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+	return c.String(http.StatusOK, fmt.Sprintf("revoked %s", requestModel.Name))
+}
@@ -9,7 +9,12 @@ import (
 func (d *Oracle) GetTxInfo(c echo.Context) error {
 	txHashStr := c.QueryParam("tx_hash")
 
-	tx, receipt, err := d.service.GetTxInfo(txHashStr)
+	svc, err := d.serviceFor(c.QueryParam("chain"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	tx, receipt, err := svc.GetTxInfo(txHashStr)
 
 	txInfo := api.TxInfo{
 		Tx:      tx,
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"oracle/models/api"
+	"oracle/service"
+)
+
+// Healthz is the liveness probe. It checks the DB and event listener only.
+func (d *Oracle) Healthz(c echo.Context) error {
+	return healthResponse(c, d.health(func(s *service.Service) api.HealthStatus {
+		return s.Liveness(c.Request().Context())
+	}))
+}
+
+// Readyz is the readiness probe. It additionally checks the keystore, the Eth provider and
+// chain ID, and that the proving key is registered.
+func (d *Oracle) Readyz(c echo.Context) error {
+	return healthResponse(c, d.health(func(s *service.Service) api.HealthStatus {
+		return s.Readiness(c.Request().Context())
+	}))
+}
+
+// health runs the checks for every chain. The oracle is only healthy if every chain is, and
+// with several chains, each check is named after the chain it's for.
+func (d *Oracle) health(checks func(s *service.Service) api.HealthStatus) api.HealthStatus {
+	if len(d.services) == 1 {
+		return checks(d.services[0])
+	}
+	status := api.HealthStatus{Healthy: true}
+	for _, s := range d.services {
+		chainStatus := checks(s)
+		if !chainStatus.Healthy {
+			status.Healthy = false
+		}
+		for _, check := range chainStatus.Checks {
+			check.Name = s.ChainName() + "/" + check.Name
+			status.Checks = append(status.Checks, check)
+		}
+	}
+	return status
+}
+
+func healthResponse(c echo.Context, status api.HealthStatus) error {
+	if !status.Healthy {
+		return c.JSON(http.StatusServiceUnavailable, status)
+	}
+	return c.JSON(http.StatusOK, status)
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"oracle/models/api"
+)
+
+// ImportKey adds the key in a V3 keystore file to the keystore. Sending accounts are used on
+// every chain straight away, and proving keys are served once they're registered.
+func (d *Oracle) ImportKey(c echo.Context) error {
+	var requestModel api.OracleImportKeyRequestModel
+	json.NewDecoder(c.Request().Body).Decode(&requestModel)
+	if requestModel.AccountName == "" || len(requestModel.Keystore) == 0 {
+		return c.String(http.StatusBadRequest, "account_name and keystore are required")
+	}
+
+	// the keystore is shared, so the first chain saves the key and the rest only use it
+	address, err := d.services[0].ImportKey(requestModel.AccountName, requestModel.Keystore, requestModel.Passphrase, requestModel.Sending)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	if requestModel.Sending {
+		for _, svc := range d.services[1:] {
+			err = svc.UseSending(requestModel.AccountName)
+			if err != nil {
+				return c.String(http.StatusInternalServerError, err.Error())
+			}
+		}
+	}
+	return c.JSON(http.StatusOK, api.OracleImportKeyResponseModel{
+		AccountName: requestModel.AccountName,
+		Address:     address,
+	})
+}
+
+// ExportKey returns an account's key as a V3 keystore file
+func (d *Oracle) ExportKey(c echo.Context) error {
+	var requestModel api.OracleExportKeyRequestModel
+	json.NewDecoder(c.Request().Body).Decode(&requestModel)
+	keyJSON, err := d.services[0].ExportKey(requestModel.AccountName, requestModel.Passphrase)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	return c.JSONBlob(http.StatusOK, keyJSON)
+}
@@ -2,16 +2,36 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"github.com/sirupsen/logrus"
 	"oracle/service"
+	"strconv"
 )
 
 type Oracle struct {
-	log     *logrus.Logger
-	service *service.Service
-	context context.Context
+	log *logrus.Logger
+	// one service per chain. The first is used when a request doesn't name a chain
+	services []*service.Service
+	context  context.Context
 }
 
-func NewOracle(ctx context.Context, log *logrus.Logger, service *service.Service) (*Oracle, error) {
-	return &Oracle{log: log, context: ctx, service: service}, nil
+func NewOracle(ctx context.Context, log *logrus.Logger, services []*service.Service) (*Oracle, error) {
+	if len(services) == 0 {
+		return nil, fmt.Errorf("no chains to serve")
+	}
+	return &Oracle{log: log, context: ctx, services: services}, nil
+}
+
+// serviceFor returns the service for a chain, selected by name or chain ID. An empty
+// chain selects the first configured chain.
+func (d *Oracle) serviceFor(chain string) (*service.Service, error) {
+	if chain == "" {
+		return d.services[0], nil
+	}
+	for _, s := range d.services {
+		if s.ChainName() == chain || strconv.FormatInt(s.ChainID(), 10) == chain {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("chain %s is not served by this oracle", chain)
 }
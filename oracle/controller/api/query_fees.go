@@ -10,7 +10,11 @@ import (
 func (d *Oracle) QueryFees(c echo.Context) error {
 	var requestModel api.OracleQueryFeesModel
 	json.NewDecoder(c.Request().Body).Decode(&requestModel)
-	fee, err := d.service.QueryFees(requestModel.Consumer)
+	svc, err := d.serviceFor(requestModel.Chain)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	fee, err := svc.QueryFees(requestModel.Account, requestModel.Consumer)
 	if err != nil {
 		return c.String(http.StatusInternalServerError, err.Error())
 	}
@@ -14,14 +14,20 @@ func (d *Oracle) QueryRequests(c echo.Context) error {
 	limit, _ := strconv.Atoi(c.QueryParam("limit"))
 	status, _ := strconv.Atoi(c.QueryParam("status"))
 	order := c.QueryParam("order")
+	account := c.QueryParam("account")
 
 	if order != "asc" && order != "desc" {
 		order = "asc"
 	}
 
+	svc, err := d.serviceFor(c.QueryParam("chain"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
 	requests := &api.RequestResponse{}
 
-	dbRequests, count, err := d.service.Requests(requestId, page, limit, status, order)
+	dbRequests, count, err := svc.Requests(requestId, page, limit, status, order, account)
 
 	numPages := count / int64(limit)
 	if count%int64(limit) > 0 {
@@ -35,7 +41,9 @@ func (d *Oracle) QueryRequests(c echo.Context) error {
 			ID:                 reqRow.ID,
 			CreatedAt:          reqRow.CreatedAt,
 			UpdatedAt:          reqRow.UpdatedAt,
+			ChainId:            reqRow.ChainId,
 			Sender:             reqRow.Sender,
+			KeyHash:            reqRow.KeyHash,
 			RequestId:          reqRow.RequestId,
 			RequestBlockNumber: reqRow.RequestBlockNumber,
 			RequestBlockHash:   reqRow.RequestBlockHash,
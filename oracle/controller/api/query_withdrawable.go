@@ -6,7 +6,11 @@ import (
 )
 
 func (d *Oracle) QueryWithdrawableTokens(c echo.Context) error {
-	withdrawable, err := d.service.QueryWithdrawableTokens()
+	svc, err := d.serviceFor(c.QueryParam("chain"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	withdrawable, err := svc.QueryWithdrawableTokens(c.QueryParam("account"))
 	if err != nil {
 		return c.String(http.StatusInternalServerError, err.Error())
 	}
@@ -10,7 +10,11 @@ import (
 func (d *Oracle) Register(c echo.Context) error {
 	var requestModel api.OracleRegisterRequestModel
 	json.NewDecoder(c.Request().Body).Decode(&requestModel)
-	transactionInfo, err := d.service.Register(requestModel.AccountName, requestModel.PrivateKey, requestModel.Fee)
+	svc, err := d.serviceFor(requestModel.Chain)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	transactionInfo, err := svc.Register(requestModel.AccountName, requestModel.PrivateKey, requestModel.Fee)
 	if err != nil {
 		return c.String(http.StatusInternalServerError, err.Error())
 	}
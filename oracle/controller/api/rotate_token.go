@@ -0,0 +1,20 @@
+package api
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"oracle/models/api"
+)
+
+// RotateToken replaces the API token, and re-encrypts the keystore under the new one. The
+// token this request was authenticated with stops working once it returns.
+func (d *Oracle) RotateToken(c echo.Context) error {
+	// the keystore is shared by every chain
+	token, err := d.services[0].RotateToken()
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, api.OracleRotateTokenResponseModel{
+		Token: token,
+	})
+}
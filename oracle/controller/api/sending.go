@@ -0,0 +1,34 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"oracle/models/api"
+)
+
+// AddSending adds a sending account to the keystore, and starts sending fulfillments from it
+// on every chain
+func (d *Oracle) AddSending(c echo.Context) error {
+	var requestModel api.OracleAddSendingRequestModel
+	json.NewDecoder(c.Request().Body).Decode(&requestModel)
+	if requestModel.AccountName == "" {
+		return c.String(http.StatusBadRequest, "account_name is required")
+	}
+
+	// the keystore is shared, so the first chain saves the key and the rest only use it
+	address, err := d.services[0].AddSending(requestModel.AccountName, requestModel.PrivateKey)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+	for _, svc := range d.services[1:] {
+		err = svc.UseSending(requestModel.AccountName)
+		if err != nil {
+			return c.String(http.StatusInternalServerError, err.Error())
+		}
+	}
+	return c.JSON(http.StatusOK, api.OracleAddSendingResponseModel{
+		AccountName: requestModel.AccountName,
+		Address:     address,
+	})
+}
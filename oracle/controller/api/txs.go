@@ -0,0 +1,75 @@
+package api
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"oracle/models/api"
+	"strconv"
+)
+
+// QueryTxs returns a page of the Tx ledger, optionally filtered by state, purpose and
+// sending account
+func (d *Oracle) QueryTxs(c echo.Context) error {
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	state := c.QueryParam("state")
+	purpose := c.QueryParam("purpose")
+	from := c.QueryParam("from")
+	order := c.QueryParam("order")
+
+	if order != "asc" && order != "desc" {
+		order = "desc"
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	if from != "" {
+		if !common.IsHexAddress(from) {
+			return c.String(http.StatusBadRequest, "invalid from address")
+		}
+		from = common.HexToAddress(from).Hex()
+	}
+
+	svc, err := d.serviceFor(c.QueryParam("chain"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	response := &api.EthTxResponse{Txs: []api.EthTxModel{}}
+
+	dbTxs, count, err := svc.Txs(page, limit, state, purpose, from, order)
+
+	numPages := count / int64(limit)
+	if count%int64(limit) > 0 {
+		numPages = numPages + 1
+	}
+
+	for _, tx := range dbTxs {
+		response.Txs = append(response.Txs, api.EthTxModel{
+			ID:          tx.ID,
+			CreatedAt:   tx.CreatedAt,
+			UpdatedAt:   tx.UpdatedAt,
+			ChainId:     tx.ChainId,
+			FromAddress: tx.FromAddress,
+			Nonce:       tx.Nonce,
+			TxHash:      tx.TxHash,
+			Purpose:     tx.Purpose,
+			State:       tx.State,
+			BlockNumber: tx.BlockNumber,
+			GasUsed:     tx.GasUsed,
+			Broadcasts:  tx.Broadcasts,
+			Error:       tx.Error,
+		})
+	}
+
+	response.Pages.Page = uint(page)
+	response.Pages.NumPages = uint(numPages)
+	response.Pages.NumRecords = uint(count)
+	response.Pages.Limit = uint(limit)
+
+	if err != nil {
+		return c.JSONPretty(http.StatusInternalServerError, response, "  ")
+	}
+	return c.JSONPretty(http.StatusOK, response, "  ")
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"oracle/models/api"
+	"oracle/service"
+)
+
+// VerifyProof verifies the proof sent in a fulfillment Tx, given by tx_hash. Alternatively, the
+// raw proof hex and request block hash can be passed as proof and block_hash, which are
+// verified without any chain lookups.
+func (d *Oracle) VerifyProof(c echo.Context) error {
+	txHashStr := c.QueryParam("tx_hash")
+	proofHex := c.QueryParam("proof")
+	blockHashStr := c.QueryParam("block_hash")
+
+	var verification api.ProofVerification
+	if len(txHashStr) > 0 {
+		svc, err := d.serviceFor(c.QueryParam("chain"))
+		if err != nil {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+		verification, err = svc.VerifyFulfillment(txHashStr)
+		if err != nil {
+			return c.String(http.StatusInternalServerError, err.Error())
+		}
+	} else {
+		if len(proofHex) == 0 || len(blockHashStr) == 0 {
+			return c.String(http.StatusBadRequest, "tx_hash, or proof and block_hash required")
+		}
+		proofBytes, err := hexutil.Decode(proofHex)
+		if err != nil {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+		proof, err := service.ProofFromBytes(proofBytes)
+		if err != nil {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+		verification = service.VerifyProof(proof, common.HexToHash(blockHashStr))
+	}
+
+	return c.JSONPretty(http.StatusOK, verification, "  ")
+}
@@ -10,7 +10,11 @@ import (
 func (d *Oracle) Withdraw(c echo.Context) error {
 	var requestModel api.OracleWithdrawRequestModel
 	json.NewDecoder(c.Request().Body).Decode(&requestModel)
-	transactionInfo, err := d.service.Withdraw(requestModel.Address, requestModel.Amount)
+	svc, err := d.serviceFor(requestModel.Chain)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	transactionInfo, err := svc.Withdraw(requestModel.Account, requestModel.Address, requestModel.Amount)
 	if err != nil {
 		return c.String(http.StatusInternalServerError, err.Error())
 	}
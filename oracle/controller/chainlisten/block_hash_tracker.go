@@ -0,0 +1,109 @@
+package chainlisten
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+	"sort"
+	"sync"
+)
+
+const defaultReorgDepth = 64
+
+// HeaderReader is the subset of the ethclient/SimulatedBackend API used to check
+// whether tracked blocks are still part of the canonical chain.
+type HeaderReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// BlockHashTracker remembers the hashes of recently seen blocks, so that a chain
+// reorganisation can be detected by comparing them against the canonical chain.
+type BlockHashTracker struct {
+	depth  uint64
+	hashes map[uint64]common.Hash
+	mu     sync.Mutex
+}
+
+func NewBlockHashTracker(depth uint64) *BlockHashTracker {
+	if depth == 0 {
+		depth = defaultReorgDepth
+	}
+	return &BlockHashTracker{
+		depth:  depth,
+		hashes: make(map[uint64]common.Hash),
+	}
+}
+
+// Add records the hash of a block, and forgets any blocks older than the tracked depth.
+func (t *BlockHashTracker) Add(blockNumber uint64, blockHash common.Hash) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.hashes[blockNumber] = blockHash
+	for num := range t.hashes {
+		if num+t.depth < blockNumber {
+			delete(t.hashes, num)
+		}
+	}
+}
+
+// Get returns the tracked hash for a block number, if any.
+func (t *BlockHashTracker) Get(blockNumber uint64) (common.Hash, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	hash, ok := t.hashes[blockNumber]
+	return hash, ok
+}
+
+// FindReorg walks back through the tracked blocks, newest first, until it finds one
+// that is still canonical. If the newest tracked block is not canonical, reorged is
+// true and ancestor is the most recent tracked block which survived the reorg. If no
+// tracked block survived, ancestor is the block before the oldest tracked block.
+// Blocks after the ancestor are dropped from the tracker.
+func (t *BlockHashTracker) FindReorg(ctx context.Context, reader HeaderReader) (ancestor uint64, reorged bool, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.hashes) == 0 {
+		return 0, false, nil
+	}
+
+	blockNumbers := make([]uint64, 0, len(t.hashes))
+	for num := range t.hashes {
+		blockNumbers = append(blockNumbers, num)
+	}
+	sort.Slice(blockNumbers, func(i, j int) bool { return blockNumbers[i] > blockNumbers[j] })
+
+	for i, num := range blockNumbers {
+		header, err := reader.HeaderByNumber(ctx, new(big.Int).SetUint64(num))
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return 0, false, err
+		}
+		// a missing header means the new canonical chain is shorter than the old one
+		if header != nil && header.Hash() == t.hashes[num] {
+			ancestor = num
+			reorged = i > 0
+			t.forgetAfter(ancestor)
+			return ancestor, reorged, nil
+		}
+	}
+
+	oldest := blockNumbers[len(blockNumbers)-1]
+	if oldest > 0 {
+		ancestor = oldest - 1
+	}
+	t.forgetAfter(ancestor)
+	return ancestor, true, nil
+}
+
+func (t *BlockHashTracker) forgetAfter(blockNumber uint64) {
+	for num := range t.hashes {
+		if num > blockNumber {
+			delete(t.hashes, num)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package chainlisten_test
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"oracle/controller/chainlisten"
+	"testing"
+	"time"
+)
+
+// forkedChain serves headers from the original chain up to and including forkBlock, and
+// from the competing chain after it.
+type forkedChain struct {
+	original  *backends.SimulatedBackend
+	competing *backends.SimulatedBackend
+	forkBlock uint64
+}
+
+func (f forkedChain) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if number.Uint64() <= f.forkBlock {
+		return f.original.HeaderByNumber(ctx, number)
+	}
+	return f.competing.HeaderByNumber(ctx, number)
+}
+
+func newSimulatedChains(originalBlocks int, competingBlocks int) (*backends.SimulatedBackend, *backends.SimulatedBackend) {
+	original := backends.NewSimulatedBackend(core.GenesisAlloc{}, 8000000)
+	competing := backends.NewSimulatedBackend(core.GenesisAlloc{}, 8000000)
+	// shift the competing chain's clock so that its blocks hash differently
+	_ = competing.AdjustTime(10 * time.Second)
+	for i := 0; i < originalBlocks; i++ {
+		original.Commit()
+	}
+	for i := 0; i < competingBlocks; i++ {
+		competing.Commit()
+	}
+	return original, competing
+}
+
+func trackChain(t *testing.T, tracker *chainlisten.BlockHashTracker, backend *backends.SimulatedBackend, from, to uint64) {
+	for num := from; num <= to; num++ {
+		header, err := backend.HeaderByNumber(context.Background(), new(big.Int).SetUint64(num))
+		if err != nil {
+			t.Fatal(err)
+		}
+		tracker.Add(num, header.Hash())
+	}
+}
+
+func TestBlockHashTracker_NoReorg(t *testing.T) {
+	original, _ := newSimulatedChains(5, 5)
+	tracker := chainlisten.NewBlockHashTracker(10)
+	trackChain(t, tracker, original, 1, 5)
+
+	ancestor, reorged, err := tracker.FindReorg(context.Background(), original)
+	assert.NoError(t, err)
+	assert.False(t, reorged)
+	assert.Equal(t, uint64(5), ancestor)
+}
+
+func TestBlockHashTracker_Reorg(t *testing.T) {
+	original, competing := newSimulatedChains(6, 6)
+	tracker := chainlisten.NewBlockHashTracker(10)
+	trackChain(t, tracker, original, 1, 6)
+
+	ancestor, reorged, err := tracker.FindReorg(context.Background(), forkedChain{original, competing, 3})
+	assert.NoError(t, err)
+	assert.True(t, reorged)
+	assert.Equal(t, uint64(3), ancestor)
+
+	// orphaned blocks are forgotten
+	_, tracked := tracker.Get(4)
+	assert.False(t, tracked)
+	_, tracked = tracker.Get(3)
+	assert.True(t, tracked)
+}
+
+func TestBlockHashTracker_ReorgDeeperThanTracked(t *testing.T) {
+	original, competing := newSimulatedChains(6, 6)
+	tracker := chainlisten.NewBlockHashTracker(10)
+	trackChain(t, tracker, original, 4, 6)
+
+	ancestor, reorged, err := tracker.FindReorg(context.Background(), competing)
+	assert.NoError(t, err)
+	assert.True(t, reorged)
+	assert.Equal(t, uint64(3), ancestor)
+}
+
+func TestBlockHashTracker_ShorterCanonicalChain(t *testing.T) {
+	original, competing := newSimulatedChains(6, 4)
+	tracker := chainlisten.NewBlockHashTracker(10)
+	trackChain(t, tracker, original, 1, 6)
+	// blocks 5 and 6 no longer exist on the competing chain
+	ancestor, reorged, err := tracker.FindReorg(context.Background(), forkedChain{original, competing, 2})
+	assert.NoError(t, err)
+	assert.True(t, reorged)
+	assert.Equal(t, uint64(2), ancestor)
+}
+
+func TestBlockHashTracker_Depth(t *testing.T) {
+	original, _ := newSimulatedChains(8, 8)
+	tracker := chainlisten.NewBlockHashTracker(3)
+	trackChain(t, tracker, original, 1, 8)
+
+	_, tracked := tracker.Get(4)
+	assert.False(t, tracked)
+	_, tracked = tracker.Get(5)
+	assert.True(t, tracked)
+}
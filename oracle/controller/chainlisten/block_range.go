@@ -0,0 +1,37 @@
+package chainlisten
+
+import (
+	"oracle/config"
+	"strings"
+)
+
+const defaultBlockRangeLimit = 1000
+
+// provider error messages returned when an eth_getLogs query spans too many blocks or results.
+// These need to be specific, as rate limit errors mustn't shrink the range
+var rangeTooLargeErrors = []string{
+	"query returned more than",
+	"too many results",
+	"response size exceeded",
+	"block range",
+	"range too large",
+	"query timeout exceeded",
+}
+
+// maxBlockRange returns the configured maximum number of blocks per eth_getLogs query
+func maxBlockRange() uint64 {
+	if config.Conf.BlockRangeLimit == 0 {
+		return defaultBlockRangeLimit
+	}
+	return config.Conf.BlockRangeLimit
+}
+
+func isRangeTooLargeError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, s := range rangeTooLargeErrors {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package chainlisten_test
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"oracle/chaincall"
+	"oracle/config"
+	"oracle/controller/chainlisten"
+	"oracle/ethrpc"
+	"oracle/service"
+	"oracle/store"
+	"path/filepath"
+	"testing"
+)
+
+// rangeLimitBackend fails eth_getLogs queries wider than maxRange with err
+type rangeLimitBackend struct {
+	ethrpc.Backend
+	maxRange uint64
+	err      error
+	queries  int
+}
+
+func (b *rangeLimitBackend) FilterLogs(_ context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	b.queries++
+	if q.ToBlock.Uint64()-q.FromBlock.Uint64()+1 > b.maxRange {
+		return nil, b.err
+	}
+	return nil, nil
+}
+
+func newRangeLimitListener(t *testing.T, logs *rangeLimitBackend) (*chainlisten.VORCoordinatorListener, *service.Service) {
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{}, 8000000)
+	for i := 0; i < 10; i++ {
+		backend.Commit()
+	}
+	client := ethrpc.NewSimulatedClient(backend)
+	chainID, _ := client.ChainID(context.Background())
+	logs.Backend = client
+
+	conn, err := chaincall.NewConnection(logs, chainID,
+		"0xCfEB869F69431e42cdB54A4F4f105C19C080A601", "0x254dffcd3277C0b1660F6d42EFbB754edaBAbC2B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Chain = &config.Chain{Name: t.Name(), FirstBlockNumber: 1}
+
+	config.Conf.Database.Storage = filepath.Join(t.TempDir(), "oracle.db")
+	thestore, err := store.NewStore(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, thestore.Db.Migrate())
+	oracleService := &service.Service{Store: thestore.ForChain(chainID.Int64()), Connection: conn}
+
+	listener, err := chainlisten.NewVORCoordinatorListener(conn, oracleService, Log, context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return listener, oracleService
+}
+
+func TestVORCoordinatorListener_ShrinksBlockRange(t *testing.T) {
+	config.Conf.BlockRangeLimit = 8
+	defer func() { config.Conf.BlockRangeLimit = 0 }()
+
+	logs := &rangeLimitBackend{maxRange: 2, err: errors.New("query returned more than 10000 results")}
+	listener, oracleService := newRangeLimitListener(t, logs)
+
+	assert.NoError(t, listener.ProcessIncommingEvents())
+	cursor, err := oracleService.Store.Db.GetListenerCursor(oracleService.Connection.VORCoordinatorAddress.Hex(), oracleService.ChainID())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(9), cursor.GetBlockNumber())
+}
+
+func TestVORCoordinatorListener_RateLimitKeepsBlockRange(t *testing.T) {
+	config.Conf.BlockRangeLimit = 8
+	defer func() { config.Conf.BlockRangeLimit = 0 }()
+
+	// a rate limit isn't fixed by querying fewer blocks
+	logs := &rangeLimitBackend{maxRange: 0, err: errors.New("daily request count limit exceeded")}
+	listener, _ := newRangeLimitListener(t, logs)
+
+	assert.Error(t, listener.ProcessIncommingEvents())
+	assert.Equal(t, 1, logs.queries)
+}
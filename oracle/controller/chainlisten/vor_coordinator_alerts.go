@@ -0,0 +1,128 @@
+package chainlisten
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/params"
+	"math/big"
+	"oracle/alerts"
+	"oracle/config"
+	"oracle/metrics"
+	"sync/atomic"
+)
+
+const (
+	defaultMinEthBalance       = 0.1
+	defaultMaxHeadLag          = 100
+	defaultRPCFailureThreshold = 5
+)
+
+const (
+	alertKeyLowBalance   = "low_eth_balance"
+	alertKeyHeadLag      = "listener_lag"
+	alertKeyRPCFailures  = "rpc_failures"
+	alertKeyFulfilFailed = "fulfilment_failed"
+)
+
+// alertThresholds returns the configured alert thresholds, with defaults for those not set.
+// A negative threshold disables the alert.
+func alertThresholds() (minEthBalance float64, maxHeadLag int64, rpcFailureThreshold int64) {
+	minEthBalance = defaultMinEthBalance
+	maxHeadLag = defaultMaxHeadLag
+	rpcFailureThreshold = defaultRPCFailureThreshold
+	conf := config.Conf.Alerts
+	if conf == nil {
+		return
+	}
+	if conf.MinEthBalance != 0 {
+		minEthBalance = conf.MinEthBalance
+	}
+	if conf.MaxHeadLag != 0 {
+		maxHeadLag = conf.MaxHeadLag
+	}
+	if conf.RPCFailureThreshold != 0 {
+		rpcFailureThreshold = int64(conf.RPCFailureThreshold)
+	}
+	return
+}
+
+// checkBalanceAlert alerts on a low balance for each of the oracle's accounts separately.
+// name identifies the account in the alert key.
+func (d *VORCoordinatorListener) checkBalanceAlert(name string, address string, balance *big.Int) {
+	minEthBalance, _, _ := alertThresholds()
+	if minEthBalance < 0 {
+		return
+	}
+	alertKey := alertKeyLowBalance + ":" + name
+	ethBalance := weiToUnits(balance, params.Ether)
+	if ethBalance < minEthBalance {
+		d.alerter.Fire(alerts.Alert{
+			Key:      alertKey,
+			Severity: alerts.SeverityCritical,
+			Title:    "Oracle ETH balance is low",
+			Message: fmt.Sprintf("%s has %f ETH, below the %f ETH threshold. Fulfillments will fail once it can't pay for gas.",
+				address, ethBalance, minEthBalance),
+		})
+	} else {
+		d.alerter.Resolve(alertKey, "Oracle ETH balance restored",
+			fmt.Sprintf("%s has %f ETH", address, ethBalance))
+	}
+}
+
+func (d *VORCoordinatorListener) checkHeadLagAlert(lag uint64) {
+	_, maxHeadLag, _ := alertThresholds()
+	if maxHeadLag < 0 {
+		return
+	}
+	if lag > uint64(maxHeadLag) {
+		d.alerter.Fire(alerts.Alert{
+			Key:      alertKeyHeadLag,
+			Severity: alerts.SeverityWarning,
+			Title:    "Oracle is lagging behind the chain",
+			Message: fmt.Sprintf("last processed block %d is %d blocks behind the head, above the %d block threshold",
+				d.query.FromBlock.Uint64()+1, lag, maxHeadLag),
+		})
+	} else {
+		d.alerter.Resolve(alertKeyHeadLag, "Oracle has caught up with the chain",
+			fmt.Sprintf("%d blocks behind the head", lag))
+	}
+}
+
+func (d *VORCoordinatorListener) alertFulfilmentFailed(requestId string, reason string) {
+	d.alerter.Fire(alerts.Alert{
+		Key:      alertKeyFulfilFailed + ":" + requestId,
+		Severity: alerts.SeverityCritical,
+		Title:    "Request fulfilment failed",
+		Message:  fmt.Sprintf("request %s will not be retried: %s", requestId, reason),
+	})
+}
+
+// recordRPCError counts a failed call to the Eth provider, and alerts once the number of
+// consecutive failures reaches the threshold. Not found errors are expected, for example
+// when a Tx hasn't been mined yet, so they don't count towards the threshold.
+func (d *VORCoordinatorListener) recordRPCError(method string, err error) {
+	metrics.RPCErrors.WithLabelValues(d.service.ChainName(), method).Inc()
+	if errors.Is(err, ethereum.NotFound) {
+		return
+	}
+
+	failures := atomic.AddInt64(&d.rpcFailures, 1)
+	_, _, rpcFailureThreshold := alertThresholds()
+	if rpcFailureThreshold < 0 || failures < rpcFailureThreshold {
+		return
+	}
+	d.alerter.Fire(alerts.Alert{
+		Key:      alertKeyRPCFailures,
+		Severity: alerts.SeverityCritical,
+		Title:    "Eth provider calls are failing",
+		Message:  fmt.Sprintf("%d consecutive failed calls, the last to %s: %s", failures, method, err),
+	})
+}
+
+// recordRPCSuccess resets the consecutive RPC failure count
+func (d *VORCoordinatorListener) recordRPCSuccess() {
+	if atomic.SwapInt64(&d.rpcFailures, 0) > 0 {
+		d.alerter.Resolve(alertKeyRPCFailures, "Eth provider calls recovered", "")
+	}
+}
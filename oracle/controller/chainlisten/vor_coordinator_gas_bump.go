@@ -0,0 +1,90 @@
+package chainlisten
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+	"oracle/chaincall"
+	"oracle/config"
+	"oracle/models/database"
+)
+
+const (
+	defaultGasBumpWaitBlocks = 5
+	defaultMaxGasBumps       = 3
+)
+
+// shouldBumpGas returns true if a pending fulfillment Tx has waited long enough to be
+// replaced, and the request hasn't used up its gas bumps. A negative max_gas_bumps
+// disables replacement.
+func (d *VORCoordinatorListener) shouldBumpGas(request database.RandomnessRequest, blocksSinceSent uint64) bool {
+	waitBlocks := uint64(defaultGasBumpWaitBlocks)
+	if config.Conf.GasBumpWaitBlocks != 0 {
+		waitBlocks = config.Conf.GasBumpWaitBlocks
+	}
+	maxBumps := defaultMaxGasBumps
+	if config.Conf.MaxGasBumps != 0 {
+		maxBumps = config.Conf.MaxGasBumps
+	}
+	if maxBumps < 0 || request.GetGasBumps() >= uint64(maxBumps) {
+		return false
+	}
+	return blocksSinceSent >= waitBlocks
+}
+
+// bumpFulfillmentGas replaces a stuck fulfillment Tx with the same nonce and a higher gas
+// price. The replaced Tx is recorded in the failed Tx history table.
+func (d *VORCoordinatorListener) bumpFulfillmentGas(request database.RandomnessRequest, stuckTx *types.Transaction, currentBlockNum uint64) {
+	requestId := request.GetRequestId()
+	bumpNum := request.GetGasBumps() + 1
+
+	// the replacement has to come from the account which sent the stuck Tx
+	key, ok := d.service.KeyByHash(common.HexToHash(request.GetKeyHash()))
+	if !ok {
+		d.logger.WithFields(logrus.Fields{
+			"package":    "chainlisten",
+			"function":   "bumpFulfillmentGas",
+			"action":     "find proving key",
+			"request_id": requestId,
+			"key_hash":   request.GetKeyHash(),
+		}).Error("proving key is not served by this oracle")
+		return
+	}
+
+	newTx, err := key.Caller.ReplaceTransaction(stuckTx)
+	if err != nil {
+		logger := d.logger.WithFields(logrus.Fields{
+			"package":    "chainlisten",
+			"function":   "bumpFulfillmentGas",
+			"action":     "replace fulfill tx",
+			"request_id": requestId,
+			"tx_hash":    stuckTx.Hash().Hex(),
+			"gas_bump":   bumpNum,
+		})
+		if errors.Is(err, chaincall.ErrGasBumpCeiling) {
+			logger.Warning("can't bump gas any further - wait for tx")
+		} else {
+			logger.Error(err.Error())
+		}
+		return
+	}
+
+	d.logger.WithFields(logrus.Fields{
+		"package":       "chainlisten",
+		"function":      "bumpFulfillmentGas",
+		"action":        "replace fulfill tx",
+		"request_id":    requestId,
+		"old_tx_hash":   stuckTx.Hash().Hex(),
+		"new_tx_hash":   newTx.Hash().Hex(),
+		"nonce":         newTx.Nonce(),
+		"old_gas_price": stuckTx.GasPrice().Uint64(),
+		"new_gas_price": newTx.GasPrice().Uint64(),
+		"gas_bump":      bumpNum,
+	}).Info("fulfill tx replaced")
+
+	failReason := fmt.Sprintf("replaced by %s (gas bump %d)", newTx.Hash().Hex(), bumpNum)
+	_ = d.service.Store.Db.InsertNewFailedFulfilment(requestId, stuckTx.Hash().Hex(), 0, stuckTx.GasPrice().Uint64(), failReason)
+	_ = d.service.Store.Db.UpdateFulfilmentReplaced(requestId, newTx.Hash().Hex(), currentBlockNum)
+}
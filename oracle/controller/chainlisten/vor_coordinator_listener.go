@@ -2,6 +2,7 @@ package chainlisten
 
 import (
 	"context"
+	"errors"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
@@ -12,8 +13,12 @@ import (
 	"github.com/sirupsen/logrus"
 	"math/big"
 	"math/rand"
+	"oracle/alerts"
+	"oracle/chaincall"
 	"oracle/config"
 	"oracle/contracts/vor_coordinator"
+	"oracle/ethrpc"
+	"oracle/metrics"
 	"oracle/models/database"
 	"oracle/service"
 	"oracle/tools/vor"
@@ -24,43 +29,46 @@ import (
 
 type VORCoordinatorListener struct {
 	contractAddress common.Address
-	client          *ethclient.Client
+	client          ethrpc.Backend
+	wsHost          string
+	wsClient        *ethclient.Client
 	instance        *vor_coordinator.VorCoordinator
 	query           ethereum.FilterQuery
 	wg              *sync.WaitGroup
 	service         *service.Service
-	keyHash         [32]byte
+	blockTracker    *BlockHashTracker
+	blockRange      uint64
 	context         context.Context
 	logger          *logrus.Logger
+	alerter         *alerts.Alerter
+	// consecutive failed calls to the Eth provider
+	rpcFailures int64
 }
 
-func NewVORCoordinatorListener(contractHexAddress string, ethHostAddress string,
+func NewVORCoordinatorListener(conn *chaincall.Connection,
 	service *service.Service, logger *logrus.Logger, ctx context.Context) (*VORCoordinatorListener, error) {
-	client, err := ethclient.Dial(ethHostAddress)
-	if err != nil {
-		return nil, err
-	}
-	contractAddress := common.HexToAddress(contractHexAddress)
-	instance, err := vor_coordinator.NewVorCoordinator(contractAddress, client)
-	if err != nil {
-		return nil, err
-	}
+	client := conn.Backend
+	contractAddress := conn.VORCoordinatorAddress
+	instance := conn.VORCoordinator
 
 	var lastBlock *big.Int
-	lastRequest, err := service.Store.Db.GetLast()
-	if blockNumber, _ := service.Store.Keystorage.GetBlockNumber(); blockNumber != 0 {
-		lastBlock = big.NewInt(blockNumber)
+	lastRequest, _ := service.Store.Db.GetLast()
+	if cursor, _ := service.Store.Db.GetListenerCursor(contractAddress.Hex(), conn.ChainID.Int64()); cursor.GetBlockNumber() != 0 {
+		lastBlock = new(big.Int).SetUint64(cursor.GetBlockNumber())
 	} else if lastRequest.GetRequestBlockNumber() != 0 {
 		lastBlock = big.NewInt(int64(lastRequest.GetRequestBlockNumber()))
-	} else if config.Conf.FirstBlockNumber != 0 {
-		lastBlock = big.NewInt(int64(config.Conf.FirstBlockNumber))
+	} else if conn.Chain.FirstBlockNumber != 0 {
+		lastBlock = big.NewInt(int64(conn.Chain.FirstBlockNumber))
 	} else {
 		lastBlock = big.NewInt(1)
 	}
 
-	keyHash, err := service.VORCoordinatorCaller.HashOfKey()
+	alerter := alerts.NewAlerterFromConfig(config.Conf.Alerts, logger)
+	alerter.SetChain(conn.Name())
+
 	return &VORCoordinatorListener{
 		client:          client,
+		wsHost:          conn.Chain.EthWSHost,
 		contractAddress: contractAddress,
 		instance:        instance,
 		query: ethereum.FilterQuery{
@@ -69,13 +77,19 @@ func NewVORCoordinatorListener(contractHexAddress string, ethHostAddress string,
 		},
 		service: service,
 		context: ctx,
-		keyHash: keyHash,
-		wg:      &sync.WaitGroup{},
-		logger:  logger,
-	}, err
+		blockTracker: NewBlockHashTracker(config.Conf.ReorgDepth),
+		blockRange:   maxBlockRange(),
+		wg:           &sync.WaitGroup{},
+		logger:       logger,
+		alerter:      alerter,
+	}, nil
 }
 
-func (d VORCoordinatorListener) StartPoll() (err error) {
+func (d *VORCoordinatorListener) StartPoll() (err error) {
+	if d.subscriptionEnabled() {
+		return d.StartSubscribe()
+	}
+
 	d.wg.Add(1)
 
 	d.logger.WithFields(logrus.Fields{
@@ -89,9 +103,11 @@ func (d VORCoordinatorListener) StartPoll() (err error) {
 	if config.Conf.CheckDuration != 0 {
 		sleepTime = config.Conf.CheckDuration
 	}
+	d.service.RecordHeartbeat()
 	for {
 		err = d.ProcessIncommingEvents()
 		err = d.CheckJobs()
+		d.service.RecordHeartbeat()
 		time.Sleep(time.Duration(rand.Int31n(sleepTime)) * time.Second)
 	}
 	d.wg.Wait()
@@ -104,7 +120,10 @@ func (d VORCoordinatorListener) Shutdown() {
 
 func (d *VORCoordinatorListener) SetLastBlockNumber(blockNumber uint64) (err error) {
 	d.query.FromBlock = big.NewInt(int64(blockNumber - 1))
-	err = d.service.Store.Keystorage.SetBlockNumber(int64(blockNumber - 1))
+	metrics.LastProcessedBlock.WithLabelValues(d.service.ChainName()).Set(float64(blockNumber))
+	// catching up after downtime can take several cycles' worth of time, so count progress as a heartbeat
+	d.service.RecordHeartbeat()
+	err = d.service.Store.Db.SetListenerCursor(d.contractAddress.Hex(), d.service.ChainID(), blockNumber-1)
 	return
 }
 
@@ -155,6 +174,19 @@ func (d *VORCoordinatorListener) processFulfillment(requestId string, requestTxR
 				}).Error(err.Error())
 				return
 			}
+			key, ok := d.service.KeyByHash(event.KeyHash)
+			if !ok {
+				d.logger.WithFields(logrus.Fields{
+					"package":    "chainlisten",
+					"function":   "processFulfillment",
+					"action":     "find proving key",
+					"request_id": requestId,
+					"key_hash":   common.Bytes2Hex(event.KeyHash[:]),
+				}).Error("proving key is not served by this oracle")
+				d.updateFailedStatus(requestId, database.REQUEST_STATUS_FULFILMENT_FAILED, "proving key is not served by this oracle")
+				return
+			}
+
 			byteSeed, err := vor.BigToSeed(event.Seed)
 			seedHex := hexutil.EncodeBig(event.Seed)
 			requestBlockHash := requestTxReceipt.BlockHash
@@ -162,8 +194,34 @@ func (d *VORCoordinatorListener) processFulfillment(requestId string, requestTxR
 			_ = d.service.Store.Db.UpdateRequestBlockAndSeed(requestId, requestBlockHash.Hex(), seedHex, requestTxReceipt.BlockNumber.Uint64())
 
 			// send fulfillment
-			fTx, err := d.service.FulfillRandomness(byteSeed, requestBlockHash, requestTxReceipt.BlockNumber.Uint64())
-			if err != nil {
+			fTx, err := d.service.FulfillRandomness(key, byteSeed, requestBlockHash, requestTxReceipt.BlockNumber.Uint64())
+			var revertErr *chaincall.RevertError
+			var proofErr *service.ProofVerificationError
+			if errors.Is(err, service.ErrRequestBlockNotCanonical) {
+				d.logger.WithFields(logrus.Fields{
+					"package":            "chainlisten",
+					"function":           "processFulfillment",
+					"action":             "verify proof",
+					"request_id":         requestId,
+					"request_block_hash": requestBlockHash,
+					"request_block_num":  requestTxReceipt.BlockNumber,
+				}).Warning(err.Error())
+				// not a failure. Re-check the request once the chain settles
+				_ = d.service.Store.Db.UpdateRequestStatus(requestId, database.REQUEST_STATUS_REORGED, err.Error())
+			} else if errors.As(err, &proofErr) {
+				d.logger.WithFields(logrus.Fields{
+					"package":            "chainlisten",
+					"function":           "processFulfillment",
+					"action":             "verify proof",
+					"request_id":         requestId,
+					"request_block_hash": requestBlockHash,
+					"request_block_num":  requestTxReceipt.BlockNumber,
+					"seed":               event.Seed,
+				}).Error(err.Error())
+				d.updateFailedStatus(requestId, database.REQUEST_STATUS_PROOF_INVALID, err.Error())
+			} else if errors.As(err, &revertErr) {
+				d.processSimulationRevert(requestId, revertErr)
+			} else if err != nil {
 				d.logger.WithFields(logrus.Fields{
 					"package":    "chainlisten",
 					"function":   "processFulfillment",
@@ -171,13 +229,14 @@ func (d *VORCoordinatorListener) processFulfillment(requestId string, requestTxR
 					"request_id": requestId,
 				}).Error(err.Error())
 				// possibly failed due to gas too low, or nonce too low. Flag so we can try again later
-				_ = d.service.Store.Db.UpdateRequestStatus(requestId, database.REQUEST_STATUS_TX_FAILED, err.Error())
+				d.updateFailedStatus(requestId, database.REQUEST_STATUS_TX_FAILED, err.Error())
 			} else {
 				d.logger.WithFields(logrus.Fields{
 					"package":            "chainlisten",
 					"function":           "processFulfillment",
 					"action":             "fulfill request",
 					"request_id":         requestId,
+					"account":            key.Account,
 					"request_tx_hash":    requestTxReceipt.TxHash,
 					"fulfill_tx_hash":    fTx.Hash().Hex(),
 					"request_block_hash": requestBlockHash,
@@ -220,7 +279,7 @@ func (d *VORCoordinatorListener) processFailed(request database.RandomnessReques
 			"num_attempts": request.GetFulfillmentAttempts(),
 		}).Info()
 
-		_ = d.service.Store.Db.UpdateRequestStatus(requestId, database.REQUEST_STATUS_FULFILMENT_FAILED, "too many failed attempts")
+		d.updateFailedStatus(requestId, database.REQUEST_STATUS_FULFILMENT_FAILED, "too many failed attempts")
 		return
 	}
 
@@ -241,7 +300,7 @@ func (d *VORCoordinatorListener) processFailed(request database.RandomnessReques
 				"action":     "check request age",
 				"request_id": request.GetRequestId(),
 			}).Info("request too old and block hash not in block store")
-			_ = d.service.Store.Db.UpdateRequestStatus(request.GetRequestId(), database.REQUEST_STATUS_FULFILMENT_FAILED, "request too old and block hash not in block store")
+			d.updateFailedStatus(request.GetRequestId(), database.REQUEST_STATUS_FULFILMENT_FAILED, "request too old and block hash not in block store")
 			return
 		}
 	}
@@ -295,8 +354,13 @@ func (d *VORCoordinatorListener) processPossiblyStuck(request database.Randomnes
 		return
 	}
 
-	// no point continuing if it's still pending. Log it and move on.
+	// no point continuing if it's still pending, unless it's been waiting long enough to
+	// replace with a higher gas price. Log it and move on.
 	if isPending {
+		if d.shouldBumpGas(request, lastFulfillSentBlockDiff) {
+			d.bumpFulfillmentGas(request, lastFulfillTx, currentBlockNum)
+			return
+		}
 		d.logger.WithFields(logrus.Fields{
 			"package":    "chainlisten",
 			"function":   "processPossiblyStuck",
@@ -337,12 +401,12 @@ func (d *VORCoordinatorListener) processPossiblyStuck(request database.Randomnes
 
 	// used later to store failed fulfill tx history
 	failedGasUsed := fulfillReceipt.GasUsed
-	failedGasPrice := lastFulfillTx.GasPrice().Uint64()
-	// todo - try to get actual revert reason
-	failReason := "transaction reverted"
+	failedGasPrice := d.effectiveGasPrice(lastFulfillTx, fulfillReceipt)
+	failReason := d.revertReason(requestId, lastFulfillTx, fulfillReceipt)
 
 	// Add fail info to failed Tx history table
 	_ = d.service.Store.Db.InsertNewFailedFulfilment(requestId, request.GetFulfillTxHash(), failedGasUsed, failedGasPrice, failReason)
+	_ = d.service.Store.Db.UpdateStatusReason(requestId, failReason)
 
 	// at some point, we just have to stop trying...
 	if request.GetFulfillmentAttempts() >= 3 {
@@ -355,7 +419,7 @@ func (d *VORCoordinatorListener) processPossiblyStuck(request database.Randomnes
 			"num_attempts": request.GetFulfillmentAttempts(),
 		}).Info()
 
-		_ = d.service.Store.Db.UpdateRequestStatus(requestId, database.REQUEST_STATUS_FULFILMENT_FAILED, "too many failed attempts")
+		d.updateFailedStatus(requestId, database.REQUEST_STATUS_FULFILMENT_FAILED, "too many failed attempts: "+failReason)
 		return
 	}
 
@@ -374,7 +438,7 @@ func (d *VORCoordinatorListener) processPossiblyStuck(request database.Randomnes
 				"action":     "check request age",
 				"request_id": request.GetRequestId(),
 			}).Info("request too old and block hash not in block store")
-			_ = d.service.Store.Db.UpdateRequestStatus(request.GetRequestId(), database.REQUEST_STATUS_FULFILMENT_FAILED, "request too old and block hash not in block store")
+			d.updateFailedStatus(request.GetRequestId(), database.REQUEST_STATUS_FULFILMENT_FAILED, "request too old and block hash not in block store")
 			return
 		}
 	}
@@ -402,6 +466,7 @@ func (d *VORCoordinatorListener) preProcessJob(request database.RandomnessReques
 	// get request Tx receipt from chain
 	requestTxReceipt, err := d.client.TransactionReceipt(context.Background(), common.HexToHash(request.GetRequestTxHash()))
 	if err != nil {
+		d.recordRPCError("eth_getTransactionReceipt", err)
 		// possibly not in Tx pool yet
 		d.logger.WithFields(logrus.Fields{
 			"package":    "chainlisten",
@@ -438,6 +503,9 @@ func (d *VORCoordinatorListener) preProcessJob(request database.RandomnessReques
 	case database.REQUEST_STATUS_SENT:
 		d.processPossiblyStuck(request, requestTxReceipt, currentBlockNum)
 		return
+	case database.REQUEST_STATUS_REORGED:
+		d.processReorged(request, currentBlockNum)
+		return
 	default:
 		return
 	}
@@ -454,6 +522,7 @@ func (d *VORCoordinatorListener) CheckJobs() error {
 	currentBlockNum, err := d.client.BlockNumber(context.Background())
 
 	if err != nil {
+		d.recordRPCError("eth_blockNumber", err)
 		d.logger.WithFields(logrus.Fields{
 			"package":  "chainlisten",
 			"function": "CheckJobs",
@@ -461,6 +530,9 @@ func (d *VORCoordinatorListener) CheckJobs() error {
 		}).Error(err.Error())
 		return err
 	}
+	d.recordRPCSuccess()
+	d.updateChainMetrics(currentBlockNum)
+	d.trackTxs(currentBlockNum)
 
 	// get requests status = INITIALISED || SENT || FAILED_TX from request_randomness table
 	requests, err := d.service.Store.Db.GetJobs()
@@ -474,214 +546,376 @@ func (d *VORCoordinatorListener) CheckJobs() error {
 		return err
 	}
 
+	// process jobs concurrently, so that proofs are generated in parallel
+	jobs := make(chan database.RandomnessRequest)
+	var workers sync.WaitGroup
+	for i := 0; i < numFulfillmentWorkers(len(requests)); i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for request := range jobs {
+				d.preProcessJob(request, currentBlockNum)
+			}
+		}()
+	}
+
 	for _, request := range requests {
-		// process
-		d.preProcessJob(request, currentBlockNum)
+		jobs <- request
 	}
+	close(jobs)
+	workers.Wait()
 
 	return nil
 }
 
-func (d *VORCoordinatorListener) ProcessIncommingEvents() error {
-	logs, err := d.client.FilterLogs(context.Background(), d.query)
-	if err != nil {
-		return err
+// numFulfillmentWorkers returns the configured number of job workers, capped by the number of jobs
+func numFulfillmentWorkers(numJobs int) int {
+	numWorkers := config.Conf.FulfillmentWorkers
+	if numWorkers <= 0 {
+		numWorkers = 1
 	}
-
-	thisBlockNum, err := d.client.BlockNumber(context.Background())
-	if err == nil {
-		_ = d.SetLastBlockNumber(thisBlockNum)
+	if numJobs < numWorkers {
+		numWorkers = numJobs
 	}
+	return numWorkers
+}
 
-	if len(logs) == 0 {
+func (d *VORCoordinatorListener) ProcessIncommingEvents() error {
+	err := d.checkReorg()
+	if err != nil {
 		d.logger.WithFields(logrus.Fields{
-			"package":    "chainlisten",
-			"function":   "ProcessIncommingEvents",
-			"action":     "check events",
-			"from_block": d.query.FromBlock.Uint64(),
-			"to_block":   thisBlockNum,
-		}).Info("no applicable logs")
+			"package":  "chainlisten",
+			"function": "ProcessIncommingEvents",
+			"action":   "check for chain reorganisation",
+		}).Error(err.Error())
+	}
 
-		return nil
+	head, err := d.client.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		d.recordRPCError("eth_getBlockByNumber", err)
+		return err
 	}
+	d.recordRPCSuccess()
+	thisBlockNum := head.Number.Uint64()
+	d.blockTracker.Add(thisBlockNum, head.Hash())
 
 	contractAbi, err := abi.JSON(strings.NewReader(vor_coordinator.VorCoordinatorABI))
 	if err != nil {
 		return err
 	}
 
-	logRandomnessRequestHash := crypto.Keccak256Hash([]byte("RandomnessRequest(bytes32,uint256,address,uint256,bytes32)"))
-	logRandomnessRequestFulfilledHash := crypto.Keccak256Hash([]byte("RandomnessRequestFulfilled(bytes32,uint256)"))
-
-	for _, vLog := range logs {
-		d.logger.WithFields(logrus.Fields{
-			"package":   "chainlisten",
-			"function":  "ProcessIncommingEvents",
-			"action":    "log",
-			"block_num": vLog.BlockNumber,
-			"log_index": vLog.Index,
-		}).Info()
-
-		gasPrice := uint64(0)
-		gasUsed := uint64(0)
-
-		txRec, err := d.client.TransactionReceipt(context.Background(), vLog.TxHash)
-		if err == nil {
-			// todo - need to clean up and gather any missing data if Tx query above fails
-			gasUsed = txRec.GasUsed
-		} else {
-			d.logger.WithFields(logrus.Fields{
-				"package":  "chainlisten",
-				"function": "ProcessIncommingEvents",
-				"action":   "get TransactionReceipt",
-			}).Error(err.Error())
+	// scan in chunks, so that catching up after downtime doesn't exceed the provider's
+	// block range or result limits
+	fromBlock := d.query.FromBlock.Uint64()
+	for fromBlock <= thisBlockNum {
+		toBlock := fromBlock + d.blockRange - 1
+		if toBlock > thisBlockNum {
+			toBlock = thisBlockNum
 		}
 
-		tx, _, err := d.client.TransactionByHash(context.Background(), vLog.TxHash)
-		if err == nil {
-			// todo - need to clean up and gather any missing data if Tx query above fails
-			gasPrice = tx.GasPrice().Uint64()
-		} else {
-			d.logger.WithFields(logrus.Fields{
-				"package":  "chainlisten",
-				"function": "ProcessIncommingEvents",
-				"action":   "get TransactionByHash",
-			}).Error(err.Error())
+		logs, err := d.client.FilterLogs(context.Background(), ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(fromBlock),
+			ToBlock:   new(big.Int).SetUint64(toBlock),
+			Addresses: d.query.Addresses,
+		})
+		if err != nil {
+			if isRangeTooLargeError(err) && d.blockRange > 1 {
+				d.blockRange = d.blockRange / 2
+				d.logger.WithFields(logrus.Fields{
+					"package":     "chainlisten",
+					"function":    "ProcessIncommingEvents",
+					"action":      "shrink block range",
+					"from_block":  fromBlock,
+					"block_range": d.blockRange,
+				}).Warning(err.Error())
+				continue
+			}
+			d.recordRPCError("eth_getLogs", err)
+			return err
 		}
 
-		switch vLog.Topics[0].Hex() {
-		// RandomnessRequest event detected
-		case logRandomnessRequestHash.Hex():
+		if len(logs) == 0 {
 			d.logger.WithFields(logrus.Fields{
 				"package":    "chainlisten",
 				"function":   "ProcessIncommingEvents",
-				"action":     "check event name",
-				"event_name": "RandomnessRequest",
-			}).Info("processing event")
+				"action":     "check events",
+				"from_block": fromBlock,
+				"to_block":   toBlock,
+			}).Info("no applicable logs")
+		}
 
-			event := vor_coordinator.VorCoordinatorRandomnessRequest{}
-			err = contractAbi.UnpackIntoInterface(&event, "RandomnessRequest", vLog.Data)
+		for _, vLog := range logs {
+			err = d.processEvent(contractAbi, vLog)
 			if err != nil {
-				d.logger.WithFields(logrus.Fields{
-					"package":  "chainlisten",
-					"function": "ProcessIncommingEvents",
-					"action":   "UnpackIntoInterface",
-				}).Error(err.Error())
 				return err
 			}
+		}
 
-			if event.KeyHash == d.keyHash {
-				requestId := common.Bytes2Hex(event.RequestID[:])
-				d.logger.WithFields(logrus.Fields{
-					"package":    "chainlisten",
-					"function":   "ProcessIncommingEvents",
-					"action":     "check event keyhash",
-					"request_id": requestId,
-				}).Info("It's a request for me =)")
-
-				// check status and if requests already exists
-				reqDbRes, _ := d.service.Store.Db.FindByRequestId(requestId)
-
-				if reqDbRes.ID == 0 {
-					d.logger.WithFields(logrus.Fields{
-						"package":  "chainlisten",
-						"function": "ProcessIncommingEvents",
-						"action":   "add job to db",
-					}).Info("new request")
-
-					_ = d.service.Store.Db.InsertNewRequest(
-						common.Bytes2Hex(event.KeyHash[:]),
-						event.Sender.Hex(),
-						requestId,
-						database.REQUEST_STATUS_INITIALISED,
-						vLog.TxHash.Hex(),
-						gasUsed,
-						gasPrice,
-						event.Fee.Uint64(),
-					)
-				} else {
-					d.logger.WithFields(logrus.Fields{
-						"package":    "chainlisten",
-						"function":   "ProcessIncommingEvents",
-						"action":     "check db for request",
-						"request_id": reqDbRes.RequestId,
-						"status":     reqDbRes.GetStatusString(),
-					}).Info("request already in db")
-				}
-			} else {
-				d.logger.WithFields(logrus.Fields{
-					"package":  "chainlisten",
-					"function": "ProcessIncommingEvents",
-					"action":   "check event keyhash",
-				}).Info("Looks like it's not addressed to me =(")
+		_ = d.SetLastBlockNumber(toBlock)
+		fromBlock = toBlock + 1
+
+		// grow back towards the configured range once the provider copes again
+		if d.blockRange < maxBlockRange() {
+			d.blockRange = d.blockRange * 2
+			if d.blockRange > maxBlockRange() {
+				d.blockRange = maxBlockRange()
 			}
-			continue
-		// RandomnessRequestFulfilled event detected
-		case logRandomnessRequestFulfilledHash.Hex():
+		}
+	}
+
+	return nil
+}
+
+func (d *VORCoordinatorListener) processEvent(contractAbi abi.ABI, vLog types.Log) (err error) {
+	logRandomnessRequestHash := crypto.Keccak256Hash([]byte("RandomnessRequest(bytes32,uint256,address,uint256,bytes32)"))
+	logRandomnessRequestFulfilledHash := crypto.Keccak256Hash([]byte("RandomnessRequestFulfilled(bytes32,uint256)"))
+
+	d.logger.WithFields(logrus.Fields{
+		"package":   "chainlisten",
+		"function":  "processEvent",
+		"action":    "log",
+		"block_num": vLog.BlockNumber,
+		"log_index": vLog.Index,
+	}).Info()
+
+	if vLog.Removed {
+		return d.processRemovedEvent(contractAbi, vLog)
+	}
+	d.blockTracker.Add(vLog.BlockNumber, vLog.BlockHash)
+
+	gasPrice := uint64(0)
+	gasUsed := uint64(0)
+
+	txRec, err := d.client.TransactionReceipt(context.Background(), vLog.TxHash)
+	if err == nil {
+		// todo - need to clean up and gather any missing data if Tx query above fails
+		gasUsed = txRec.GasUsed
+	} else {
+		d.recordRPCError("eth_getTransactionReceipt", err)
+		d.logger.WithFields(logrus.Fields{
+			"package":  "chainlisten",
+			"function": "processEvent",
+			"action":   "get TransactionReceipt",
+		}).Error(err.Error())
+	}
+
+	tx, _, err := d.client.TransactionByHash(context.Background(), vLog.TxHash)
+	if err == nil {
+		// todo - need to clean up and gather any missing data if Tx query above fails
+		gasPrice = d.effectiveGasPrice(tx, txRec)
+	} else {
+		d.recordRPCError("eth_getTransactionByHash", err)
+		d.logger.WithFields(logrus.Fields{
+			"package":  "chainlisten",
+			"function": "processEvent",
+			"action":   "get TransactionByHash",
+		}).Error(err.Error())
+	}
+
+	switch vLog.Topics[0].Hex() {
+	// RandomnessRequest event detected
+	case logRandomnessRequestHash.Hex():
+		d.logger.WithFields(logrus.Fields{
+			"package":    "chainlisten",
+			"function":   "processEvent",
+			"action":     "check event name",
+			"event_name": "RandomnessRequest",
+		}).Info("processing event")
+
+		event := vor_coordinator.VorCoordinatorRandomnessRequest{}
+		err = contractAbi.UnpackIntoInterface(&event, "RandomnessRequest", vLog.Data)
+		if err != nil {
+			d.logger.WithFields(logrus.Fields{
+				"package":  "chainlisten",
+				"function": "processEvent",
+				"action":   "UnpackIntoInterface",
+			}).Error(err.Error())
+			return err
+		}
+
+		if key, ok := d.service.KeyByHash(event.KeyHash); ok {
+			requestId := common.Bytes2Hex(event.RequestID[:])
 			d.logger.WithFields(logrus.Fields{
 				"package":    "chainlisten",
-				"function":   "ProcessIncommingEvents",
-				"action":     "check event name",
-				"event_name": "RandomnessRequestFulfilled",
-			}).Info("processing event")
+				"function":   "processEvent",
+				"action":     "check event keyhash",
+				"request_id": requestId,
+				"account":    key.Account,
+			}).Info("It's a request for me =)")
 
-			event := vor_coordinator.VorCoordinatorRandomnessRequestFulfilled{}
-			err := contractAbi.UnpackIntoInterface(&event, "RandomnessRequestFulfilled", vLog.Data)
-			requestId := common.Bytes2Hex(event.RequestId[:])
+			// check status and if requests already exists
 			reqDbRes, _ := d.service.Store.Db.FindByRequestId(requestId)
 
-			if reqDbRes.ID != 0 {
+			if reqDbRes.ID == 0 {
 				d.logger.WithFields(logrus.Fields{
-					"package":    "chainlisten",
-					"function":   "ProcessIncommingEvents",
-					"action":     "confirm fulfillment",
-					"request_id": requestId,
-				}).Info("confirmed request fulfilment for request")
-
-				if err != nil {
-					return err
-				}
+					"package":  "chainlisten",
+					"function": "processEvent",
+					"action":   "add job to db",
+				}).Info("new request")
 
-				err = d.service.Store.Db.UpdateFulfillment(
+				_ = d.service.Store.Db.InsertNewRequest(
+					common.Bytes2Hex(event.KeyHash[:]),
+					event.Sender.Hex(),
 					requestId,
-					database.REQUEST_STATUS_SUCCESS,
-					event.Output.String(),
+					database.REQUEST_STATUS_INITIALISED,
+					vLog.TxHash.Hex(),
 					vLog.BlockHash.Hex(),
 					vLog.BlockNumber,
-					vLog.TxHash.Hex(),
 					gasUsed,
 					gasPrice,
+					event.Fee.Uint64(),
 				)
-				if err != nil {
-					d.logger.WithFields(logrus.Fields{
-						"package":  "chainlisten",
-						"function": "ProcessIncommingEvents",
-						"action":   "UpdateFulfillment",
-					}).Error(err.Error())
-					return err
-				}
+				metrics.RequestsSeen.WithLabelValues(d.service.ChainName(), key.Account).Inc()
 			} else {
 				d.logger.WithFields(logrus.Fields{
 					"package":    "chainlisten",
-					"function":   "ProcessIncommingEvents",
-					"action":     "confirm fulfillment",
-					"request_id": requestId,
-				}).Warning("request id does not exist in db. Probably not mine")
+					"function":   "processEvent",
+					"action":     "check db for request",
+					"request_id": reqDbRes.RequestId,
+					"status":     reqDbRes.GetStatusString(),
+				}).Info("request already in db")
 			}
-			continue
-		default:
+		} else {
 			d.logger.WithFields(logrus.Fields{
 				"package":  "chainlisten",
-				"function": "ProcessIncommingEvents",
-				"action":   "check event name",
-			}).Info("event not applicable")
-			continue
+				"function": "processEvent",
+				"action":   "check event keyhash",
+			}).Info("Looks like it's not addressed to me =(")
 		}
-	}
+		return nil
+	// RandomnessRequestFulfilled event detected
+	case logRandomnessRequestFulfilledHash.Hex():
+		d.logger.WithFields(logrus.Fields{
+			"package":    "chainlisten",
+			"function":   "processEvent",
+			"action":     "check event name",
+			"event_name": "RandomnessRequestFulfilled",
+		}).Info("processing event")
+
+		event := vor_coordinator.VorCoordinatorRandomnessRequestFulfilled{}
+		err := contractAbi.UnpackIntoInterface(&event, "RandomnessRequestFulfilled", vLog.Data)
+		requestId := common.Bytes2Hex(event.RequestId[:])
+		reqDbRes, _ := d.service.Store.Db.FindByRequestId(requestId)
+
+		if reqDbRes.ID != 0 {
+			d.logger.WithFields(logrus.Fields{
+				"package":    "chainlisten",
+				"function":   "processEvent",
+				"action":     "confirm fulfillment",
+				"request_id": requestId,
+			}).Info("confirmed request fulfilment for request")
+
+			if err != nil {
+				return err
+			}
 
-	return err
+			// rescanned blocks deliver the same log again, so it's only counted once
+			alreadyFulfilled := reqDbRes.GetStatus() == database.REQUEST_STATUS_SUCCESS
+			err = d.service.Store.Db.UpdateFulfillment(
+				requestId,
+				database.REQUEST_STATUS_SUCCESS,
+				event.Output.String(),
+				vLog.BlockHash.Hex(),
+				vLog.BlockNumber,
+				vLog.TxHash.Hex(),
+				gasUsed,
+				gasPrice,
+			)
+			if err != nil {
+				d.logger.WithFields(logrus.Fields{
+					"package":  "chainlisten",
+					"function": "processEvent",
+					"action":   "UpdateFulfillment",
+				}).Error(err.Error())
+				return err
+			}
+			if !alreadyFulfilled {
+				d.observeFulfillment(reqDbRes, vLog.BlockNumber, gasUsed, gasPrice)
+			}
+		} else {
+			d.logger.WithFields(logrus.Fields{
+				"package":    "chainlisten",
+				"function":   "processEvent",
+				"action":     "confirm fulfillment",
+				"request_id": requestId,
+			}).Warning("request id does not exist in db. Probably not mine")
+		}
+		return nil
+	default:
+		d.logger.WithFields(logrus.Fields{
+			"package":  "chainlisten",
+			"function": "processEvent",
+			"action":   "check event name",
+		}).Info("event not applicable")
+		return nil
+	}
 }
 
 func (d VORCoordinatorListener) RandomnessRequest() {
 
 }
+
+// effectiveGasPrice returns the price per gas paid by a mined Tx. Type-2 Txs pay less than
+// their GasFeeCap, so the receipt's block base fee is needed. Falls back to the Tx's own
+// gas price if the receipt or block isn't available.
+func (d *VORCoordinatorListener) effectiveGasPrice(tx *types.Transaction, receipt *types.Receipt) uint64 {
+	if receipt == nil {
+		return tx.GasPrice().Uint64()
+	}
+	gasPrice, err := d.service.VORCoordinatorCaller.GetEffectiveGasPrice(tx, receipt)
+	if err != nil {
+		d.logger.WithFields(logrus.Fields{
+			"package":  "chainlisten",
+			"function": "effectiveGasPrice",
+			"action":   "get effective gas price",
+			"tx_hash":  tx.Hash().Hex(),
+		}).Error(err.Error())
+		return tx.GasPrice().Uint64()
+	}
+	return gasPrice.Uint64()
+}
+
+// revertReason replays a failed fulfillment Tx to find out why it reverted
+func (d *VORCoordinatorListener) revertReason(requestId string, tx *types.Transaction, receipt *types.Receipt) string {
+	reason, err := d.service.VORCoordinatorCaller.GetRevertReason(tx, receipt)
+	if err != nil {
+		d.logger.WithFields(logrus.Fields{
+			"package":    "chainlisten",
+			"function":   "revertReason",
+			"action":     "get revert reason",
+			"request_id": requestId,
+			"tx_hash":    tx.Hash().Hex(),
+		}).Error(err.Error())
+		return "transaction reverted"
+	}
+
+	d.logger.WithFields(logrus.Fields{
+		"package":    "chainlisten",
+		"function":   "revertReason",
+		"action":     "get revert reason",
+		"request_id": requestId,
+		"tx_hash":    tx.Hash().Hex(),
+		"reason":     reason,
+	}).Info("fulfill tx reverted")
+	return reason
+}
+
+// processSimulationRevert handles a fulfillment whose dry run reverted, so was never sent. If the
+// coordinator no longer has the request it has already been fulfilled, and there's nothing to
+// retry or alert on - the RandomnessRequestFulfilled event will still mark it as a success if it
+// was ours. Anything else is flagged as a failed Tx, and retried up to the usual number of attempts.
+func (d *VORCoordinatorListener) processSimulationRevert(requestId string, revertErr *chaincall.RevertError) {
+	d.logger.WithFields(logrus.Fields{
+		"package":    "chainlisten",
+		"function":   "processSimulationRevert",
+		"action":     "simulate fulfillment",
+		"request_id": requestId,
+		"reason":     revertErr.Reason,
+	}).Warning("fulfillment would revert - not sent")
+
+	if revertErr.Reason == "no corresponding request" {
+		_ = d.service.Store.Db.UpdateRequestStatus(requestId, database.REQUEST_STATUS_ALREADY_FULFILLED, "request already fulfilled: "+revertErr.Error())
+		return
+	}
+	d.updateFailedStatus(requestId, database.REQUEST_STATUS_TX_FAILED, revertErr.Error())
+}
@@ -3,7 +3,7 @@ package chainlisten_test
 import (
 	"context"
 	"github.com/sirupsen/logrus"
-	"math/big"
+	"oracle/chaincall"
 	"oracle/controller/chainlisten"
 	"oracle/service"
 	"os"
@@ -17,8 +17,8 @@ func InitCaller() (err error) {
 	return err
 }
 
-func VORCoordinatorCallerTestValues() (string, string, *big.Int, []byte) {
-	return Config.VORCoordinatorContractAddress, Config.EthHTTPHost, big.NewInt(Config.NetworkID), []byte(Keystore.GetByUsername(Config.Keystorage.Account).Private)
+func VORCoordinatorCallerTestValues() (*chaincall.Connection, []byte) {
+	return Service.Connection, []byte(Keystore.GetByUsername(Config.Keystorage.Account).Private)
 }
 
 func Init(configAddres string, pass string) (err error) {
@@ -42,8 +42,8 @@ func Init(configAddres string, pass string) (err error) {
 	return err
 }
 
-func VORCoordinatorListenerTestValues() (string, string, *service.Service, *logrus.Logger, context.Context) {
-	return Config.VORCoordinatorContractAddress, Config.EthHTTPHost, Service, logrus.New(), context.Background()
+func VORCoordinatorListenerTestValues() (*chaincall.Connection, *service.Service, *logrus.Logger, context.Context) {
+	return Service.Connection, Service, logrus.New(), context.Background()
 }
 
 func TestVORCoordinatorListener_Request(t *testing.T) {
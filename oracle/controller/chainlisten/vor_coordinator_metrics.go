@@ -0,0 +1,140 @@
+package chainlisten
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/sirupsen/logrus"
+	"math/big"
+	"oracle/metrics"
+	"oracle/models/database"
+	"oracle/service"
+	"time"
+)
+
+// updateFailedStatus flags a request as failed, and counts the failure. Requests which
+// won't be retried are alerted on.
+func (d *VORCoordinatorListener) updateFailedStatus(requestId string, status int, statusReason string) {
+	metrics.RequestsFailed.WithLabelValues(d.service.ChainName(), metrics.StatusLabel(status)).Inc()
+	_ = d.service.Store.Db.UpdateRequestStatus(requestId, status, statusReason)
+	if status == database.REQUEST_STATUS_FULFILMENT_FAILED {
+		d.alertFulfilmentFailed(requestId, statusReason)
+	}
+}
+
+// observeFulfillment records the latency and gas cost of a confirmed fulfillment
+func (d *VORCoordinatorListener) observeFulfillment(request database.RandomnessRequest, fulfillBlockNum uint64, gasUsed uint64, gasPrice uint64) {
+	chain := d.service.ChainName()
+	metrics.RequestsFulfilled.WithLabelValues(chain, d.accountForKeyHash(request.GetKeyHash())).Inc()
+	if request.GetRequestBlockNumber() > 0 && fulfillBlockNum >= request.GetRequestBlockNumber() {
+		metrics.FulfillmentLatencyBlocks.WithLabelValues(chain).Observe(float64(fulfillBlockNum - request.GetRequestBlockNumber()))
+	}
+	metrics.FulfillmentLatencySeconds.WithLabelValues(chain).Observe(time.Since(request.CreatedAt).Seconds())
+	metrics.FulfillmentGasUsed.WithLabelValues(chain).Observe(float64(gasUsed))
+	metrics.FulfillmentGasPrice.WithLabelValues(chain).Observe(float64(gasPrice) / params.GWei)
+}
+
+// updateChainMetrics refreshes the head lag and the oracle's balances, and alerts if either
+// has crossed its threshold
+func (d *VORCoordinatorListener) updateChainMetrics(headBlockNum uint64) {
+	var lag uint64
+	lastProcessed := d.query.FromBlock.Uint64() + 1
+	if headBlockNum > lastProcessed {
+		lag = headBlockNum - lastProcessed
+	}
+	metrics.HeadBlockLag.WithLabelValues(d.service.ChainName()).Set(float64(lag))
+	d.checkHeadLagAlert(lag)
+
+	for _, key := range d.service.Keys() {
+		d.updateKeyMetrics(key)
+	}
+	d.updateSendingMetrics()
+}
+
+// updateSendingMetrics refreshes the balances and nonces of the sending accounts, and tops
+// up any which are running low
+func (d *VORCoordinatorListener) updateSendingMetrics() {
+	err := d.service.RefreshSenders(d.context)
+	if err != nil {
+		d.recordRPCError("eth_getBalance", err)
+		d.logger.WithFields(logrus.Fields{
+			"package":  "chainlisten",
+			"function": "updateSendingMetrics",
+			"action":   "refresh sending accounts",
+		}).Error(err.Error())
+		return
+	}
+
+	for _, account := range d.service.SendingAccounts() {
+		if account.Balance == nil {
+			// added since the refresh
+			continue
+		}
+		address := account.Address.Hex()
+		metrics.SendingBalance.WithLabelValues(d.service.ChainName(), address).Set(weiToUnits(account.Balance, params.Ether))
+		metrics.SendingPendingTxs.WithLabelValues(d.service.ChainName(), address).Set(float64(account.Pending))
+		d.checkBalanceAlert(address, address, account.Balance)
+	}
+
+	txs, err := d.service.TopUpSenders(d.context)
+	for _, tx := range txs {
+		metrics.SendingTopUps.WithLabelValues(d.service.ChainName(), tx.To().Hex()).Inc()
+		d.logger.WithFields(logrus.Fields{
+			"package":  "chainlisten",
+			"function": "updateSendingMetrics",
+			"action":   "top up sending account",
+			"address":  tx.To().Hex(),
+			"tx":       tx.Hash().Hex(),
+		}).Info()
+	}
+	if err != nil {
+		d.logger.WithFields(logrus.Fields{
+			"package":  "chainlisten",
+			"function": "updateSendingMetrics",
+			"action":   "top up sending accounts",
+		}).Error(err.Error())
+	}
+}
+
+// updateKeyMetrics refreshes the balances of one of the oracle's keys
+func (d *VORCoordinatorListener) updateKeyMetrics(key *service.ProvingKey) {
+	ethBalance, err := key.Caller.GetOracleEthBalance()
+	if err != nil {
+		d.recordRPCError("eth_getBalance", err)
+		d.logger.WithFields(logrus.Fields{
+			"package":  "chainlisten",
+			"function": "updateKeyMetrics",
+			"action":   "get eth balance",
+			"account":  key.Account,
+		}).Error(err.Error())
+	} else {
+		metrics.EthBalance.WithLabelValues(d.service.ChainName(), key.Account).Set(weiToUnits(ethBalance, params.Ether))
+		d.checkBalanceAlert(key.Account, key.Caller.OracleAddress(), ethBalance)
+	}
+
+	withdrawable, err := key.Caller.QueryWithdrawableTokens()
+	if err != nil {
+		d.recordRPCError("eth_call", err)
+		d.logger.WithFields(logrus.Fields{
+			"package":  "chainlisten",
+			"function": "updateKeyMetrics",
+			"action":   "query withdrawable tokens",
+			"account":  key.Account,
+		}).Error(err.Error())
+	} else {
+		// xFUND has 9 decimals
+		metrics.WithdrawableTokens.WithLabelValues(d.service.ChainName(), key.Account).Set(weiToUnits(withdrawable, params.GWei))
+	}
+}
+
+// accountForKeyHash returns the keystore account of a key hash stored in the DB
+func (d *VORCoordinatorListener) accountForKeyHash(keyHashHex string) string {
+	if key, ok := d.service.KeyByHash(common.HexToHash(keyHashHex)); ok {
+		return key.Account
+	}
+	return ""
+}
+
+func weiToUnits(amount *big.Int, unit float64) float64 {
+	units, _ := new(big.Float).Quo(new(big.Float).SetInt(amount), big.NewFloat(unit)).Float64()
+	return units
+}
@@ -0,0 +1,98 @@
+package chainlisten_test
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"oracle/chaincall"
+	"oracle/config"
+	"oracle/contracts/vor_coordinator"
+	"oracle/controller/chainlisten"
+	"oracle/ethrpc"
+	"oracle/metrics"
+	"oracle/models/database"
+	"oracle/service"
+	"oracle/store"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fixedLogsBackend returns the same logs for every eth_getLogs query
+type fixedLogsBackend struct {
+	ethrpc.Backend
+	logs []types.Log
+}
+
+func (b *fixedLogsBackend) FilterLogs(_ context.Context, _ ethereum.FilterQuery) ([]types.Log, error) {
+	return b.logs, nil
+}
+
+func TestVORCoordinatorListener_FulfilledCountedOnce(t *testing.T) {
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{}, 8000000)
+	backend.Commit()
+	client := ethrpc.NewSimulatedClient(backend)
+	chainID, _ := client.ChainID(context.Background())
+
+	contractAbi, err := abi.JSON(strings.NewReader(vor_coordinator.VorCoordinatorABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	requestID := crypto.Keccak256Hash([]byte(t.Name()))
+	data, err := contractAbi.Events["RandomnessRequestFulfilled"].Inputs.Pack(requestID, big.NewInt(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := client.HeaderByNumber(context.Background(), big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fulfilled := types.Log{
+		Topics:      []common.Hash{crypto.Keccak256Hash([]byte("RandomnessRequestFulfilled(bytes32,uint256)"))},
+		Data:        data,
+		BlockNumber: 1,
+		BlockHash:   block.Hash(),
+		TxHash:      common.HexToHash("0x02"),
+	}
+	logs := &fixedLogsBackend{Backend: client, logs: []types.Log{fulfilled}}
+
+	conn, err := chaincall.NewConnection(logs, chainID,
+		"0xCfEB869F69431e42cdB54A4F4f105C19C080A601", "0x254dffcd3277C0b1660F6d42EFbB754edaBAbC2B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Chain = &config.Chain{Name: "fulfilled_once", FirstBlockNumber: 1}
+
+	config.Conf.Database.Storage = filepath.Join(t.TempDir(), "oracle.db")
+	thestore, err := store.NewStore(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, thestore.Db.Migrate())
+	thestore = thestore.ForChain(chainID.Int64())
+	requestId := common.Bytes2Hex(requestID[:])
+	assert.NoError(t, thestore.Db.InsertNewRequest("keyHash", "Sender", requestId, database.REQUEST_STATUS_SENT, "txHash", block.Hash().Hex(), 1, 1, 1, 1))
+
+	listener, err := chainlisten.NewVORCoordinatorListener(conn, &service.Service{Store: thestore, Connection: conn}, Log, context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the last processed block is scanned again, so the log is seen twice
+	fulfilledCount := metrics.RequestsFulfilled.WithLabelValues("fulfilled_once", "")
+	assert.NoError(t, listener.ProcessIncommingEvents())
+	assert.NoError(t, listener.ProcessIncommingEvents())
+	assert.Equal(t, float64(1), testutil.ToFloat64(fulfilledCount))
+
+	request, err := thestore.Db.FindByRequestId(requestId)
+	assert.NoError(t, err)
+	assert.Equal(t, database.REQUEST_STATUS_SUCCESS, request.GetStatus())
+}
@@ -0,0 +1,216 @@
+package chainlisten
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/sirupsen/logrus"
+	"oracle/contracts/vor_coordinator"
+	"oracle/models/database"
+)
+
+// checkReorg compares the tracked block hashes against the canonical chain. If a reorg
+// is found, requests which depended on orphaned blocks are flagged for re-checking, and
+// the event query is rewound to the common ancestor so that events are picked up again.
+func (d *VORCoordinatorListener) checkReorg() error {
+	ancestor, reorged, err := d.blockTracker.FindReorg(d.context, d.client)
+	if err != nil || !reorged {
+		return err
+	}
+
+	d.logger.WithFields(logrus.Fields{
+		"package":        "chainlisten",
+		"function":       "checkReorg",
+		"action":         "find common ancestor",
+		"ancestor_block": ancestor,
+	}).Warning("chain reorganisation detected")
+
+	numRows, err := d.service.Store.Db.RollbackReorgedRequests(ancestor, "block orphaned by chain reorganisation")
+	if err != nil {
+		return err
+	}
+
+	d.logger.WithFields(logrus.Fields{
+		"package":        "chainlisten",
+		"function":       "checkReorg",
+		"action":         "roll back requests",
+		"ancestor_block": ancestor,
+		"num_requests":   numRows,
+	}).Info()
+
+	if d.query.FromBlock.Uint64() > ancestor {
+		return d.SetLastBlockNumber(ancestor + 1)
+	}
+	return nil
+}
+
+// trackHead records a new head received via subscription, checking for a reorg first if it
+// doesn't build on the tracked chain. Returns true if the head replaced tracked blocks.
+func (d *VORCoordinatorListener) trackHead(head *types.Header) (reorged bool) {
+	headNum := head.Number.Uint64()
+	parentHash, parentTracked := d.blockTracker.Get(headNum - 1)
+	headHash, headTracked := d.blockTracker.Get(headNum)
+	if (parentTracked && parentHash != head.ParentHash) || (headTracked && headHash != head.Hash()) {
+		reorged = true
+		err := d.checkReorg()
+		if err != nil {
+			d.logger.WithFields(logrus.Fields{
+				"package":   "chainlisten",
+				"function":  "trackHead",
+				"action":    "check for chain reorganisation",
+				"block_num": headNum,
+			}).Error(err.Error())
+		}
+	}
+	d.blockTracker.Add(headNum, head.Hash())
+	return reorged
+}
+
+// processRemovedEvent handles logs flagged as removed by the node when their block is orphaned.
+// Only subscriptions deliver these - polled logs are caught by checkReorg.
+func (d *VORCoordinatorListener) processRemovedEvent(contractAbi abi.ABI, vLog types.Log) error {
+	logRandomnessRequestHash := crypto.Keccak256Hash([]byte("RandomnessRequest(bytes32,uint256,address,uint256,bytes32)"))
+	logRandomnessRequestFulfilledHash := crypto.Keccak256Hash([]byte("RandomnessRequestFulfilled(bytes32,uint256)"))
+
+	var requestId string
+	switch vLog.Topics[0].Hex() {
+	case logRandomnessRequestHash.Hex():
+		event := vor_coordinator.VorCoordinatorRandomnessRequest{}
+		err := contractAbi.UnpackIntoInterface(&event, "RandomnessRequest", vLog.Data)
+		if err != nil {
+			return err
+		}
+		requestId = common.Bytes2Hex(event.RequestID[:])
+	case logRandomnessRequestFulfilledHash.Hex():
+		event := vor_coordinator.VorCoordinatorRandomnessRequestFulfilled{}
+		err := contractAbi.UnpackIntoInterface(&event, "RandomnessRequestFulfilled", vLog.Data)
+		if err != nil {
+			return err
+		}
+		requestId = common.Bytes2Hex(event.RequestId[:])
+	default:
+		return nil
+	}
+
+	d.logger.WithFields(logrus.Fields{
+		"package":    "chainlisten",
+		"function":   "processRemovedEvent",
+		"action":     "flag request",
+		"request_id": requestId,
+		"block_num":  vLog.BlockNumber,
+		"block_hash": vLog.BlockHash.Hex(),
+	}).Warning("event removed by chain reorganisation")
+
+	return d.service.Store.Db.MarkRequestReorged(requestId, "event removed by chain reorganisation")
+}
+
+// processReorged works out what happened to a request's fulfillment Tx after a reorg, and
+// moves the request back into the normal job flow.
+func (d *VORCoordinatorListener) processReorged(request database.RandomnessRequest, currentBlockNum uint64) {
+	requestId := request.GetRequestId()
+	d.logger.WithFields(logrus.Fields{
+		"package":       "chainlisten",
+		"function":      "processReorged",
+		"action":        "start",
+		"request_id":    requestId,
+		"current_block": currentBlockNum,
+	}).Info()
+
+	if request.GetFulfillTxHash() == "" {
+		d.processReorgedRequest(request)
+		return
+	}
+
+	fulfilTxHash := common.HexToHash(request.GetFulfillTxHash())
+	_, isPending, err := d.client.TransactionByHash(context.Background(), fulfilTxHash)
+	if err != nil {
+		// the fulfillment Tx was dropped along with the orphaned block. Fulfil again.
+		d.logger.WithFields(logrus.Fields{
+			"package":    "chainlisten",
+			"function":   "processReorged",
+			"action":     "get fulfill tx",
+			"request_id": requestId,
+			"tx_hash":    request.GetFulfillTxHash(),
+		}).Info("fulfill tx dropped - re-initialise request")
+		_ = d.service.Store.Db.UpdateRequestStatus(requestId, database.REQUEST_STATUS_INITIALISED, "fulfill tx dropped by chain reorganisation")
+		return
+	}
+
+	if isPending {
+		// back in the Tx pool - handle it like any other sent Tx
+		_ = d.service.Store.Db.UpdateRequestStatus(requestId, database.REQUEST_STATUS_SENT, "fulfill tx returned to tx pool by chain reorganisation")
+		return
+	}
+
+	fulfillReceipt, err := d.client.TransactionReceipt(context.Background(), fulfilTxHash)
+	if err != nil {
+		d.logger.WithFields(logrus.Fields{
+			"package":    "chainlisten",
+			"function":   "processReorged",
+			"action":     "get fulfill tx receipt",
+			"request_id": requestId,
+			"tx_hash":    request.GetFulfillTxHash(),
+		}).Error(err.Error())
+		return
+	}
+
+	if fulfillReceipt.Status == 1 {
+		// re-mined on the new chain. The RandomnessRequestFulfilled event will be picked
+		// up again by ProcessIncommingEvents, which was rewound to the common ancestor
+		d.logger.WithFields(logrus.Fields{
+			"package":    "chainlisten",
+			"function":   "processReorged",
+			"action":     "check fulfill tx status",
+			"request_id": requestId,
+			"block_num":  fulfillReceipt.BlockNumber.Uint64(),
+		}).Info("tx re-mined. wait for RandomnessRequestFulfilled event")
+		return
+	}
+
+	// re-mined but reverted, for example because the request block hash changed
+	d.updateFailedStatus(requestId, database.REQUEST_STATUS_TX_FAILED, "fulfill tx reverted after chain reorganisation")
+}
+
+// processReorgedRequest checks a request with no fulfillment Tx still exists after a reorg.
+// It's fulfilled again once the request Tx is re-mined. If the request Tx has gone from the
+// chain and the Tx pool, the request no longer exists, so it's abandoned.
+func (d *VORCoordinatorListener) processReorgedRequest(request database.RandomnessRequest) {
+	requestId := request.GetRequestId()
+	requestTxHash := common.HexToHash(request.GetRequestTxHash())
+
+	_, err := d.client.TransactionReceipt(context.Background(), requestTxHash)
+	if err == nil {
+		_ = d.service.Store.Db.UpdateRequestStatus(requestId, database.REQUEST_STATUS_INITIALISED, "request tx re-mined after chain reorganisation")
+		return
+	}
+
+	_, _, err = d.client.TransactionByHash(context.Background(), requestTxHash)
+	if err == nil {
+		// back in the Tx pool. Check again next cycle
+		d.logger.WithFields(logrus.Fields{
+			"package":    "chainlisten",
+			"function":   "processReorgedRequest",
+			"action":     "get request tx",
+			"request_id": requestId,
+			"tx_hash":    request.GetRequestTxHash(),
+		}).Info("request tx returned to tx pool by chain reorganisation - wait for it to be mined")
+		return
+	}
+	if !errors.Is(err, ethereum.NotFound) {
+		d.recordRPCError("eth_getTransactionByHash", err)
+		return
+	}
+
+	d.logger.WithFields(logrus.Fields{
+		"package":    "chainlisten",
+		"function":   "processReorgedRequest",
+		"action":     "get request tx",
+		"request_id": requestId,
+		"tx_hash":    request.GetRequestTxHash(),
+	}).Warning("request tx dropped by chain reorganisation - abandon request")
+	_ = d.service.Store.Db.UpdateRequestStatus(requestId, database.REQUEST_STATUS_FULFILMENT_FAILED, "request tx dropped by chain reorganisation")
+}
@@ -0,0 +1,180 @@
+package chainlisten
+
+import (
+	"errors"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/sirupsen/logrus"
+	"oracle/config"
+	"oracle/contracts/vor_coordinator"
+	"strings"
+	"time"
+)
+
+// subscriptionEnabled returns true if a websocket endpoint is configured for the chain. The
+// eth_ws_host value is often set to the same http address as eth_http_host, which can't be
+// subscribed to.
+func (d *VORCoordinatorListener) subscriptionEnabled() bool {
+	host := strings.ToLower(d.wsHost)
+	return strings.HasPrefix(host, "ws://") || strings.HasPrefix(host, "wss://")
+}
+
+// StartSubscribe listens for RandomnessRequest and RandomnessRequestFulfilled events over
+// the websocket endpoint, and checks the job queue each time a new block arrives. If the
+// socket can't be opened or drops, the listener falls back to polling until it can
+// resubscribe. Any blocks missed during the outage are back-filled before resubscribing.
+func (d *VORCoordinatorListener) StartSubscribe() error {
+	d.wg.Add(1)
+
+	d.logger.WithFields(logrus.Fields{
+		"package":    "chainlisten",
+		"function":   "StartSubscribe",
+		"action":     "begin subscription",
+		"from_block": d.query.FromBlock.Uint64(),
+		"ws_host":    d.wsHost,
+	}).Info()
+
+	var sleepTime = int32(30)
+	if config.Conf.CheckDuration != 0 {
+		sleepTime = config.Conf.CheckDuration
+	}
+
+	d.service.RecordHeartbeat()
+	for {
+		logsSub, logs, headsSub, heads, err := d.subscribe()
+		if err != nil {
+			d.recordRPCError("eth_subscribe", err)
+			d.logger.WithFields(logrus.Fields{
+				"package":  "chainlisten",
+				"function": "StartSubscribe",
+				"action":   "subscribe",
+			}).Warning("subscription unavailable, falling back to polling: " + err.Error())
+
+			_ = d.ProcessIncommingEvents()
+			_ = d.CheckJobs()
+			d.service.RecordHeartbeat()
+			time.Sleep(time.Duration(sleepTime) * time.Second)
+			continue
+		}
+
+		// back-fill anything emitted between the last processed block and the new subscription
+		err = d.ProcessIncommingEvents()
+		if err != nil {
+			d.logger.WithFields(logrus.Fields{
+				"package":  "chainlisten",
+				"function": "StartSubscribe",
+				"action":   "back-fill missed events",
+			}).Error(err.Error())
+		}
+		_ = d.CheckJobs()
+
+		err = d.watch(logsSub, logs, headsSub, heads)
+		logsSub.Unsubscribe()
+		headsSub.Unsubscribe()
+		if d.context.Err() != nil {
+			return err
+		}
+
+		d.logger.WithFields(logrus.Fields{
+			"package":    "chainlisten",
+			"function":   "StartSubscribe",
+			"action":     "watch subscription",
+			"from_block": d.query.FromBlock.Uint64(),
+		}).Warning("subscription dropped: " + err.Error())
+	}
+}
+
+func (d *VORCoordinatorListener) subscribe() (ethereum.Subscription, chan types.Log, ethereum.Subscription, chan *types.Header, error) {
+	if d.wsClient == nil {
+		wsClient, err := ethclient.DialContext(d.context, d.wsHost)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		d.wsClient = wsClient
+	}
+
+	logs := make(chan types.Log)
+	logsSub, err := d.wsClient.SubscribeFilterLogs(d.context, ethereum.FilterQuery{
+		Addresses: []common.Address{d.contractAddress},
+		Topics: [][]common.Hash{{
+			crypto.Keccak256Hash([]byte("RandomnessRequest(bytes32,uint256,address,uint256,bytes32)")),
+			crypto.Keccak256Hash([]byte("RandomnessRequestFulfilled(bytes32,uint256)")),
+		}},
+	}, logs)
+	if err != nil {
+		// force a fresh connection on the next attempt
+		d.closeWsClient()
+		return nil, nil, nil, nil, err
+	}
+
+	heads := make(chan *types.Header)
+	headsSub, err := d.wsClient.SubscribeNewHead(d.context, heads)
+	if err != nil {
+		logsSub.Unsubscribe()
+		d.closeWsClient()
+		return nil, nil, nil, nil, err
+	}
+
+	return logsSub, logs, headsSub, heads, nil
+}
+
+// watch processes subscribed events until either subscription fails.
+func (d *VORCoordinatorListener) watch(logsSub ethereum.Subscription, logs chan types.Log,
+	headsSub ethereum.Subscription, heads chan *types.Header) error {
+	contractAbi, err := abi.JSON(strings.NewReader(vor_coordinator.VorCoordinatorABI))
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case err = <-logsSub.Err():
+			d.closeWsClient()
+			return subscriptionError(err)
+		case err = <-headsSub.Err():
+			d.closeWsClient()
+			return subscriptionError(err)
+		case <-d.context.Done():
+			return d.context.Err()
+		case vLog := <-logs:
+			err = d.processEvent(contractAbi, vLog)
+			if err != nil {
+				d.logger.WithFields(logrus.Fields{
+					"package":   "chainlisten",
+					"function":  "watch",
+					"action":    "process event",
+					"block_num": vLog.BlockNumber,
+					"tx_hash":   vLog.TxHash.Hex(),
+				}).Error(err.Error())
+			}
+		case head := <-heads:
+			d.trackHead(head)
+			// a head can arrive before its logs, so move the cursor via FilterLogs, which
+			// only advances past blocks whose logs have been fetched. After a reorg this
+			// also rescans from the common ancestor.
+			_ = d.ProcessIncommingEvents()
+			// request confirmations are counted in blocks, so check the queue on every new head
+			_ = d.CheckJobs()
+			d.service.RecordHeartbeat()
+		}
+	}
+}
+
+// subscriptionError covers a subscription Err channel being closed without an error
+func subscriptionError(err error) error {
+	if err == nil {
+		return errors.New("subscription closed")
+	}
+	return err
+}
+
+func (d *VORCoordinatorListener) closeWsClient() {
+	if d.wsClient != nil {
+		d.wsClient.Close()
+		d.wsClient = nil
+	}
+}
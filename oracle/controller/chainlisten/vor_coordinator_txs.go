@@ -0,0 +1,36 @@
+package chainlisten
+
+import (
+	"github.com/sirupsen/logrus"
+	"oracle/models/database"
+)
+
+// trackTxs follows the oracle's unconfirmed Txs until they're confirmed, rebroadcasting any
+// the node has dropped
+func (d *VORCoordinatorListener) trackTxs(headBlockNum uint64) {
+	txs, err := d.service.TrackTxs(d.context, headBlockNum)
+	for _, tx := range txs {
+		entry := d.logger.WithFields(logrus.Fields{
+			"package":  "chainlisten",
+			"function": "trackTxs",
+			"action":   "tx " + tx.GetState(),
+			"tx":       tx.GetTxHash(),
+			"from":     tx.GetFromAddress(),
+			"nonce":    tx.GetNonce(),
+			"purpose":  tx.GetPurpose(),
+		})
+		if tx.GetState() == database.ETH_TX_STATE_REVERTED {
+			entry.Warn()
+		} else {
+			entry.Info()
+		}
+	}
+	if err != nil {
+		d.recordRPCError("eth_getTransactionReceipt", err)
+		d.logger.WithFields(logrus.Fields{
+			"package":  "chainlisten",
+			"function": "trackTxs",
+			"action":   "track txs",
+		}).Error(err.Error())
+	}
+}
@@ -7,11 +7,11 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"math/big"
-	"oracle/config"
+	"oracle/chaincall"
 	"oracle/contracts/vor_coordinator"
 	"oracle/contracts/vor_randomness_request_mock"
+	"oracle/ethrpc"
 	"oracle/models/database"
 	"oracle/service"
 	"oracle/tools/vor"
@@ -21,7 +21,7 @@ import (
 
 type VORRandomnessRequestMockListener struct {
 	contractAddress common.Address
-	client          *ethclient.Client
+	client          ethrpc.Backend
 	instance        *vor_randomness_request_mock.VorRandomnessRequestMock
 	query           ethereum.FilterQuery
 	wg              *sync.WaitGroup
@@ -29,11 +29,8 @@ type VORRandomnessRequestMockListener struct {
 	context         context.Context
 }
 
-func NewVORRandomnessRequestMockListener(contractHexAddress string, ethHostAddress string, service *service.Service, ctx context.Context) (*VORRandomnessRequestMockListener, error) {
-	client, err := ethclient.Dial(ethHostAddress)
-	if err != nil {
-		return nil, err
-	}
+func NewVORRandomnessRequestMockListener(conn *chaincall.Connection, contractHexAddress string, service *service.Service, ctx context.Context) (*VORRandomnessRequestMockListener, error) {
+	client := conn.Backend
 	contractAddress := common.HexToAddress(contractHexAddress)
 	instance, err := vor_randomness_request_mock.NewVorRandomnessRequestMock(contractAddress, client)
 	if err != nil {
@@ -42,12 +39,12 @@ func NewVORRandomnessRequestMockListener(contractHexAddress string, ethHostAddre
 
 	var lastBlock *big.Int
 	lastRequest, err := service.Store.Db.GetLast()
-	if blockNumber, _ := service.Store.Keystorage.GetBlockNumber(); blockNumber != 0 {
-		lastBlock = big.NewInt(blockNumber)
+	if cursor, _ := service.Store.Db.GetListenerCursor(contractAddress.Hex(), conn.ChainID.Int64()); cursor.GetBlockNumber() != 0 {
+		lastBlock = new(big.Int).SetUint64(cursor.GetBlockNumber())
 	} else if lastRequest.GetRequestBlockNumber() != 0 {
 		lastBlock = big.NewInt(int64(lastRequest.GetRequestBlockNumber()))
-	} else if config.Conf.FirstBlockNumber != 0 {
-		lastBlock = big.NewInt(int64(config.Conf.FirstBlockNumber))
+	} else if conn.Chain.FirstBlockNumber != 0 {
+		lastBlock = big.NewInt(int64(conn.Chain.FirstBlockNumber))
 	} else {
 		lastBlock = big.NewInt(1)
 	}
@@ -73,7 +70,7 @@ func (d VORRandomnessRequestMockListener) StartPoll() {
 
 func (d *VORRandomnessRequestMockListener) SetLastBlockNumber(blockNumber uint64) (err error) {
 	d.query.FromBlock = big.NewInt(int64(blockNumber))
-	err = d.service.Store.Keystorage.SetBlockNumber(int64(blockNumber))
+	err = d.service.Store.Db.SetListenerCursor(d.contractAddress.Hex(), d.service.ChainID(), blockNumber)
 	return
 }
 
@@ -134,7 +131,12 @@ func (d *VORRandomnessRequestMockListener) Request() error {
 			byteSeed, err := vor.BigToSeed(event.Seed)
 
 			var status int
-			fulfilTx, err := d.service.FulfillRandomness(byteSeed, vLog.BlockHash, vLog.BlockNumber)
+			key, ok := d.service.KeyByHash(event.KeyHash)
+			if !ok {
+				fmt.Println("proving key is not served by this oracle")
+				continue
+			}
+			fulfilTx, err := d.service.FulfillRandomness(key, byteSeed, vLog.BlockHash, vLog.BlockNumber)
 			fmt.Println(fulfilTx)
 			if err != nil {
 				fmt.Println(err)
@@ -149,6 +151,8 @@ func (d *VORRandomnessRequestMockListener) Request() error {
 				common.Bytes2Hex(event.RequestID[:]),
 				status,
 				vLog.TxHash.Hex(),
+				vLog.BlockHash.Hex(),
+				vLog.BlockNumber,
 				gasUsed,
 				gasPrice,
 				event.Fee.Uint64(),
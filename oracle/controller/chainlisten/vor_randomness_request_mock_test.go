@@ -2,6 +2,7 @@ package chainlisten_test
 
 import (
 	"context"
+	"oracle/chaincall"
 	"oracle/controller/chainlisten"
 	"oracle/service"
 	"os"
@@ -20,8 +21,8 @@ func InitVORRandomnessRequestMockListener(configAddres string) (err error) {
 	return err
 }
 
-func VORRandomnessRequestMockListenerCallerTestValues() (string, string, *service.Service, context.Context) {
-	return Config.MockContractAddress, Config.EthHTTPHost, Service, context.Background()
+func VORRandomnessRequestMockListenerCallerTestValues() (*chaincall.Connection, string, *service.Service, context.Context) {
+	return Service.Connection, Config.MockContractAddress, Service, context.Background()
 }
 
 func TestVORRandomnessRequestMockListener_Request(t *testing.T) {
@@ -0,0 +1,306 @@
+package ethrpc
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"math/big"
+	"oracle/config"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTimeout             = 10
+	defaultHealthCheckInterval = 15
+	defaultMaxHeadLag          = 5
+)
+
+// ChainIDMismatchError is recorded against an endpoint which is on a different chain to
+// the configured network_id
+type ChainIDMismatchError struct {
+	URL      string
+	Expected *big.Int
+	Actual   *big.Int
+}
+
+func (e *ChainIDMismatchError) Error() string {
+	return fmt.Sprintf("%s is on chain %s, expected %s", e.URL, e.Actual, e.Expected)
+}
+
+// Client is an Eth client backed by pools of RPC endpoints. Reads go to the read pool, and
+// Tx broadcasts and pending nonce lookups to the broadcast pool, which is the read pool
+// unless configured separately. Each call goes to the healthiest endpoint that is in sync
+// with the others, and fails over to the next on connection errors and timeouts.
+//
+// Client implements bind.ContractBackend, so it can be used for contract bindings.
+type Client struct {
+	read      *pool
+	broadcast *pool
+	chainID   *big.Int
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+// Dial creates a Client for a chain, using the timeouts in config.Conf. Txs are broadcast
+// via the read endpoints if broadcastHosts is empty. The endpoints are health checked before
+// returning, and then in the background until Close.
+func Dial(readHosts []string, broadcastHosts []string, chainID *big.Int) (*Client, error) {
+	timeout := time.Duration(defaultTimeout) * time.Second
+	if config.Conf.RPCTimeout != 0 {
+		timeout = time.Duration(config.Conf.RPCTimeout) * time.Second
+	}
+	interval := time.Duration(defaultHealthCheckInterval) * time.Second
+	if config.Conf.RPCHealthCheckInterval != 0 {
+		interval = time.Duration(config.Conf.RPCHealthCheckInterval) * time.Second
+	}
+	maxLag := uint64(defaultMaxHeadLag)
+	if config.Conf.RPCMaxHeadLag != 0 {
+		maxLag = config.Conf.RPCMaxHeadLag
+	}
+	if len(broadcastHosts) == 0 {
+		broadcastHosts = readHosts
+	}
+	return DialPools(readHosts, broadcastHosts, chainID, timeout, interval, maxLag)
+}
+
+// DialPools creates a Client with the given read and broadcast endpoints. Endpoints not on
+// chainID are excluded, unless chainID is nil or zero.
+func DialPools(readHosts []string, broadcastHosts []string, chainID *big.Int,
+	timeout time.Duration, healthCheckInterval time.Duration, maxLag uint64) (*Client, error) {
+	read, err := newPool(readHosts, timeout, maxLag)
+	if err != nil {
+		return nil, err
+	}
+	broadcast := read
+	if !sameHosts(readHosts, broadcastHosts) {
+		broadcast, err = newPool(broadcastHosts, timeout, maxLag)
+		if err != nil {
+			read.close()
+			return nil, err
+		}
+	}
+
+	c := &Client{
+		read:      read,
+		broadcast: broadcast,
+		chainID:   chainID,
+		stop:      make(chan struct{}),
+	}
+	c.healthCheck()
+	if healthCheckInterval > 0 {
+		go c.monitor(healthCheckInterval)
+	}
+	return c, nil
+}
+
+func sameHosts(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Client) healthCheck() {
+	c.read.healthCheck(context.Background(), c.chainID)
+	if c.broadcast != c.read {
+		c.broadcast.healthCheck(context.Background(), c.chainID)
+	}
+}
+
+func (c *Client) monitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.healthCheck()
+		}
+	}
+}
+
+// Close stops the health checks and closes the endpoint connections
+func (c *Client) Close() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+		c.read.close()
+		if c.broadcast != c.read {
+			c.broadcast.close()
+		}
+	})
+}
+
+// ReadEndpoints returns the health of each read endpoint
+func (c *Client) ReadEndpoints() []EndpointStatus {
+	return endpointStatuses(c.read)
+}
+
+// BroadcastEndpoints returns the health of each broadcast endpoint
+func (c *Client) BroadcastEndpoints() []EndpointStatus {
+	return endpointStatuses(c.broadcast)
+}
+
+func endpointStatuses(p *pool) []EndpointStatus {
+	statuses := make([]EndpointStatus, len(p.endpoints))
+	for i, e := range p.endpoints {
+		statuses[i] = e.status()
+	}
+	return statuses
+}
+
+func (c *Client) ChainID(ctx context.Context) (id *big.Int, err error) {
+	err = c.read.do(ctx, func(ctx context.Context, client *ethclient.Client) (err error) {
+		id, err = client.ChainID(ctx)
+		return
+	})
+	return
+}
+
+func (c *Client) BlockNumber(ctx context.Context) (num uint64, err error) {
+	err = c.read.do(ctx, func(ctx context.Context, client *ethclient.Client) (err error) {
+		num, err = client.BlockNumber(ctx)
+		return
+	})
+	return
+}
+
+func (c *Client) HeaderByNumber(ctx context.Context, number *big.Int) (header *types.Header, err error) {
+	err = c.read.do(ctx, func(ctx context.Context, client *ethclient.Client) (err error) {
+		header, err = client.HeaderByNumber(ctx, number)
+		return
+	})
+	return
+}
+
+func (c *Client) HeaderByHash(ctx context.Context, hash common.Hash) (header *types.Header, err error) {
+	err = c.read.do(ctx, func(ctx context.Context, client *ethclient.Client) (err error) {
+		header, err = client.HeaderByHash(ctx, hash)
+		return
+	})
+	return
+}
+
+func (c *Client) TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error) {
+	err = c.read.do(ctx, func(ctx context.Context, client *ethclient.Client) (err error) {
+		tx, isPending, err = client.TransactionByHash(ctx, hash)
+		return
+	})
+	return
+}
+
+func (c *Client) TransactionReceipt(ctx context.Context, txHash common.Hash) (receipt *types.Receipt, err error) {
+	err = c.read.do(ctx, func(ctx context.Context, client *ethclient.Client) (err error) {
+		receipt, err = client.TransactionReceipt(ctx, txHash)
+		return
+	})
+	return
+}
+
+func (c *Client) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (balance *big.Int, err error) {
+	err = c.read.do(ctx, func(ctx context.Context, client *ethclient.Client) (err error) {
+		balance, err = client.BalanceAt(ctx, account, blockNumber)
+		return
+	})
+	return
+}
+
+func (c *Client) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (nonce uint64, err error) {
+	err = c.read.do(ctx, func(ctx context.Context, client *ethclient.Client) (err error) {
+		nonce, err = client.NonceAt(ctx, account, blockNumber)
+		return
+	})
+	return
+}
+
+func (c *Client) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) (code []byte, err error) {
+	err = c.read.do(ctx, func(ctx context.Context, client *ethclient.Client) (err error) {
+		code, err = client.CodeAt(ctx, account, blockNumber)
+		return
+	})
+	return
+}
+
+func (c *Client) PendingCodeAt(ctx context.Context, account common.Address) (code []byte, err error) {
+	err = c.read.do(ctx, func(ctx context.Context, client *ethclient.Client) (err error) {
+		code, err = client.PendingCodeAt(ctx, account)
+		return
+	})
+	return
+}
+
+func (c *Client) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) (result []byte, err error) {
+	err = c.read.do(ctx, func(ctx context.Context, client *ethclient.Client) (err error) {
+		result, err = client.CallContract(ctx, msg, blockNumber)
+		return
+	})
+	return
+}
+
+func (c *Client) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (gas uint64, err error) {
+	err = c.read.do(ctx, func(ctx context.Context, client *ethclient.Client) (err error) {
+		gas, err = client.EstimateGas(ctx, msg)
+		return
+	})
+	return
+}
+
+func (c *Client) SuggestGasPrice(ctx context.Context) (price *big.Int, err error) {
+	err = c.read.do(ctx, func(ctx context.Context, client *ethclient.Client) (err error) {
+		price, err = client.SuggestGasPrice(ctx)
+		return
+	})
+	return
+}
+
+func (c *Client) SuggestGasTipCap(ctx context.Context) (tip *big.Int, err error) {
+	err = c.read.do(ctx, func(ctx context.Context, client *ethclient.Client) (err error) {
+		tip, err = client.SuggestGasTipCap(ctx)
+		return
+	})
+	return
+}
+
+func (c *Client) FilterLogs(ctx context.Context, q ethereum.FilterQuery) (logs []types.Log, err error) {
+	err = c.read.do(ctx, func(ctx context.Context, client *ethclient.Client) (err error) {
+		logs, err = client.FilterLogs(ctx, q)
+		return
+	})
+	return
+}
+
+// SubscribeFilterLogs is only supported by websocket endpoints. The listener subscribes
+// using eth_ws_host, so this is only here to satisfy bind.ContractBackend.
+func (c *Client) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (sub ethereum.Subscription, err error) {
+	err = c.read.do(ctx, func(_ context.Context, client *ethclient.Client) (err error) {
+		// the subscription lives beyond the call, so it can't use the call's timeout
+		sub, err = client.SubscribeFilterLogs(ctx, q, ch)
+		return
+	})
+	return
+}
+
+// PendingNonceAt reads from the broadcast pool, since that is where the account's pending
+// Txs were sent
+func (c *Client) PendingNonceAt(ctx context.Context, account common.Address) (nonce uint64, err error) {
+	err = c.broadcast.do(ctx, func(ctx context.Context, client *ethclient.Client) (err error) {
+		nonce, err = client.PendingNonceAt(ctx, account)
+		return
+	})
+	return
+}
+
+func (c *Client) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return c.broadcast.do(ctx, func(ctx context.Context, client *ethclient.Client) error {
+		return client.SendTransaction(ctx, tx)
+	})
+}
@@ -0,0 +1,181 @@
+package ethrpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"oracle/ethrpc"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeNode is a minimal JSON-RPC server, answering eth_chainId and eth_blockNumber
+type fakeNode struct {
+	mu      sync.Mutex
+	chainID uint64
+	head    uint64
+	down    bool
+	calls   int
+	server  *httptest.Server
+}
+
+func newFakeNode(t *testing.T, chainID uint64, head uint64) *fakeNode {
+	n := &fakeNode{chainID: chainID, head: head}
+	n.server = httptest.NewServer(http.HandlerFunc(n.handle))
+	t.Cleanup(n.server.Close)
+	return n
+}
+
+func (n *fakeNode) handle(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.down {
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	n.calls++
+
+	var result interface{}
+	switch req.Method {
+	case "eth_chainId":
+		result = fmt.Sprintf("0x%x", n.chainID)
+	case "eth_blockNumber":
+		result = fmt.Sprintf("0x%x", n.head)
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32601,"message":"method not found"}}`, req.ID)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+}
+
+func (n *fakeNode) setDown(down bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.down = down
+}
+
+func (n *fakeNode) callCount() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.calls
+}
+
+func (n *fakeNode) resetCalls() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.calls = 0
+}
+
+func dial(t *testing.T, read []*fakeNode, broadcast []*fakeNode) *ethrpc.Client {
+	var readHosts, broadcastHosts []string
+	for _, n := range read {
+		readHosts = append(readHosts, n.server.URL)
+	}
+	for _, n := range broadcast {
+		broadcastHosts = append(broadcastHosts, n.server.URL)
+	}
+	if broadcastHosts == nil {
+		broadcastHosts = readHosts
+	}
+	client, err := ethrpc.DialPools(readHosts, broadcastHosts, big.NewInt(696969), time.Second, 0, 2)
+	assert.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestClient_FailsOver(t *testing.T) {
+	primary := newFakeNode(t, 696969, 100)
+	backup := newFakeNode(t, 696969, 100)
+	client := dial(t, []*fakeNode{primary, backup}, nil)
+
+	primary.setDown(true)
+	head, err := client.BlockNumber(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), head)
+
+	// the failed endpoint's score dropped, so the backup is now preferred
+	statuses := client.ReadEndpoints()
+	assert.Less(t, statuses[0].Score, statuses[1].Score)
+	assert.NotEmpty(t, statuses[0].LastError)
+
+	primary.setDown(false)
+	primary.resetCalls()
+	_, err = client.BlockNumber(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, primary.callCount())
+}
+
+func TestClient_AllEndpointsDown(t *testing.T) {
+	primary := newFakeNode(t, 696969, 100)
+	backup := newFakeNode(t, 696969, 100)
+	client := dial(t, []*fakeNode{primary, backup}, nil)
+
+	primary.setDown(true)
+	backup.setDown(true)
+	_, err := client.BlockNumber(context.Background())
+	assert.Error(t, err)
+}
+
+func TestClient_NoFailoverOnRPCError(t *testing.T) {
+	primary := newFakeNode(t, 696969, 100)
+	backup := newFakeNode(t, 696969, 100)
+	client := dial(t, []*fakeNode{primary, backup}, nil)
+	backup.resetCalls()
+
+	// the node answered - another endpoint would give the same answer
+	_, err := client.SuggestGasPrice(context.Background())
+	assert.EqualError(t, err, "method not found")
+	assert.Equal(t, 0, backup.callCount())
+}
+
+func TestClient_AvoidsLaggingEndpoint(t *testing.T) {
+	lagging := newFakeNode(t, 696969, 90)
+	synced := newFakeNode(t, 696969, 100)
+	client := dial(t, []*fakeNode{lagging, synced}, nil)
+
+	lagging.resetCalls()
+	head, err := client.BlockNumber(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), head)
+	assert.Equal(t, 0, lagging.callCount())
+}
+
+func TestClient_ExcludesWrongChain(t *testing.T) {
+	wrongChain := newFakeNode(t, 1, 200)
+	rightChain := newFakeNode(t, 696969, 100)
+	client := dial(t, []*fakeNode{wrongChain, rightChain}, nil)
+
+	chainID, err := client.ChainID(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(696969), chainID)
+	assert.Equal(t, 0, client.ReadEndpoints()[0].Score)
+}
+
+func TestClient_SeparateBroadcastPool(t *testing.T) {
+	reader := newFakeNode(t, 696969, 100)
+	broadcaster := newFakeNode(t, 696969, 100)
+	client := dial(t, []*fakeNode{reader}, []*fakeNode{broadcaster})
+
+	assert.Len(t, client.ReadEndpoints(), 1)
+	assert.Len(t, client.BroadcastEndpoints(), 1)
+	assert.Equal(t, broadcaster.server.URL, client.BroadcastEndpoints()[0].URL)
+
+	reader.resetCalls()
+	broadcaster.resetCalls()
+	_, _ = client.BlockNumber(context.Background())
+	assert.Equal(t, 1, reader.callCount())
+	assert.Equal(t, 0, broadcaster.callCount())
+}
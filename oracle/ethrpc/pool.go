@@ -0,0 +1,235 @@
+package ethrpc
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	maxScore = 100
+	// score regained with each successful call or health check
+	scoreRecovery = 10
+)
+
+// endpoint is a single RPC provider. Its score drops by half with each failed call or health
+// check, and recovers with each success, so a flapping endpoint is tried after stable ones.
+type endpoint struct {
+	url    string
+	client *ethclient.Client
+
+	mu        sync.Mutex
+	score     int
+	head      uint64
+	lastError error
+}
+
+func (e *endpoint) success() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.score += scoreRecovery
+	if e.score > maxScore {
+		e.score = maxScore
+	}
+	e.lastError = nil
+}
+
+func (e *endpoint) failure(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.score = e.score / 2
+	e.lastError = err
+}
+
+func (e *endpoint) setHead(head uint64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.head = head
+}
+
+// disable excludes the endpoint until it next passes a health check
+func (e *endpoint) disable(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.score = 0
+	e.lastError = err
+}
+
+func (e *endpoint) status() EndpointStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	status := EndpointStatus{
+		URL:   e.url,
+		Score: e.score,
+		Head:  e.head,
+	}
+	if e.lastError != nil {
+		status.LastError = e.lastError.Error()
+	}
+	return status
+}
+
+// EndpointStatus is a snapshot of an endpoint's health
+type EndpointStatus struct {
+	URL       string
+	Score     int
+	Head      uint64
+	LastError string
+}
+
+// pool is a set of interchangeable endpoints. Calls go to the best endpoint first, and
+// fail over to the next on connection errors and timeouts.
+type pool struct {
+	endpoints []*endpoint
+	timeout   time.Duration
+	maxLag    uint64
+}
+
+func newPool(urls []string, timeout time.Duration, maxLag uint64) (*pool, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("no RPC endpoints configured")
+	}
+	p := &pool{timeout: timeout, maxLag: maxLag}
+	for _, url := range urls {
+		client, err := ethclient.Dial(url)
+		if err != nil {
+			return nil, err
+		}
+		p.endpoints = append(p.endpoints, &endpoint{url: url, client: client, score: maxScore})
+	}
+	return p, nil
+}
+
+// ordered returns the endpoints in the order they should be tried. Endpoints within maxLag
+// blocks of the highest head seen come first, by score. Lagging endpoints follow, then any
+// with a score of zero, which are still tried as a last resort.
+func (p *pool) ordered() []*endpoint {
+	statuses := make([]EndpointStatus, len(p.endpoints))
+	var bestHead uint64
+	for i, e := range p.endpoints {
+		statuses[i] = e.status()
+		if statuses[i].Score > 0 && statuses[i].Head > bestHead {
+			bestHead = statuses[i].Head
+		}
+	}
+
+	rank := func(s EndpointStatus) int {
+		switch {
+		case s.Score == 0:
+			return 2
+		case s.Head+p.maxLag < bestHead:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	indexes := make([]int, len(p.endpoints))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(a, b int) bool {
+		sa, sb := statuses[indexes[a]], statuses[indexes[b]]
+		if rank(sa) != rank(sb) {
+			return rank(sa) < rank(sb)
+		}
+		return sa.Score > sb.Score
+	})
+
+	ordered := make([]*endpoint, len(indexes))
+	for i, index := range indexes {
+		ordered[i] = p.endpoints[index]
+	}
+	return ordered
+}
+
+// do runs the call against each endpoint in turn until one responds. An error returned by
+// a node which did respond, such as a revert, is returned straight away.
+func (p *pool) do(ctx context.Context, call func(ctx context.Context, client *ethclient.Client) error) error {
+	var err error
+	for _, e := range p.ordered() {
+		callCtx, cancel := context.WithTimeout(ctx, p.timeout)
+		err = call(callCtx, e.client)
+		cancel()
+
+		if err == nil || !isFailoverError(err) {
+			e.success()
+			return err
+		}
+		e.failure(err)
+		if ctx.Err() != nil {
+			// the caller gave up, rather than the endpoint timing out
+			return err
+		}
+	}
+	return err
+}
+
+// healthCheck refreshes each endpoint's head block. An endpoint on the wrong chain is
+// disabled until its next check.
+func (p *pool) healthCheck(ctx context.Context, chainID *big.Int) {
+	var wg sync.WaitGroup
+	for _, e := range p.endpoints {
+		wg.Add(1)
+		go func(e *endpoint) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, p.timeout)
+			defer cancel()
+
+			if chainID != nil && chainID.Sign() > 0 {
+				id, err := e.client.ChainID(checkCtx)
+				if err != nil {
+					e.failure(err)
+					return
+				}
+				if id.Cmp(chainID) != 0 {
+					e.disable(&ChainIDMismatchError{URL: e.url, Expected: chainID, Actual: id})
+					return
+				}
+			}
+			head, err := e.client.BlockNumber(checkCtx)
+			if err != nil {
+				e.failure(err)
+				return
+			}
+			e.setHead(head)
+			e.success()
+		}(e)
+	}
+	wg.Wait()
+}
+
+func (p *pool) close() {
+	for _, e := range p.endpoints {
+		e.client.Close()
+	}
+}
+
+// JSON-RPC error code providers use for rate limiting
+const limitExceededCode = -32005
+
+// IsTransportError returns true if err means a call may not have reached a node, or the node
+// didn't answer it, rather than the node answering with an error
+func IsTransportError(err error) bool {
+	return isFailoverError(err)
+}
+
+// isFailoverError returns true for errors which mean the endpoint didn't handle the call -
+// connection failures, timeouts, HTTP errors and rate limiting. Other JSON-RPC errors, such
+// as reverts and nonce errors, are the node's answer and would be the same from any endpoint.
+func isFailoverError(err error) bool {
+	if errors.Is(err, ethereum.NotFound) {
+		return false
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr.ErrorCode() == limitExceededCode
+	}
+	return true
+}
@@ -0,0 +1,31 @@
+package ethrpc
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/params"
+	"math/big"
+)
+
+// SimulatedClient adapts a SimulatedBackend to the Backend interface, adding the calls it
+// doesn't implement
+type SimulatedClient struct {
+	*backends.SimulatedBackend
+}
+
+func NewSimulatedClient(backend *backends.SimulatedBackend) *SimulatedClient {
+	return &SimulatedClient{backend}
+}
+
+// ChainID returns the chain ID the SimulatedBackend signs with
+func (s *SimulatedClient) ChainID(_ context.Context) (*big.Int, error) {
+	return new(big.Int).Set(params.AllEthashProtocolChanges.ChainID), nil
+}
+
+func (s *SimulatedClient) BlockNumber(ctx context.Context) (uint64, error) {
+	header, err := s.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	return header.Number.Uint64(), nil
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
 	"oracle/store/keystorage"
 	"strconv"
 )
@@ -26,7 +27,7 @@ func noKeyFound(keystorage *keystorage.Keystorage) (fee int64, err error) {
 	}
 	fmt.Println("")
 	fmt.Println("Do you want to add an existing private key or generate a new one?")
-	fmt.Print("[ 1-add existing; 2-generate new ]:	")
+	fmt.Print("[ 1-add existing; 2-generate new; 3-import V3 keystore file ]:	")
 	var addgenerate string
 	fmt.Scanf("%s\n", &addgenerate)
 
@@ -63,6 +64,31 @@ func noKeyFound(keystorage *keystorage.Keystorage) (fee int64, err error) {
 		fmt.Println(token)
 		fmt.Println("\nUse this key to login via cli/HTTP (command: oracle-cli settings)")
 		fmt.Println("KEEP THIS KEYS SAFE!")
+	case "3":
+		token, err = keystorage.GenerateToken()
+		if err != nil {
+			return
+		}
+		fmt.Println("")
+		fmt.Print("Path to the V3 keystore file: ")
+		var keyFile string
+		fmt.Scanf("%s\n", &keyFile)
+		var keyJSON []byte
+		keyJSON, err = ioutil.ReadFile(keyFile)
+		if err != nil {
+			return
+		}
+		var address string
+		address, err = keystorage.ImportV3(addusername, keyJSON, inputPassphrase(), false)
+		if err != nil {
+			return
+		}
+		fmt.Println("\nSuccessfully imported the key for", address)
+		fmt.Print("Your daemon api key:   ")
+		fmt.Println(token)
+		fmt.Println("\nUse this key to login via cli/HTTP (command: oracle-cli settings)")
+		fmt.Println("KEEP THIS KEY SAFE! YOU WILL LOSE YOUR KEYSTORAGE DATA WITHOUT IT!")
+		fmt.Println("")
 	default:
 		fmt.Println("Sorry, I can't understand you =(")
 		fee, err = noKeyFound(keystorage)
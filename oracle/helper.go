@@ -8,7 +8,7 @@ import (
 	"time"
 )
 
-var oracleListener *chainlisten.VORCoordinatorListener
+var oracleListeners []*chainlisten.VORCoordinatorListener
 
 func Stop() (err error) {
 	if stop1 {
@@ -36,6 +36,8 @@ func Stop() (err error) {
 		}).Error()
 		err = e.Close()
 	}
-	oracleListener.Shutdown()
+	for _, oracleListener := range oracleListeners {
+		oracleListener.Shutdown()
+	}
 	return err
 }
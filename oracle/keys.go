@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"oracle/config"
+	"oracle/store/keystorage"
+	"os"
+)
+
+// keys imports and exports V3 keystore files without starting the oracle:
+//
+//	oracle keys import --file key.json --account name [--sending]
+//	oracle keys export --account name --file key.json
+//
+// The oracle rewrites the keystore from memory, so stop it first, or use the /keys API
+// calls while it's running.
+func keys() error {
+	if len(os.Args) < 3 || (os.Args[2] != "import" && os.Args[2] != "export") {
+		return errors.New("keys requires import or export")
+	}
+	if options.Account == "" || options.File == "" {
+		return errors.New("keys requires --account and --file")
+	}
+
+	keystore, err := keystorage.NewKeyStorage(log, config.Conf.Keystorage.File)
+	if err != nil {
+		return err
+	}
+	if keystore.KeyStore.GetHash() == "" {
+		return errors.New("the keystore hasn't been set up yet. Run oracle start first")
+	}
+	err = unlock(keystore)
+	if err != nil {
+		return err
+	}
+
+	passphrase := ""
+	if options.Passphrase != "" {
+		passphrase = getPasswordFromFileOrFlag(options.Passphrase)
+	} else {
+		passphrase = inputPassphrase()
+	}
+
+	switch os.Args[2] {
+	case "import":
+		keyJSON, err := ioutil.ReadFile(options.File)
+		if err != nil {
+			return err
+		}
+		address, err := keystore.ImportV3(options.Account, keyJSON, passphrase, options.Sending)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Imported", options.Account, address)
+		if !options.Sending {
+			fmt.Println("Register it with: oraclecli register --stored")
+		}
+	case "export":
+		if _, err := os.Stat(options.File); err == nil {
+			return fmt.Errorf("%s already exists", options.File)
+		}
+		keyJSON, err := keystore.ExportV3(options.Account, passphrase)
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(options.File, keyJSON, 0600)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Exported", options.Account, "to", options.File)
+	}
+	return nil
+}
+
+func inputPassphrase() string {
+	fmt.Println("")
+	fmt.Print("V3 keystore passphrase: ")
+	var passphrase string
+	fmt.Scanf("%s\n", &passphrase)
+	return passphrase
+}
@@ -26,6 +26,12 @@ var options struct {
 	Config       string `short:"c" long:"config" description:"Config path" required:"false" default:"./config.json"`
 	PasswordFile string `short:"k" long:"key" description:"Path to file containing decryption key, or the key itself" required:"false"`
 	Version      bool   `short:"v" long:"version" description:"Show version information and exit" required:"false"`
+	Proof        string `long:"proof" description:"verify: fulfillment proof hex, as sent to the VORCoordinator" required:"false"`
+	BlockHash    string `long:"block-hash" description:"verify: hash of the block the request was made in" required:"false"`
+	Account      string `long:"account" description:"keys: account name to import as, or to export" required:"false"`
+	File         string `long:"file" description:"keys: V3 keystore file to import, or to export to" required:"false"`
+	Passphrase   string `long:"passphrase" description:"keys: path to file containing the V3 keystore passphrase, or the passphrase itself" required:"false"`
+	Sending      bool   `long:"sending" description:"keys: import as a sending account, rather than a proving key" required:"false"`
 }
 
 var parser = flags.NewParser(&options, flags.Default)
@@ -61,6 +67,16 @@ func main() {
 		err = Stop()
 	}()
 
+	// offline proof verification doesn't need a config
+	if len(os.Args) > 1 && os.Args[1] == "verify" {
+		err = verify()
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
 	fmt.Println(options.Config)
 	config.Conf, err = config.NewConfig(options.Config)
 	if err != nil {
@@ -81,6 +97,20 @@ func main() {
 			"function": "main",
 		}).Info(vers.StringLine())
 		err = start()
+	case "keys":
+		err = keys()
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		return
+	case "rotate-token":
+		err = rotateToken()
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		return
 	default:
 		log.WithFields(logrus.Fields{
 			"package":  "main",
@@ -0,0 +1,173 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"oracle/models/database"
+	"strconv"
+)
+
+const namespace = "vor_oracle"
+
+var (
+	RequestsSeen = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "requests_seen_total",
+		Help:      "RandomnessRequest events addressed to the oracle's keys, by chain and keystore account",
+	}, []string{"chain", "account"})
+	RequestsFulfilled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "requests_fulfilled_total",
+		Help:      "RandomnessRequestFulfilled events confirmed for the oracle's requests, by chain and keystore account",
+	}, []string{"chain", "account"})
+	RequestsFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "requests_failed_total",
+		Help:      "Failed fulfillment attempts, by chain and the status the request was moved to",
+	}, []string{"chain", "status"})
+	FulfillmentLatencyBlocks = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "fulfillment_latency_blocks",
+		Help:      "Blocks between a request and its fulfillment, by chain",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 10),
+	}, []string{"chain"})
+	FulfillmentLatencySeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "fulfillment_latency_seconds",
+		Help:      "Seconds between a request being detected and its fulfillment being confirmed, by chain",
+		Buckets:   prometheus.ExponentialBuckets(5, 2, 10),
+	}, []string{"chain"})
+	FulfillmentGasUsed = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "fulfillment_gas_used",
+		Help:      "Gas used by fulfillment Txs, by chain",
+		Buckets:   prometheus.LinearBuckets(100000, 50000, 10),
+	}, []string{"chain"})
+	FulfillmentGasPrice = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "fulfillment_gas_price_gwei",
+		Help:      "Effective gas price paid by fulfillment Txs, in gwei, by chain",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 10),
+	}, []string{"chain"})
+	EthBalance = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "eth_balance",
+		Help:      "ETH balance of each of the oracle's wallets, by chain and keystore account",
+	}, []string{"chain", "account"})
+	WithdrawableTokens = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "withdrawable_xfund",
+		Help:      "xFUND fees held by the VORCoordinator for each of the oracle's keys, by chain and keystore account",
+	}, []string{"chain", "account"})
+	SendingBalance = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "sending_eth_balance",
+		Help:      "ETH balance of each sending account, by chain and address",
+	}, []string{"chain", "address"})
+	SendingPendingTxs = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "sending_pending_txs",
+		Help:      "Txs sent by each sending account which haven't been mined, by chain and address",
+	}, []string{"chain", "address"})
+	SendingTopUps = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sending_top_ups_total",
+		Help:      "ETH top ups sent from the treasury to each sending account, by chain and address",
+	}, []string{"chain", "address"})
+	LastProcessedBlock = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "last_processed_block",
+		Help:      "Last block scanned for events, by chain",
+	}, []string{"chain"})
+	HeadBlockLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "head_block_lag",
+		Help:      "Blocks between the chain head and the last block scanned for events, by chain",
+	}, []string{"chain"})
+	RPCErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rpc_errors_total",
+		Help:      "Failed calls to the Eth provider, by chain and RPC method",
+	}, []string{"chain", "method"})
+)
+
+// RequestStatusCounter is the subset of the DB API used to report request statuses
+type RequestStatusCounter interface {
+	CountRequestsByChainAndStatus() (map[int64]map[int]int64, error)
+}
+
+// requestStatusCollector reports the number of requests on each chain in each status, read
+// from the DB on each scrape so that it's correct across restarts.
+type requestStatusCollector struct {
+	db RequestStatusCounter
+	// chain label for each chain ID. Chains without one are labelled with their ID
+	chainNames map[int64]string
+	desc       *prometheus.Desc
+}
+
+func NewRequestStatusCollector(db RequestStatusCounter, chainNames map[int64]string) prometheus.Collector {
+	return &requestStatusCollector{
+		db:         db,
+		chainNames: chainNames,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "requests"),
+			"Requests in the oracle's DB, by chain and status",
+			[]string{"chain", "status"}, nil,
+		),
+	}
+}
+
+func (c *requestStatusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *requestStatusCollector) Collect(ch chan<- prometheus.Metric) {
+	counts, err := c.db.CountRequestsByChainAndStatus()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+	for chainId, statusCounts := range counts {
+		chain, ok := c.chainNames[chainId]
+		if !ok {
+			chain = strconv.FormatInt(chainId, 10)
+		}
+		for status, count := range statusCounts {
+			ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count), chain, StatusLabel(status))
+		}
+	}
+}
+
+// StatusLabel returns the metric label for a request status
+func StatusLabel(status int) string {
+	return database.RandomnessRequest{Status: status}.GetStatusString()
+}
+
+// Register registers the oracle's metrics with the default Prometheus registry. chainNames
+// labels the requests stored for each chain ID.
+func Register(db RequestStatusCounter, chainNames map[int64]string) {
+	prometheus.MustRegister(
+		RequestsSeen,
+		RequestsFulfilled,
+		RequestsFailed,
+		FulfillmentLatencyBlocks,
+		FulfillmentLatencySeconds,
+		FulfillmentGasUsed,
+		FulfillmentGasPrice,
+		EthBalance,
+		WithdrawableTokens,
+		SendingBalance,
+		SendingPendingTxs,
+		SendingTopUps,
+		LastProcessedBlock,
+		HeadBlockLag,
+		RPCErrors,
+		NewRequestStatusCollector(db, chainNames),
+	)
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,51 @@
+package metrics_test
+
+import (
+	"errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"oracle/metrics"
+	"oracle/models/database"
+	"strings"
+	"testing"
+)
+
+type statusCounts struct {
+	counts map[int64]map[int]int64
+	err    error
+}
+
+func (s statusCounts) CountRequestsByChainAndStatus() (map[int64]map[int]int64, error) {
+	return s.counts, s.err
+}
+
+func TestRequestStatusCollector(t *testing.T) {
+	collector := metrics.NewRequestStatusCollector(statusCounts{counts: map[int64]map[int]int64{
+		1: {
+			database.REQUEST_STATUS_SUCCESS:   12,
+			database.REQUEST_STATUS_TX_FAILED: 2,
+		},
+		137: {
+			database.REQUEST_STATUS_SUCCESS: 5,
+		},
+		// a chain no longer served
+		4: {
+			database.REQUEST_STATUS_SUCCESS: 1,
+		},
+	}}, map[int64]string{1: "mainnet", 137: "polygon"})
+
+	expected := `
+# HELP vor_oracle_requests Requests in the oracle's DB, by chain and status
+# TYPE vor_oracle_requests gauge
+vor_oracle_requests{chain="4",status="SUCCESS"} 1
+vor_oracle_requests{chain="mainnet",status="SUCCESS"} 12
+vor_oracle_requests{chain="mainnet",status="TX FAILED"} 2
+vor_oracle_requests{chain="polygon",status="SUCCESS"} 5
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
+
+func TestRequestStatusCollector_Error(t *testing.T) {
+	collector := metrics.NewRequestStatusCollector(statusCounts{err: errors.New("db closed")}, nil)
+	assert.Error(t, testutil.CollectAndCompare(collector, strings.NewReader("")))
+}
@@ -6,7 +6,9 @@ type SimValues struct {
 }
 
 type AnalyticsFilter struct {
+	Chain            string `json:"chain,omitempty"`
 	ConsumerContract string `json:"consumer_contract,omitempty"`
+	Account          string `json:"account,omitempty"`
 	Limit            int    `json:"limit,omitempty"`
 }
 
@@ -1,31 +1,104 @@
 package api
 
 import (
+	"encoding/json"
 	"github.com/ethereum/go-ethereum/core/types"
 	"time"
 )
 
 type OracleWithdrawRequestModel struct {
+	Chain   string `json:"chain"`
+	Account string `json:"account"`
 	Address string `json:"address"`
 	Amount  int64  `json:"amount"`
 }
 
 type OracleRegisterRequestModel struct {
+	Chain       string `json:"chain"`
 	AccountName string `json:"account_name"`
 	PrivateKey  string `json:"private_key"`
 	Fee         int64  `json:"fee"`
 }
 
+// OracleAddSendingRequestModel adds a sending account. A new key is generated if the
+// private key is empty.
+type OracleAddSendingRequestModel struct {
+	AccountName string `json:"account_name"`
+	PrivateKey  string `json:"private_key"`
+}
+
+type OracleAddSendingResponseModel struct {
+	AccountName string `json:"account_name"`
+	Address     string `json:"address"`
+}
+
+// OracleImportKeyRequestModel imports the key in a V3 keystore file, as a proving key or a
+// sending account
+type OracleImportKeyRequestModel struct {
+	AccountName string          `json:"account_name"`
+	Keystore    json.RawMessage `json:"keystore"`
+	Passphrase  string          `json:"passphrase"`
+	Sending     bool            `json:"sending"`
+}
+
+type OracleImportKeyResponseModel struct {
+	AccountName string `json:"account_name"`
+	Address     string `json:"address"`
+}
+
+// OracleExportKeyRequestModel exports an account's key as a V3 keystore file, encrypted
+// with the passphrase
+type OracleExportKeyRequestModel struct {
+	AccountName string `json:"account_name"`
+	Passphrase  string `json:"passphrase"`
+}
+
+// OracleRotateTokenResponseModel returns the new API token. The old one stops working
+// straight away.
+type OracleRotateTokenResponseModel struct {
+	Token string `json:"token"`
+}
+
+// OracleCreateCredentialRequestModel creates a named API key limited to the scopes
+type OracleCreateCredentialRequestModel struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+// OracleCreateCredentialResponseModel returns a new API key. It's only stored hashed, so
+// it can't be shown again.
+type OracleCreateCredentialResponseModel struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	Key    string   `json:"key"`
+}
+
+type OracleRevokeCredentialRequestModel struct {
+	Name string `json:"name"`
+}
+
+type ApiCredentialModel struct {
+	Name      string    `json:"name"`
+	Scopes    []string  `json:"scopes"`
+	CreatedAt time.Time `json:"created"`
+}
+
 type OracleChangeFeeRequestModel struct {
-	Amount int64 `json:"amount"`
+	Chain   string `json:"chain"`
+	Account string `json:"account"`
+	Amount  int64  `json:"amount"`
 }
 
 type OracleChangeGranularFeeRequestModel struct {
+	Chain    string `json:"chain"`
+	Account  string `json:"account"`
 	Consumer string `json:"consumer"`
 	Amount   int64  `json:"amount"`
 }
 
 type OracleQueryFeesModel struct {
+	Chain    string `json:"chain"`
+	Account  string `json:"account"`
 	Consumer string `json:"consumer"`
 }
 
@@ -33,7 +106,9 @@ type RandomnessRequestModel struct {
 	ID                 uint      `json:"id"`
 	CreatedAt          time.Time `json:"created"`
 	UpdatedAt          time.Time `json:"updated"`
+	ChainId            int64     `json:"chain_id"`
 	Sender             string    `json:"consumer"`
+	KeyHash            string    `json:"key_hash"`
 	RequestId          string    `json:"request_id"`
 	RequestBlockNumber uint64    `json:"request_block_num"`
 	RequestBlockHash   string    `json:"request_block_hash"`
@@ -69,3 +144,51 @@ type RequestResponse struct {
 	Requests []RandomnessRequestModel `json:"requests"`
 	Pages    Pages                    `json:"pagination"`
 }
+
+// EthTxModel is an entry in the oracle's Tx ledger
+type EthTxModel struct {
+	ID          uint      `json:"id"`
+	CreatedAt   time.Time `json:"created"`
+	UpdatedAt   time.Time `json:"updated"`
+	ChainId     int64     `json:"chain_id"`
+	FromAddress string    `json:"from"`
+	Nonce       uint64    `json:"nonce"`
+	TxHash      string    `json:"tx_hash"`
+	Purpose     string    `json:"purpose"`
+	State       string    `json:"state"`
+	BlockNumber uint64    `json:"block_num"`
+	GasUsed     uint64    `json:"gas_used"`
+	Broadcasts  uint      `json:"broadcasts"`
+	Error       string    `json:"error,omitempty"`
+}
+
+type EthTxResponse struct {
+	Txs   []EthTxModel `json:"txs"`
+	Pages Pages        `json:"pagination"`
+}
+
+type ProofVerification struct {
+	FulfillTxHash string `json:"fulfill_tx_hash,omitempty"`
+	PublicKey     string `json:"public_key"`
+	KeyHash       string `json:"key_hash"`
+	Seed          string `json:"seed"`
+	BlockNumber   uint64 `json:"block_num"`
+	BlockHash     string `json:"block_hash"`
+	FinalSeed     string `json:"final_seed"`
+	Gamma         string `json:"gamma"`
+	Output        string `json:"output"`
+	Valid         bool   `json:"valid"`
+	Error         string `json:"error,omitempty"`
+}
+
+type HealthCheck struct {
+	Name       string `json:"name"`
+	Healthy    bool   `json:"healthy"`
+	Message    string `json:"message,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+type HealthStatus struct {
+	Healthy bool          `json:"healthy"`
+	Checks  []HealthCheck `json:"checks"`
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"gorm.io/gorm"
+	"strings"
+)
+
+// API credential scopes
+const (
+	// requests, analytics, fees, balances, Txs, proofs and metrics
+	API_SCOPE_READ = "read"
+	// change fees
+	API_SCOPE_FEES = "fees"
+	// withdraw earned tokens
+	API_SCOPE_FUNDS = "funds"
+	// register and add keys, manage API credentials and stop the oracle
+	API_SCOPE_ADMIN = "admin"
+	// export keys and rotate the keystore token. Only the keystore token has it, and it
+	// can't be given to a credential.
+	API_SCOPE_KEYSTORE = "keystore"
+)
+
+// ApiCredentialScopes are the scopes a credential can be given
+var ApiCredentialScopes = []string{API_SCOPE_READ, API_SCOPE_FEES, API_SCOPE_FUNDS, API_SCOPE_ADMIN}
+
+// ApiCredential is a named API key limited to some scopes. Only the key's hash is stored, and
+// the key has nothing to do with the keystore token, so it can't decrypt the keystore.
+type ApiCredential struct {
+	gorm.Model
+	Name string `gorm:"uniqueIndex"`
+	// hex encoded sha256 of the key
+	KeyHash string `gorm:"uniqueIndex"`
+	// comma separated
+	Scopes string
+}
+
+func (ApiCredential) TableName() string {
+	return "api_credentials"
+}
+
+func (c ApiCredential) GetId() uint {
+	return c.ID
+}
+
+func (c ApiCredential) GetName() string {
+	return c.Name
+}
+
+func (c ApiCredential) GetKeyHash() string {
+	return c.KeyHash
+}
+
+func (c ApiCredential) GetScopes() []string {
+	if c.Scopes == "" {
+		return nil
+	}
+	return strings.Split(c.Scopes, ",")
+}
+
+// HasScope returns true if the credential was given the scope, or is an admin credential.
+// Admin credentials don't get the keystore scope.
+func (c ApiCredential) HasScope(scope string) bool {
+	for _, s := range c.GetScopes() {
+		if s == scope || (s == API_SCOPE_ADMIN && scope != API_SCOPE_KEYSTORE) {
+			return true
+		}
+	}
+	return false
+}
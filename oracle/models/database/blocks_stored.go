@@ -4,6 +4,7 @@ import "gorm.io/gorm"
 
 type BlocksStored struct {
 	gorm.Model
+	ChainId     int64  `gorm:"index;default:0"`
 	BlockHash   string `gorm:"index"`
 	BlockNumber uint64 `gorm:"index"`
 	TxHash      string `gorm:"index"`
@@ -17,6 +18,10 @@ func (f BlocksStored) GetId() uint {
 	return f.ID
 }
 
+func (f BlocksStored) GetChainId() int64 {
+	return f.ChainId
+}
+
 func (f BlocksStored) GetBlockHash() string {
 	return f.BlockHash
 }
@@ -0,0 +1,100 @@
+package database
+
+import "gorm.io/gorm"
+
+// Tx ledger states
+const (
+	// signed and saved, but not yet accepted by a node
+	ETH_TX_STATE_UNBROADCAST = "unbroadcast"
+	// broadcast, and waiting for enough confirmations
+	ETH_TX_STATE_UNCONFIRMED = "unconfirmed"
+	// mined with enough confirmations
+	ETH_TX_STATE_CONFIRMED = "confirmed"
+	// mined with enough confirmations, but reverted
+	ETH_TX_STATE_REVERTED = "reverted"
+	// another Tx with the same nonce was mined instead, e.g. a gas bump replacement
+	ETH_TX_STATE_REPLACED = "replaced"
+	// rejected by the node, so its nonce was never used
+	ETH_TX_STATE_FAILED = "failed"
+)
+
+// Tx purposes
+const (
+	ETH_TX_PURPOSE_FULFILLMENT         = "fulfillment"
+	ETH_TX_PURPOSE_STORE_BLOCK_HASH    = "store_block_hash"
+	ETH_TX_PURPOSE_REGISTER            = "register"
+	ETH_TX_PURPOSE_CHANGE_FEE          = "change_fee"
+	ETH_TX_PURPOSE_CHANGE_GRANULAR_FEE = "change_granular_fee"
+	ETH_TX_PURPOSE_WITHDRAW            = "withdraw"
+	ETH_TX_PURPOSE_RANDOMNESS_REQUEST  = "randomness_request"
+	ETH_TX_PURPOSE_TOP_UP              = "top_up"
+)
+
+// EthTx is a Tx sent by the oracle. It's saved, with its signed bytes, before it's broadcast
+// so it can be tracked and rebroadcast after a restart.
+type EthTx struct {
+	gorm.Model
+	ChainId     int64  `gorm:"index;default:0"`
+	FromAddress string `gorm:"index"`
+	Nonce       uint64 `gorm:"index"`
+	TxHash      string `gorm:"index"`
+	// the signed Tx, as sent with eth_sendRawTransaction
+	Raw     []byte
+	Purpose string `gorm:"index"`
+	State   string `gorm:"index"`
+	// set once the Tx is mined
+	BlockNumber uint64
+	GasUsed     uint64
+	// times the Tx has been sent to a node
+	Broadcasts uint
+	// the last error broadcasting the Tx
+	Error string
+}
+
+func (EthTx) TableName() string {
+	return "eth_txes"
+}
+
+func (t EthTx) GetId() uint {
+	return t.ID
+}
+
+func (t EthTx) GetChainId() int64 {
+	return t.ChainId
+}
+
+func (t EthTx) GetFromAddress() string {
+	return t.FromAddress
+}
+
+func (t EthTx) GetNonce() uint64 {
+	return t.Nonce
+}
+
+func (t EthTx) GetTxHash() string {
+	return t.TxHash
+}
+
+func (t EthTx) GetRaw() []byte {
+	return t.Raw
+}
+
+func (t EthTx) GetPurpose() string {
+	return t.Purpose
+}
+
+func (t EthTx) GetState() string {
+	return t.State
+}
+
+func (t EthTx) GetBlockNumber() uint64 {
+	return t.BlockNumber
+}
+
+func (t EthTx) GetGasUsed() uint64 {
+	return t.GasUsed
+}
+
+func (t EthTx) GetError() string {
+	return t.Error
+}
@@ -4,6 +4,7 @@ import "gorm.io/gorm"
 
 type FailedFulfilment struct {
 	gorm.Model
+	ChainId    int64  `gorm:"index;default:0"`
 	RequestId  string `gorm:"index"`
 	TxHash     string `gorm:"index"`
 	GasUsed    uint64
@@ -19,6 +20,10 @@ func (f FailedFulfilment) GetId() uint {
 	return f.ID
 }
 
+func (f FailedFulfilment) GetChainId() int64 {
+	return f.ChainId
+}
+
 func (f FailedFulfilment) GetRequestId() string {
 	return f.RequestId
 }
@@ -0,0 +1,31 @@
+package database
+
+import "gorm.io/gorm"
+
+// ListenerCursor is the block the event listener resumes scanning from, per contract and chain
+type ListenerCursor struct {
+	gorm.Model
+	ContractAddress string `gorm:"uniqueIndex:idx_listener_cursor"`
+	ChainId         int64  `gorm:"uniqueIndex:idx_listener_cursor"`
+	BlockNumber     uint64
+}
+
+func (ListenerCursor) TableName() string {
+	return "listener_cursors"
+}
+
+func (c ListenerCursor) GetId() uint {
+	return c.ID
+}
+
+func (c ListenerCursor) GetContractAddress() string {
+	return c.ContractAddress
+}
+
+func (c ListenerCursor) GetChainId() int64 {
+	return c.ChainId
+}
+
+func (c ListenerCursor) GetBlockNumber() uint64 {
+	return c.BlockNumber
+}
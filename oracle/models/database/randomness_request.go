@@ -9,14 +9,18 @@ const (
 	REQUEST_STATUS_TX_FAILED         // Fulfilment Tx failed and not broadcast
 	REQUEST_STATUS_SUCCESS           // Fulfilment Tx successful and confirmed in RandomnessRequestFulfilled event
 	REQUEST_STATUS_FULFILMENT_FAILED // Fulfilment failed - too many failed attempts, request too old etc.
+	REQUEST_STATUS_REORGED           // Request or fulfilment block orphaned by a chain reorganisation - needs re-checking
+	REQUEST_STATUS_PROOF_INVALID     // Generated proof failed local verification, and was not sent
+	REQUEST_STATUS_ALREADY_FULFILLED // Coordinator has no pending request - fulfilled by another Tx, so nothing was sent
 )
 
 type RandomnessRequest struct {
 	gorm.Model
+	ChainId                    int64 `gorm:"uniqueIndex:idx_randomness_requests_chain_request,priority:2;default:0"`
 	KeyHash                    string
 	Seed                       string
 	Sender                     string `gorm:"index"`
-	RequestId                  string `gorm:"uniqueIndex"`
+	RequestId                  string `gorm:"uniqueIndex:idx_randomness_requests_chain_request,priority:1"`
 	RequestBlockHash           string `gorm:"index"`
 	RequestBlockNumber         uint64 `gorm:"index"`
 	RequestTxHash              string `gorm:"index"`
@@ -31,6 +35,7 @@ type RandomnessRequest struct {
 	FulfillGasUsed             uint64
 	FulfillGasPrice            uint64
 	FulfillmentAttempts        uint64 `gorm:"default:0"`
+	GasBumps                   uint64 `gorm:"default:0"`
 	Status                     int    `gorm:"index"`
 	StatusReason               string
 }
@@ -43,6 +48,10 @@ func (r RandomnessRequest) GetId() uint {
 	return r.ID
 }
 
+func (r RandomnessRequest) GetChainId() int64 {
+	return r.ChainId
+}
+
 func (r RandomnessRequest) GetKeyHash() string {
 	return r.KeyHash
 }
@@ -115,6 +124,10 @@ func (r RandomnessRequest) GetFulfillmentAttempts() uint64 {
 	return r.FulfillmentAttempts
 }
 
+func (r RandomnessRequest) GetGasBumps() uint64 {
+	return r.GasBumps
+}
+
 func (r RandomnessRequest) GetStatus() int {
 	return r.Status
 }
@@ -134,6 +147,12 @@ func (r RandomnessRequest) GetStatusString() string {
 		return "SUCCESS"
 	case REQUEST_STATUS_FULFILMENT_FAILED:
 		return "FULFILMENT FAILED"
+	case REQUEST_STATUS_REORGED:
+		return "REORGED"
+	case REQUEST_STATUS_PROOF_INVALID:
+		return "PROOF INVALID"
+	case REQUEST_STATUS_ALREADY_FULFILLED:
+		return "ALREADY FULFILLED"
 	}
 
 	return "UNKNOWN"
@@ -8,8 +8,14 @@ type KeyStorageKeyModel struct {
 	// true if public key generated from this private is already
 	// registered in VORCoordinator
 	Registered bool `json:"registered"`
-	// last checked block number (may be set manually)
-	BlockNumber int64 `json:"block_number"`
+	// chain IDs of the VORCoordinators the key has been registered with at startup
+	RegisteredChains []int64 `json:"registered_chains,omitempty"`
+	// true if the key is a sending account, which pays for gas and sends Txs, rather than
+	// a VOR proving key
+	Sending bool `json:"sending,omitempty"`
+	// last checked block number, kept here before the listener cursor moved to the DB.
+	// Only read to seed the cursor on upgrade
+	BlockNumber int64 `json:"block_number,omitempty"`
 }
 
 func (d KeyStorageKeyModel) GetAccount() string {
@@ -28,6 +34,14 @@ func (d KeyStorageKeyModel) GetRegistered() bool {
 	return d.Registered
 }
 
+func (d KeyStorageKeyModel) GetRegisteredChains() []int64 {
+	return d.RegisteredChains
+}
+
+func (d KeyStorageKeyModel) GetSending() bool {
+	return d.Sending
+}
+
 func (d KeyStorageKeyModel) GetBlockNumber() int64 {
 	return d.BlockNumber
 }
@@ -44,9 +58,26 @@ func (d KeyStorageKeyModel) SetPrivate(private string) {
 	d.Private = private
 }
 
+// KeyStorageCryptoModel records how a keystore's private keys are encrypted. The AES key is
+// derived from the API token with the KDF and its parameters.
+type KeyStorageCryptoModel struct {
+	Cipher string `json:"cipher"`
+	KDF    string `json:"kdf"`
+	N      int    `json:"n"`
+	R      int    `json:"r"`
+	P      int    `json:"p"`
+	DKLen  int    `json:"dklen"`
+	// hex encoded, generated for each keystore
+	Salt string `json:"salt"`
+}
+
 type KeyStorageModel struct {
-	Key  []*KeyStorageKeyModel `json:"keys"`
-	Hash string                `json:"hash"`
+	// format of the keystore. Files written before it was added are version 0
+	Version int `json:"version,omitempty"`
+	// encryption parameters. Not set for version 0
+	Crypto *KeyStorageCryptoModel `json:"crypto,omitempty"`
+	Key    []*KeyStorageKeyModel  `json:"keys"`
+	Hash   string                 `json:"hash"`
 	// used to store decrypted api token(key) which is being used
 	Token string `json:"-"`
 	// used to store decrypted private key which is being used
@@ -57,6 +88,14 @@ func (d KeyStorageModel) GetKey() []*KeyStorageKeyModel {
 	return d.Key
 }
 
+func (d KeyStorageModel) GetVersion() int {
+	return d.Version
+}
+
+func (d KeyStorageModel) GetCrypto() *KeyStorageCryptoModel {
+	return d.Crypto
+}
+
 func (d KeyStorageModel) GetHash() string {
 	return d.Hash
 }
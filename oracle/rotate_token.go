@@ -0,0 +1,39 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"oracle/config"
+	"oracle/store/keystorage"
+)
+
+// rotateToken replaces the API token and re-encrypts the keystore under it, without starting
+// the oracle:
+//
+//	oracle rotate-token -k current_key
+//
+// The oracle rewrites the keystore from memory, so stop it first, or use the /rotatetoken
+// API call while it's running.
+func rotateToken() error {
+	keystore, err := keystorage.NewKeyStorage(log, config.Conf.Keystorage.File)
+	if err != nil {
+		return err
+	}
+	if keystore.KeyStore.GetHash() == "" {
+		return errors.New("the keystore hasn't been set up yet. Run oracle start first")
+	}
+	err = unlock(keystore)
+	if err != nil {
+		return err
+	}
+
+	token, err := keystore.RotateToken()
+	if err != nil {
+		return err
+	}
+	fmt.Println("")
+	fmt.Println("The old key no longer works. Your new cli/HTTP key is:")
+	fmt.Println(token)
+	fmt.Println("Update the file passed with -k, and run oraclecli settings with the new key.")
+	return nil
+}
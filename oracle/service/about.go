@@ -1,7 +1,6 @@
 package service
 
 import (
-	"crypto/ecdsa"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -10,17 +9,63 @@ import (
 	"math/big"
 	"oracle/config"
 	"oracle/utils"
-	"oracle/utils/walletworker"
+	"strings"
 )
 
 func (d *Service) About() (response string, err error) {
-	privateKey, err := crypto.HexToECDSA(utils.RemoveHexPrefix(d.Store.Keystorage.GetSelectedPrivateKey()))
-	publicKey := hexutil.Encode(crypto.FromECDSAPub(privateKey.Public().(*ecdsa.PublicKey)))
-	ECDSAoraclePublicKey, err := crypto.UnmarshalPubkey(crypto.FromECDSAPub(privateKey.Public().(*ecdsa.PublicKey)))
-	_, oracleAddress := walletworker.GenerateAddress(ECDSAoraclePublicKey)
-	keyhash, err := d.VORCoordinatorCaller.HashOfKey()
+	var keys strings.Builder
+	for _, key := range d.Keys() {
+		keys.WriteString(d.aboutKey(key))
+	}
+
+	sending := "provider"
+	if accounts := d.aboutSending(); accounts != "" {
+		sending = accounts
+	}
+
+	return fmt.Sprintf(`
+Chain:                  %s
+VORCoordinator address: %s
+Host:                   %s 
+Port:                   %d
+Network:                %d
+Sending accounts:       %s
+%s`,
+		d.ChainName(),
+		d.Connection.VORCoordinatorAddress.Hex(),
+		config.Conf.Serve.Host,
+		config.Conf.Serve.Port,
+		d.ChainID(),
+		sending,
+		keys.String()), nil
+}
 
-	tokens, err := d.VORCoordinatorCaller.QueryWithdrawableTokens()
+// aboutSending lists the sending accounts with their balances and nonces
+func (d *Service) aboutSending() string {
+	if err := d.RefreshSenders(d.ctx); err != nil {
+		return err.Error()
+	}
+	var sending strings.Builder
+	for _, account := range d.SendingAccounts() {
+		balance := "unknown"
+		if account.Balance != nil {
+			toEth := new(big.Float).Quo(new(big.Float).SetInt(account.Balance), big.NewFloat(params.Ether))
+			balance = fmt.Sprintf("%s ETH", toEth.String())
+		}
+		sending.WriteString(fmt.Sprintf("\n  %s  balance %s, nonce %d, pending %d",
+			account.Address.Hex(), balance, account.Nonce, account.Pending))
+	}
+	return sending.String()
+}
+
+func (d *Service) aboutKey(key *ProvingKey) string {
+	publicKey := ""
+	privateKey, err := crypto.HexToECDSA(utils.RemoveHexPrefix(key.privateKey))
+	if err == nil {
+		publicKey = hexutil.Encode(crypto.FromECDSAPub(&privateKey.PublicKey))
+	}
+
+	tokens, err := key.Caller.QueryWithdrawableTokens()
 	var withdrawableTokens = ""
 	if err != nil {
 		withdrawableTokens = err.Error()
@@ -29,7 +74,7 @@ func (d *Service) About() (response string, err error) {
 		withdrawableTokens = fmt.Sprintf("%s (%s XFUND)", tokens.String(), toXfund.String())
 	}
 
-	balance, err := d.VORCoordinatorCaller.GetOracleEthBalance()
+	balance, err := key.Caller.GetOracleEthBalance()
 	var ethBalance = ""
 	if err != nil {
 		ethBalance = err.Error()
@@ -38,27 +83,24 @@ func (d *Service) About() (response string, err error) {
 		ethBalance = fmt.Sprintf("%s (%s ETH)", balance.String(), toEth.String())
 	}
 
-	return fmt.Sprintf(`
-VORCoordinator address: %s
-Host:                   %s 
-Port:                   %d
-Network:                %d
+	isDefault := ""
+	if key.Caller == d.VORCoordinatorCaller {
+		isDefault = " (default)"
+	}
 
-Account:                %s
+	return fmt.Sprintf(`
+Account:                %s%s
 Public Key:             %s
 KeyHash:                %s
-Address:                %s
+Provider:               %s
 
 Withdrawable Tokens:    %s
 ETH Balance:            %s
 `,
-		config.Conf.VORCoordinatorContractAddress,
-		config.Conf.Serve.Host,
-		config.Conf.Serve.Port,
-		config.Conf.NetworkID,
-		config.Conf.Keystorage.Account,
+		key.Account, isDefault,
 		publicKey,
-		common.BytesToHash([]byte(keyhash[:])),
-		oracleAddress, withdrawableTokens,
-		ethBalance), nil
+		common.BytesToHash(key.KeyHash[:]),
+		key.Caller.OracleAddress(),
+		withdrawableTokens,
+		ethBalance)
 }
@@ -7,9 +7,14 @@ import (
 	"oracle/models/database"
 )
 
-func (d *Service) Analytics(xFundEth, xFundUsd, fees float64, limit int, gasPrice int64, simulation int, consumer string) (interface{}, error) {
+func (d *Service) Analytics(xFundEth, xFundUsd, fees float64, limit int, gasPrice int64, simulation int, consumer string, account string) (interface{}, error) {
 
-	requests, err := d.Store.Db.GetLastXRequests(limit, consumer)
+	keyHash, err := d.keyHashFilter(account)
+	if err != nil {
+		return nil, err
+	}
+
+	requests, err := d.Store.Db.GetLastXRequests(limit, consumer, keyHash)
 
 	if err != nil {
 		return nil, err
@@ -22,11 +27,11 @@ func (d *Service) Analytics(xFundEth, xFundUsd, fees float64, limit int, gasPric
 	mostGasUsedContract := ""
 	leastGasUSedContract := ""
 
-	mgu, err := d.Store.Db.GetMostGasUsed()
+	mgu, err := d.Store.Db.GetMostGasUsed(keyHash)
 	if err == nil {
 		mostGasUsedContract = mgu.Sender
 	}
-	lgu, err := d.Store.Db.GetLeastGasUsed()
+	lgu, err := d.Store.Db.GetLeastGasUsed(keyHash)
 	if err == nil {
 		leastGasUSedContract = lgu.Sender
 	}
@@ -39,7 +44,9 @@ func (d *Service) Analytics(xFundEth, xFundUsd, fees float64, limit int, gasPric
 	}
 
 	filters := api.AnalyticsFilter{
+		Chain:            d.ChainName(),
 		ConsumerContract: consumer,
+		Account:          account,
 		Limit:            limit,
 	}
 
@@ -95,7 +102,8 @@ func process(rows []database.RandomnessRequest, xFundEth, xFundUsd, fees float64
 
 		gasSum = big.NewInt(0).Add(gasSum, big.NewInt(gasVal))
 
-		// gas prices
+		// gas prices. FulfillGasPrice is the effective price paid, taken from the
+		// fulfillment receipt, so type-2 Txs are costed correctly
 		gasPriceVal := int64(reqRow.FulfillGasPrice)
 
 		// check for simulating gas prices
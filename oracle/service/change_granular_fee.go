@@ -6,6 +6,10 @@ import (
 	"math/big"
 )
 
-func (d *Service) ChangeGranularFee(consumer common.Address, amount int64) (*types.Transaction, error) {
-	return d.VORCoordinatorCaller.ChangeGranularFee(consumer, big.NewInt(amount))
+func (d *Service) ChangeGranularFee(account string, consumer common.Address, amount int64) (*types.Transaction, error) {
+	caller, err := d.callerFor(account)
+	if err != nil {
+		return nil, err
+	}
+	return caller.ChangeGranularFee(consumer, big.NewInt(amount))
 }
@@ -5,6 +5,10 @@ import (
 	"math/big"
 )
 
-func (d *Service) ChangeFee(amount int64) (*types.Transaction, error) {
-	return d.VORCoordinatorCaller.ChangeFee(big.NewInt(amount))
+func (d *Service) ChangeFee(account string, amount int64) (*types.Transaction, error) {
+	caller, err := d.callerFor(account)
+	if err != nil {
+		return nil, err
+	}
+	return caller.ChangeFee(big.NewInt(amount))
 }
@@ -6,9 +6,18 @@ import (
 	"oracle/models/api"
 )
 
-func (d *Service) Consumers(xFundEth, xFundUsd float64, consumer string) (interface{}, error) {
+func (d *Service) Consumers(xFundEth, xFundUsd float64, consumer string, account string) (interface{}, error) {
 
-	consumers, err := d.Store.Db.GetDistinctConsumers(consumer)
+	key, err := d.KeyByAccount(account)
+	if err != nil {
+		return nil, err
+	}
+	keyHash, err := d.keyHashFilter(account)
+	if err != nil {
+		return nil, err
+	}
+
+	consumers, err := d.Store.Db.GetDistinctConsumers(consumer, keyHash)
 
 	if err != nil {
 		return nil, err
@@ -23,13 +32,13 @@ func (d *Service) Consumers(xFundEth, xFundUsd float64, consumer string) (interf
 	for _, consumer := range consumers {
 		var analyticsData api.AnalyticsData
 		currentXfundFee := 0.0
-		consumerRows, err := d.Store.Db.GetLastXRequests(0, consumer.Sender)
+		consumerRows, err := d.Store.Db.GetLastXRequests(0, consumer.Sender, keyHash)
 
 		if err == nil {
 			analyticsData = process(consumerRows, xFundEth, xFundUsd, 0, 0, 0)
 		}
 
-		currentFee, err := d.VORCoordinatorCaller.QueryFees(consumer.Sender)
+		currentFee, err := key.Caller.QueryFees(consumer.Sender)
 		currentFeeTokens := new(big.Float).Quo(new(big.Float).SetInt(currentFee), big.NewFloat(params.GWei))
 		currentXfundFee, _ = currentFeeTokens.Float64()
 
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"oracle/models/database"
+	"oracle/store/keystorage"
+	"strings"
+)
+
+// CreateCredential adds a named API key with the scopes. Returns the key, which is only
+// stored hashed, so it can't be shown again.
+func (d *Service) CreateCredential(name string, scopes []string) (string, error) {
+	if name == "" {
+		return "", errors.New("a credential needs a name")
+	}
+	if len(scopes) == 0 {
+		return "", errors.New("a credential needs at least one scope")
+	}
+	for _, scope := range scopes {
+		if !isCredentialScope(scope) {
+			return "", fmt.Errorf("unknown scope %s. Use %s", scope, strings.Join(database.ApiCredentialScopes, ", "))
+		}
+	}
+
+	key := keystorage.GenerateRandomBytes(32)
+	err := d.Store.Db.InsertApiCredential(&database.ApiCredential{
+		Name:    name,
+		KeyHash: hashKey(key),
+		Scopes:  strings.Join(scopes, ","),
+	})
+	if err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+func (d *Service) Credentials() ([]database.ApiCredential, error) {
+	return d.Store.Db.GetApiCredentials()
+}
+
+// RevokeCredential deletes a credential. Its key stops working straight away.
+func (d *Service) RevokeCredential(name string) error {
+	return d.Store.Db.DeleteApiCredential(name)
+}
+
+// Authenticate returns the credential an API key belongs to, and false if it doesn't belong
+// to any. The keystore token is a credential with every scope.
+func (d *Service) Authenticate(key string) (database.ApiCredential, bool, error) {
+	if d.Store.Keystorage.IsToken(key) {
+		return database.ApiCredential{
+			Name:   "keystore",
+			Scopes: strings.Join(database.ApiCredentialScopes, ",") + "," + database.API_SCOPE_KEYSTORE,
+		}, true, nil
+	}
+	credential, err := d.Store.Db.GetApiCredentialByHash(hashKey(key))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return credential, false, nil
+	}
+	if err != nil {
+		return credential, false, err
+	}
+	return credential, true, nil
+}
+
+// hashKey returns the hash an API key is stored as. Keys are long and random, so a fast
+// hash is enough.
+func hashKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+func isCredentialScope(scope string) bool {
+	for _, s := range database.ApiCredentialScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
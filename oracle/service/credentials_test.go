@@ -0,0 +1,77 @@
+package service_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"oracle/models/database"
+	"oracle/store/keystorage"
+	"testing"
+)
+
+func TestService_Credentials(t *testing.T) {
+	var token string
+	oracleService := newTestService(t, func(keystore *keystorage.Keystorage) {
+		_, err := keystore.GeneratePrivate("oracle")
+		assert.NoError(t, err)
+		assert.NoError(t, keystore.SelectPrivateKey("oracle"))
+		token = keystore.KeyStore.Token
+	})
+
+	// the keystore token has every scope
+	credential, ok, err := oracleService.Authenticate(token)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, credential.HasScope(database.API_SCOPE_ADMIN))
+	assert.True(t, credential.HasScope(database.API_SCOPE_KEYSTORE))
+
+	_, err = oracleService.CreateCredential("", []string{database.API_SCOPE_READ})
+	assert.Error(t, err)
+	_, err = oracleService.CreateCredential("dashboard", nil)
+	assert.Error(t, err)
+	_, err = oracleService.CreateCredential("dashboard", []string{database.API_SCOPE_KEYSTORE})
+	assert.Error(t, err)
+
+	readKey, err := oracleService.CreateCredential("dashboard", []string{database.API_SCOPE_READ})
+	assert.NoError(t, err)
+	assert.NotEqual(t, token, readKey)
+	_, err = oracleService.CreateCredential("dashboard", []string{database.API_SCOPE_FEES})
+	assert.Error(t, err)
+	adminKey, err := oracleService.CreateCredential("ops", []string{database.API_SCOPE_ADMIN})
+	assert.NoError(t, err)
+
+	credential, ok, err = oracleService.Authenticate(readKey)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "dashboard", credential.GetName())
+	assert.True(t, credential.HasScope(database.API_SCOPE_READ))
+	assert.False(t, credential.HasScope(database.API_SCOPE_FUNDS))
+	assert.False(t, credential.HasScope(database.API_SCOPE_ADMIN))
+
+	// admin keys can do everything but decrypt the keystore
+	credential, ok, err = oracleService.Authenticate(adminKey)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, credential.HasScope(database.API_SCOPE_FUNDS))
+	assert.False(t, credential.HasScope(database.API_SCOPE_KEYSTORE))
+
+	// only hashes are stored
+	credentials, err := oracleService.Credentials()
+	assert.NoError(t, err)
+	assert.Len(t, credentials, 2)
+	for _, c := range credentials {
+		assert.NotEqual(t, readKey, c.GetKeyHash())
+		assert.NotEqual(t, adminKey, c.GetKeyHash())
+	}
+
+	_, ok, err = oracleService.Authenticate("wrong")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, oracleService.RevokeCredential("dashboard"))
+	assert.Error(t, oracleService.RevokeCredential("dashboard"))
+	_, ok, err = oracleService.Authenticate(readKey)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	credentials, err = oracleService.Credentials()
+	assert.NoError(t, err)
+	assert.Len(t, credentials, 1)
+}
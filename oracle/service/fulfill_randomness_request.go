@@ -9,28 +9,40 @@ import (
 	"oracle/utils"
 )
 
-func (d *Service) FulfillRandomness(seed vor.Seed, blockHash common.Hash, blockNum uint64) (tx *types.Transaction, err error) {
+// FulfillRandomness generates the proof with the request's proving key, and sends it in a Tx
+// from the account picked by the sender pool, or the key's provider if the pool is empty
+func (d *Service) FulfillRandomness(key *ProvingKey, seed vor.Seed, blockHash common.Hash, blockNum uint64) (tx *types.Transaction, err error) {
 	preSeed := vor.PreSeedData{
 		PreSeed:   seed,
 		BlockHash: blockHash,
 		BlockNum:  blockNum,
 	}
-	oraclePrivateKeyECDSA, err := crypto.HexToECDSA(utils.RemoveHexPrefix(d.Store.Keystorage.GetSelectedPrivateKey()))
+	oraclePrivateKeyECDSA, err := crypto.HexToECDSA(utils.RemoveHexPrefix(key.privateKey))
+	if err != nil {
+		return nil, err
+	}
 
 	secretKeyScalar := secp256k1.IntToScalar(oraclePrivateKeyECDSA.D)
 	secretKey := secp256k1.ScalarToHash(secretKeyScalar)
 
-	//secretKey, err := d.VORCoordinatorCaller.HashOfKey()
+	marshalledResponse, err := vor.GenerateProofResponse(secretKey, preSeed)
 	if err != nil {
 		return nil, err
 	}
 
-	marshalledResponse, err := vor.GenerateProofResponse(secretKey, preSeed)
+	// verify locally before it costs gas
+	err = d.verifyProofResponse(marshalledResponse, preSeed, secp256k1.ScalarToPublicPoint(secretKeyScalar))
+	if err != nil {
+		return nil, err
+	}
+
+	// dry run first, so a fulfillment which would revert doesn't cost gas
+	gasLimit, err := key.Caller.SimulateFulfillRandomnessRequest(marshalledResponse[:])
 	if err != nil {
 		return nil, err
 	}
 
-	tx, err = d.VORCoordinatorCaller.FulfillRandomnessRequest(marshalledResponse[:])
+	tx, err = key.Caller.FulfillRandomnessRequest(marshalledResponse[:], gasLimit)
 
 	return
 }
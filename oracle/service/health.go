@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"oracle/config"
+	"oracle/models/api"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// time allowed for each dependency check, so a hung RPC or DB doesn't hang the probe
+	healthCheckTimeout      = 5 * time.Second
+	defaultHeartbeatTimeout = 300
+)
+
+type healthCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// RecordHeartbeat is called by the event listener each time it completes a cycle
+func (d *Service) RecordHeartbeat() {
+	atomic.StoreInt64(&d.lastHeartbeat, time.Now().UnixNano())
+}
+
+// LastHeartbeat returns when the event listener last completed a cycle
+func (d *Service) LastHeartbeat() time.Time {
+	nanos := atomic.LoadInt64(&d.lastHeartbeat)
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// Liveness checks the process can still do work - the DB responds and the event listener
+// is running. It does not depend on the Eth provider, so an RPC outage doesn't cause restarts.
+func (d *Service) Liveness(ctx context.Context) api.HealthStatus {
+	return runHealthChecks(ctx, []healthCheck{
+		{name: "database", check: d.checkDatabase},
+		{name: "listener", check: d.checkListener},
+	})
+}
+
+// Readiness checks everything needed to fulfil requests
+func (d *Service) Readiness(ctx context.Context) api.HealthStatus {
+	return runHealthChecks(ctx, []healthCheck{
+		{name: "database", check: d.checkDatabase},
+		{name: "listener", check: d.checkListener},
+		{name: "keystore", check: d.checkKeystore},
+		{name: "eth_rpc", check: d.checkEthRPC},
+		{name: "proving_key", check: d.checkProvingKey},
+	})
+}
+
+func runHealthChecks(ctx context.Context, checks []healthCheck) api.HealthStatus {
+	status := api.HealthStatus{Healthy: true}
+	for _, c := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		start := time.Now()
+		err := c.check(checkCtx)
+		cancel()
+
+		result := api.HealthCheck{
+			Name:       c.name,
+			Healthy:    err == nil,
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		if err != nil {
+			result.Message = err.Error()
+			status.Healthy = false
+		}
+		status.Checks = append(status.Checks, result)
+	}
+	return status
+}
+
+func (d *Service) checkDatabase(ctx context.Context) error {
+	return d.Store.Db.Ping(ctx)
+}
+
+// checkListener fails once the listener's heartbeat is older than HeartbeatTimeout. Before its
+// first heartbeat, the listener gets the same time from the service's creation to start up.
+func (d *Service) checkListener(_ context.Context) error {
+	heartbeatTimeout := int32(defaultHeartbeatTimeout)
+	if config.Conf.HeartbeatTimeout != 0 {
+		heartbeatTimeout = config.Conf.HeartbeatTimeout
+	}
+	timeout := time.Duration(heartbeatTimeout) * time.Second
+
+	last := d.LastHeartbeat()
+	if last.IsZero() {
+		if timeout > 0 && !d.createdAt.IsZero() && time.Since(d.createdAt) <= timeout {
+			return nil
+		}
+		return fmt.Errorf("listener has not completed a cycle")
+	}
+	age := time.Since(last)
+	if timeout > 0 && age > timeout {
+		return fmt.Errorf("last heartbeat %s ago, exceeds %s", age.Round(time.Second), timeout)
+	}
+	return nil
+}
+
+func (d *Service) checkKeystore(_ context.Context) error {
+	if d.Store.Keystorage.GetSelectedPrivateKey() == "" {
+		return fmt.Errorf("keystore is locked or no key is selected")
+	}
+	return nil
+}
+
+func (d *Service) checkEthRPC(ctx context.Context) error {
+	chainID, err := d.VORCoordinatorCaller.GetChainID(ctx)
+	if err != nil {
+		return err
+	}
+	if chainID.Cmp(d.Connection.ChainID) != 0 {
+		return fmt.Errorf("chain ID %s does not match network_id %s", chainID, d.Connection.ChainID)
+	}
+	return nil
+}
+
+// checkProvingKey checks every served key is registered to its own address
+func (d *Service) checkProvingKey(ctx context.Context) error {
+	var unregistered []string
+	for _, key := range d.Keys() {
+		registered, err := key.Caller.IsProvingKeyRegistered(ctx)
+		if err != nil {
+			return err
+		}
+		if !registered {
+			unregistered = append(unregistered, key.Account)
+		}
+	}
+	if len(unregistered) > 0 {
+		return fmt.Errorf("proving keys for %s are not registered to the oracle's address", strings.Join(unregistered, ", "))
+	}
+	return nil
+}
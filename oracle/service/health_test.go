@@ -0,0 +1,50 @@
+package service_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"oracle/config"
+	"oracle/service"
+	"oracle/store"
+	"path/filepath"
+	"testing"
+)
+
+func newHealthTestService(t *testing.T) *service.Service {
+	config.Conf.Database.Storage = filepath.Join(t.TempDir(), "oracle.db")
+	thestore, err := store.NewStore(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &service.Service{Store: thestore}
+}
+
+func TestService_Liveness(t *testing.T) {
+	s := newHealthTestService(t)
+
+	// listener hasn't started
+	status := s.Liveness(context.Background())
+	assert.False(t, status.Healthy)
+	assert.Len(t, status.Checks, 2)
+	assert.Equal(t, "database", status.Checks[0].Name)
+	assert.True(t, status.Checks[0].Healthy)
+	assert.Equal(t, "listener", status.Checks[1].Name)
+	assert.False(t, status.Checks[1].Healthy)
+
+	s.RecordHeartbeat()
+	status = s.Liveness(context.Background())
+	assert.True(t, status.Healthy)
+	assert.True(t, status.Checks[1].Healthy)
+	assert.Empty(t, status.Checks[1].Message)
+}
+
+func TestService_Liveness_HeartbeatTimeoutDisabled(t *testing.T) {
+	s := newHealthTestService(t)
+	s.RecordHeartbeat()
+
+	timeout := config.Conf.HeartbeatTimeout
+	defer func() { config.Conf.HeartbeatTimeout = timeout }()
+	config.Conf.HeartbeatTimeout = -1
+
+	assert.True(t, s.Liveness(context.Background()).Healthy)
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"oracle/chaincall"
+	"oracle/config"
+)
+
+// ProvingKey is one of the oracle's VOR proving keys, along with a caller which sends
+// Txs signed by it
+type ProvingKey struct {
+	Account    string
+	KeyHash    [32]byte
+	Caller     *chaincall.VORCoordinatorCaller
+	privateKey string
+}
+
+// KeyHashHex returns the key hash as stored in the DB
+func (k *ProvingKey) KeyHashHex() string {
+	return common.Bytes2Hex(k.KeyHash[:])
+}
+
+// loadKeys creates a caller for each key the oracle serves. The selected key is the default,
+// used by admin calls which don't name an account.
+func (d *Service) loadKeys() error {
+	selected := d.Store.Keystorage.GetSelectedPrivateKey()
+	for _, key := range d.Store.Keystorage.GetAll() {
+		if key.GetSending() {
+			continue
+		}
+		isSelected := key.GetPrivate() == selected
+		if !isSelected && !isServedAccount(key.GetAccount()) {
+			continue
+		}
+		provingKey, err := d.AddKey(key.GetAccount(), key.GetPrivate())
+		if err != nil {
+			return fmt.Errorf("load key for account %s: %w", key.GetAccount(), err)
+		}
+		if isSelected && d.VORCoordinatorCaller == nil {
+			d.VORCoordinatorCaller = provingKey.Caller
+		}
+	}
+
+	if d.VORCoordinatorCaller == nil {
+		provingKey, err := d.AddKey(config.Conf.Keystorage.Account, selected)
+		if err != nil {
+			return err
+		}
+		d.VORCoordinatorCaller = provingKey.Caller
+	}
+	return nil
+}
+
+// isServedAccount returns true if the account's key should be served. All keys are served
+// unless keystorage.accounts limits them.
+func isServedAccount(account string) bool {
+	if config.Conf.Keystorage == nil || len(config.Conf.Keystorage.Accounts) == 0 {
+		return true
+	}
+	for _, served := range config.Conf.Keystorage.Accounts {
+		if served == account {
+			return true
+		}
+	}
+	return false
+}
+
+// AddKey starts serving a proving key. Adding a key which is already served returns the
+// existing one.
+func (d *Service) AddKey(account string, privateKey string) (*ProvingKey, error) {
+	caller, err := d.newCaller(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	d.keysMu.Lock()
+	defer d.keysMu.Unlock()
+	keyHash := caller.KeyHash()
+	for _, key := range d.keys {
+		if key.KeyHash == keyHash {
+			return key, nil
+		}
+	}
+	provingKey := &ProvingKey{
+		Account:    account,
+		KeyHash:    keyHash,
+		Caller:     caller,
+		privateKey: privateKey,
+	}
+	d.keys = append(d.keys, provingKey)
+	return provingKey, nil
+}
+
+// newCaller creates a caller for a proving key, which sends Txs from the oracle's provider
+// and sending accounts. Keys registered before a provider was configured are still their
+// own provider.
+func (d *Service) newCaller(privateKey string) (*chaincall.VORCoordinatorCaller, error) {
+	caller, err := chaincall.NewVORCoordinatorCallerWithSenders(d.Connection, []byte(privateKey), d.provider, d.senders)
+	if err != nil || d.provider == nil {
+		return caller, err
+	}
+	self, err := chaincall.NewKeySigner(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	registered, err := caller.GetProviderAddress(d.ctx)
+	if err != nil || registered != self.Address() {
+		return caller, nil
+	}
+	return chaincall.NewVORCoordinatorCallerWithSenders(d.Connection, []byte(privateKey), self, d.senders)
+}
+
+// Keys returns the proving keys served by the oracle
+func (d *Service) Keys() []*ProvingKey {
+	d.keysMu.RLock()
+	defer d.keysMu.RUnlock()
+	keys := make([]*ProvingKey, len(d.keys))
+	copy(keys, d.keys)
+	return keys
+}
+
+// KeyByHash returns the served key with the given key hash, if there is one
+func (d *Service) KeyByHash(keyHash [32]byte) (*ProvingKey, bool) {
+	d.keysMu.RLock()
+	defer d.keysMu.RUnlock()
+	for _, key := range d.keys {
+		if key.KeyHash == keyHash {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// KeyByAccount returns the served key for a keystore account. An empty account returns the
+// default key.
+func (d *Service) KeyByAccount(account string) (*ProvingKey, error) {
+	d.keysMu.RLock()
+	defer d.keysMu.RUnlock()
+	for _, key := range d.keys {
+		if account == "" && key.Caller == d.VORCoordinatorCaller {
+			return key, nil
+		}
+		if account != "" && key.Account == account {
+			return key, nil
+		}
+	}
+	if account == "" {
+		return nil, fmt.Errorf("no default key")
+	}
+	return nil, fmt.Errorf("account %s is not served by this oracle", account)
+}
+
+// callerFor returns the caller for a keystore account, or the default caller if account is empty
+func (d *Service) callerFor(account string) (*chaincall.VORCoordinatorCaller, error) {
+	key, err := d.KeyByAccount(account)
+	if err != nil {
+		return nil, err
+	}
+	return key.Caller, nil
+}
+
+// keyHashFilter returns the DB key hash for an account, to filter requests by. An empty
+// account doesn't filter.
+func (d *Service) keyHashFilter(account string) (string, error) {
+	if account == "" {
+		return "", nil
+	}
+	key, err := d.KeyByAccount(account)
+	if err != nil {
+		return "", err
+	}
+	return key.KeyHashHex(), nil
+}
@@ -0,0 +1,122 @@
+package service_test
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"oracle/chaincall"
+	"oracle/config"
+	"oracle/ethrpc"
+	"oracle/service"
+	"oracle/store"
+	"oracle/store/keystorage"
+	"path/filepath"
+	"testing"
+)
+
+func newMultiKeyTestService(t *testing.T, accounts ...string) *service.Service {
+	return newTestService(t, func(keystore *keystorage.Keystorage) {
+		for _, account := range accounts {
+			_, err := keystore.GeneratePrivate(account)
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, keystore.SelectPrivateKey(accounts[0]))
+	})
+}
+
+// newTestService creates a service on a simulated chain, with a keystore filled in by setup
+func newTestService(t *testing.T, setup func(keystore *keystorage.Keystorage)) *service.Service {
+	dir := t.TempDir()
+	config.Conf.Database.Storage = filepath.Join(dir, "oracle.db")
+	config.Conf.Keystorage.InsecureFastScrypt = true
+
+	keystore, err := keystorage.NewKeyStorage(logrus.New(), filepath.Join(dir, "keystore.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = keystore.GenerateToken()
+	assert.NoError(t, err)
+	setup(keystore)
+
+	thestore, err := store.NewStore(context.Background(), keystore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = thestore.Db.Migrate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := ethrpc.NewSimulatedClient(backends.NewSimulatedBackend(core.GenesisAlloc{}, 10000000))
+	chainID, err := client.ChainID(context.Background())
+	assert.NoError(t, err)
+	conn, err := chaincall.NewConnection(client, chainID,
+		"0xCfEB869F69431e42cdB54A4F4f105C19C080A601", "0x254dffcd3277C0b1660F6d42EFbB754edaBAbC2B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(conn.Close)
+
+	oracleService, err := service.NewService(context.Background(), thestore, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return oracleService
+}
+
+func TestService_Keys(t *testing.T) {
+	oracleService := newMultiKeyTestService(t, "tier1", "tier2", "tier3")
+
+	keys := oracleService.Keys()
+	assert.Len(t, keys, 3)
+
+	defaultKey, err := oracleService.KeyByAccount("")
+	assert.NoError(t, err)
+	assert.Equal(t, "tier1", defaultKey.Account)
+	assert.Equal(t, oracleService.VORCoordinatorCaller, defaultKey.Caller)
+
+	tier2, err := oracleService.KeyByAccount("tier2")
+	assert.NoError(t, err)
+	assert.NotEqual(t, defaultKey.KeyHash, tier2.KeyHash)
+	assert.NotEqual(t, defaultKey.Caller.OracleAddress(), tier2.Caller.OracleAddress())
+
+	byHash, ok := oracleService.KeyByHash(tier2.KeyHash)
+	assert.True(t, ok)
+	assert.Equal(t, "tier2", byHash.Account)
+
+	_, ok = oracleService.KeyByHash([32]byte{1})
+	assert.False(t, ok)
+	_, err = oracleService.KeyByAccount("unknown")
+	assert.Error(t, err)
+}
+
+func TestService_Keys_ServedAccounts(t *testing.T) {
+	config.Conf.Keystorage.Accounts = []string{"tier3"}
+	defer func() { config.Conf.Keystorage.Accounts = nil }()
+
+	oracleService := newMultiKeyTestService(t, "tier1", "tier2", "tier3")
+
+	// the selected key is always served, as the default
+	keys := oracleService.Keys()
+	assert.Len(t, keys, 2)
+	_, err := oracleService.KeyByAccount("tier1")
+	assert.NoError(t, err)
+	_, err = oracleService.KeyByAccount("tier3")
+	assert.NoError(t, err)
+	_, err = oracleService.KeyByAccount("tier2")
+	assert.Error(t, err)
+}
+
+func TestService_Chain(t *testing.T) {
+	oracleService := newMultiKeyTestService(t, "tier1")
+
+	// the simulated backend's chain ID
+	assert.Equal(t, int64(1337), oracleService.ChainID())
+	assert.Equal(t, "1337", oracleService.ChainName())
+	assert.Equal(t, int64(1337), oracleService.Store.Db.ChainId())
+
+	oracleService.Connection.Chain.Name = "local"
+	assert.Equal(t, "local", oracleService.ChainName())
+}
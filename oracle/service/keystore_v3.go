@@ -0,0 +1,19 @@
+package service
+
+// ImportKey adds the key in a V3 keystore file to the keystore. A sending account is used
+// straight away. A proving key is served once it's registered.
+func (d *Service) ImportKey(account string, keyJSON []byte, passphrase string, sending bool) (string, error) {
+	address, err := d.Store.Keystorage.ImportV3(account, keyJSON, passphrase, sending)
+	if err != nil {
+		return "", err
+	}
+	if sending {
+		return address, d.UseSending(account)
+	}
+	return address, nil
+}
+
+// ExportKey returns an account's key as a V3 keystore file, encrypted with passphrase
+func (d *Service) ExportKey(account string, passphrase string) ([]byte, error) {
+	return d.Store.Keystorage.ExportV3(account, passphrase)
+}
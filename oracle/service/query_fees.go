@@ -4,6 +4,10 @@ import (
 	"math/big"
 )
 
-func (d *Service) QueryFees(consumer string) (*big.Int, error) {
-	return d.VORCoordinatorCaller.QueryFees(consumer)
+func (d *Service) QueryFees(account string, consumer string) (*big.Int, error) {
+	caller, err := d.callerFor(account)
+	if err != nil {
+		return nil, err
+	}
+	return caller.QueryFees(consumer)
 }
@@ -2,6 +2,10 @@ package service
 
 import "oracle/models/database"
 
-func (d *Service) Requests(requestId, page, limit, status int, order string) ([]database.RandomnessRequest, int64, error) {
-	return d.Store.Db.GetPaginatedRequests(page, limit, status, order)
+func (d *Service) Requests(requestId, page, limit, status int, order string, account string) ([]database.RandomnessRequest, int64, error) {
+	keyHash, err := d.keyHashFilter(account)
+	if err != nil {
+		return nil, 0, err
+	}
+	return d.Store.Db.GetPaginatedRequests(page, limit, status, order, keyHash)
 }
@@ -4,6 +4,10 @@ import (
 	"math/big"
 )
 
-func (d *Service) QueryWithdrawableTokens() (*big.Int, error) {
-	return d.VORCoordinatorCaller.QueryWithdrawableTokens()
+func (d *Service) QueryWithdrawableTokens(account string) (*big.Int, error) {
+	caller, err := d.callerFor(account)
+	if err != nil {
+		return nil, err
+	}
+	return caller.QueryWithdrawableTokens()
 }
@@ -2,26 +2,60 @@ package service
 
 import (
 	"fmt"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"math/big"
-	"oracle/chaincall"
-	"oracle/config"
 )
 
+// Register registers a proving key with the VORCoordinator, and starts serving it. If
+// privateKey is empty, the account's key must already be in the keystore, e.g. imported
+// from a V3 keystore file.
 func (d *Service) Register(account string, privateKey string, fee int64) (tx *types.Transaction, err error) {
-	if d.Store.Keystorage.ExistsByUsername(account) {
+	if privateKey == "" {
+		privateKey, err = d.storedProvingKey(account)
+		if err != nil {
+			return
+		}
+	} else if d.Store.Keystorage.ExistsByUsername(account) {
 		return nil, fmt.Errorf("This account name is already used")
 	}
 
-	VORCoordinatorCallerNew, err := chaincall.NewVORCoordinatorCaller(config.Conf.VORCoordinatorContractAddress, config.Conf.BlockHashStoreContractAddress, config.Conf.EthHTTPHost, big.NewInt(config.Conf.NetworkID), []byte(privateKey))
+	VORCoordinatorCallerNew, err := d.newCaller(privateKey)
 	if err != nil {
 		return
 	}
 
-	err = d.Store.Keystorage.AddExisting(account, privateKey)
-	if err != nil {
+	if !d.Store.Keystorage.ExistsByUsername(account) {
+		err = d.Store.Keystorage.AddExisting(account, privateKey)
+		if err != nil {
+			return
+		}
+	}
+
+	provider := common.HexToAddress(VORCoordinatorCallerNew.OracleAddress())
+	tx, err = VORCoordinatorCallerNew.RegisterProvingKey(big.NewInt(fee), provider)
+	if err != nil || !isServedAccount(account) {
 		return
 	}
 
-	return VORCoordinatorCallerNew.RegisterProvingKey(big.NewInt(fee))
+	// start serving the new key straight away, rather than on the next restart
+	_, err = d.AddKey(account, privateKey)
+	return
+}
+
+// storedProvingKey returns the private key of a proving key already in the keystore
+func (d *Service) storedProvingKey(account string) (string, error) {
+	for _, key := range d.Store.Keystorage.GetAll() {
+		if key.GetAccount() != account {
+			continue
+		}
+		if key.GetSending() {
+			return "", fmt.Errorf("%s is a sending account, not a proving key", account)
+		}
+		if key.GetPrivate() == "" {
+			return "", fmt.Errorf("can't decrypt the key for %s", account)
+		}
+		return key.GetPrivate(), nil
+	}
+	return "", fmt.Errorf("%s isn't in the keystore. Import it, or pass its private key", account)
 }
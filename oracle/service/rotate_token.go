@@ -0,0 +1,7 @@
+package service
+
+// RotateToken replaces the API token, and re-encrypts the keystore under the new one.
+// Returns the new token.
+func (d *Service) RotateToken() (string, error) {
+	return d.Store.Keystorage.RotateToken()
+}
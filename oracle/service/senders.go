@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"math/big"
+	"oracle/chaincall"
+	"oracle/config"
+)
+
+const (
+	defaultTopUpThreshold = 0.1
+	defaultTopUpAmount    = 0.5
+)
+
+// loadSenders sets up the accounts which send the oracle's Txs - the provider of its keys,
+// and the pool of sending accounts which pay for fulfillments
+func (d *Service) loadSenders() error {
+	signer, err := chaincall.NewSignerFromConfig(config.Conf.Signer)
+	if err != nil {
+		return err
+	}
+	d.provider = signer
+
+	providerAccount := ""
+	if config.Conf.Keystorage != nil {
+		providerAccount = config.Conf.Keystorage.ProviderAccount
+	}
+	if providerAccount != "" {
+		if d.provider != nil {
+			return fmt.Errorf("signer and keystorage.provider_account can't both be set")
+		}
+		d.provider, err = d.sendingSigner(providerAccount)
+		if err != nil {
+			return err
+		}
+	}
+
+	d.senders, err = chaincall.NewSenderPool(d.Connection)
+	if err != nil {
+		return err
+	}
+	err = d.configureSenders()
+	if err != nil {
+		return err
+	}
+	for _, key := range d.Store.Keystorage.GetSending() {
+		err = d.UseSending(key.GetAccount())
+		if err != nil {
+			return fmt.Errorf("load sending account %s: %w", key.GetAccount(), err)
+		}
+	}
+	return nil
+}
+
+// configureSenders sets the pool's strategy, and the treasury which tops it up
+func (d *Service) configureSenders() error {
+	conf := config.Conf.Sending
+	if conf == nil {
+		return nil
+	}
+	err := d.senders.SetStrategy(conf.Strategy)
+	if err != nil {
+		return err
+	}
+	if conf.TreasuryAccount == "" {
+		return nil
+	}
+
+	treasury, err := d.sendingSigner(conf.TreasuryAccount)
+	if err != nil {
+		return err
+	}
+	threshold := conf.TopUpThreshold
+	if threshold == 0 {
+		threshold = defaultTopUpThreshold
+	}
+	amount := conf.TopUpAmount
+	if amount == 0 {
+		amount = defaultTopUpAmount
+	}
+	d.senders.SetTreasury(treasury, ethToWei(threshold), ethToWei(amount))
+	return nil
+}
+
+// ethToWei converts an ETH amount from the config into wei
+func ethToWei(eth float64) *big.Int {
+	wei, _ := new(big.Float).Mul(big.NewFloat(eth), big.NewFloat(params.Ether)).Int(nil)
+	return wei
+}
+
+// sendingSigner returns a signer for one of the keystore's sending accounts
+func (d *Service) sendingSigner(account string) (chaincall.Signer, error) {
+	for _, key := range d.Store.Keystorage.GetSending() {
+		if key.GetAccount() == account {
+			return chaincall.NewKeySigner(key.GetPrivate())
+		}
+	}
+	return nil, fmt.Errorf("%s is not a sending account in the keystore", account)
+}
+
+// isSendingAccount returns true if the sending account should pay for fulfillments. All
+// sending accounts apart from the treasury are used unless keystorage.sending_accounts
+// limits them.
+func isSendingAccount(account string) bool {
+	if config.Conf.Sending != nil && config.Conf.Sending.TreasuryAccount == account {
+		return false
+	}
+	if config.Conf.Keystorage == nil || len(config.Conf.Keystorage.SendingAccounts) == 0 {
+		return true
+	}
+	for _, sending := range config.Conf.Keystorage.SendingAccounts {
+		if sending == account {
+			return true
+		}
+	}
+	return false
+}
+
+// AddSending saves a sending account to the keystore, generating a new key if privateKey is
+// empty, and starts sending fulfillments from it. Returns the account's address, which needs
+// ETH for gas.
+func (d *Service) AddSending(account string, privateKey string) (string, error) {
+	if d.Store.Keystorage.ExistsByUsername(account) {
+		return "", fmt.Errorf("This account name is already used")
+	}
+	privateKey, err := d.Store.Keystorage.AddSending(account, privateKey)
+	if err != nil {
+		return "", err
+	}
+	signer, err := chaincall.NewKeySigner(privateKey)
+	if err != nil {
+		return "", err
+	}
+	return signer.Address().Hex(), d.UseSending(account)
+}
+
+// UseSending starts sending fulfillments from a sending account already in the keystore,
+// unless keystorage.sending_accounts excludes it
+func (d *Service) UseSending(account string) error {
+	if !isSendingAccount(account) {
+		return nil
+	}
+	signer, err := d.sendingSigner(account)
+	if err != nil {
+		return err
+	}
+	return d.senders.Add(signer)
+}
+
+// SendingAddresses returns the accounts fulfillments are sent from. If there are none, each
+// key's provider sends its own.
+func (d *Service) SendingAddresses() []string {
+	var addresses []string
+	for _, address := range d.senders.Addresses() {
+		addresses = append(addresses, address.Hex())
+	}
+	return addresses
+}
+
+// SendingAccounts returns the balance and nonces of each sending account
+func (d *Service) SendingAccounts() []chaincall.SendingAccount {
+	return d.senders.Accounts()
+}
+
+// RefreshSenders reads the balance and nonce of each sending account from the chain
+func (d *Service) RefreshSenders(ctx context.Context) error {
+	return d.senders.Refresh(ctx)
+}
+
+// TopUpSenders sends ETH from the treasury to sending accounts which are running low. It
+// does nothing if there's no treasury.
+func (d *Service) TopUpSenders(ctx context.Context) ([]*types.Transaction, error) {
+	return d.senders.TopUp(ctx)
+}
@@ -0,0 +1,79 @@
+package service_test
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"oracle/config"
+	"oracle/store/keystorage"
+	"oracle/utils"
+	"testing"
+)
+
+func addressOf(t *testing.T, privateKey string) string {
+	key, err := crypto.HexToECDSA(utils.RemoveHexPrefix(privateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return crypto.PubkeyToAddress(key.PublicKey).Hex()
+}
+
+func TestService_SendingAccounts(t *testing.T) {
+	config.Conf.Keystorage.ProviderAccount = "owner"
+	config.Conf.Keystorage.SendingAccounts = []string{"gas1", "gas2", "gas3"}
+	defer func() {
+		config.Conf.Keystorage.ProviderAccount = ""
+		config.Conf.Keystorage.SendingAccounts = nil
+	}()
+
+	var owner, gas1 string
+	oracleService := newTestService(t, func(keystore *keystorage.Keystorage) {
+		_, err := keystore.GeneratePrivate("oracle")
+		assert.NoError(t, err)
+		assert.NoError(t, keystore.SelectPrivateKey("oracle"))
+		owner, err = keystore.AddSending("owner", "")
+		assert.NoError(t, err)
+		gas1, err = keystore.AddSending("gas1", "")
+		assert.NoError(t, err)
+	})
+
+	// sending accounts aren't served as proving keys
+	keys := oracleService.Keys()
+	assert.Len(t, keys, 1)
+	assert.Equal(t, "oracle", keys[0].Account)
+	assert.Equal(t, addressOf(t, owner), keys[0].Caller.OracleAddress())
+
+	// the provider only sends fulfillments if it's listed in sending_accounts
+	assert.Equal(t, []string{addressOf(t, gas1)}, oracleService.SendingAddresses())
+
+	gas2, err := oracleService.AddSending("gas2", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{addressOf(t, gas1), gas2}, oracleService.SendingAddresses())
+	assert.Equal(t, []string{addressOf(t, gas1), gas2}, keys[0].Caller.SendingAddresses())
+
+	_, err = oracleService.AddSending("gas1", "")
+	assert.Error(t, err)
+}
+
+func TestService_SendingTreasury(t *testing.T) {
+	config.Conf.Sending = &config.Sending{Strategy: "lowest_nonce", TreasuryAccount: "treasury"}
+	defer func() { config.Conf.Sending = nil }()
+
+	var gas1 string
+	oracleService := newTestService(t, func(keystore *keystorage.Keystorage) {
+		_, err := keystore.GeneratePrivate("oracle")
+		assert.NoError(t, err)
+		assert.NoError(t, keystore.SelectPrivateKey("oracle"))
+		gas1, err = keystore.AddSending("gas1", "")
+		assert.NoError(t, err)
+		_, err = keystore.AddSending("treasury", "")
+		assert.NoError(t, err)
+	})
+
+	// the treasury tops up the sending accounts, but doesn't send fulfillments
+	assert.Equal(t, []string{addressOf(t, gas1)}, oracleService.SendingAddresses())
+	assert.NoError(t, oracleService.RefreshSenders(context.Background()))
+	accounts := oracleService.SendingAccounts()
+	assert.Len(t, accounts, 1)
+	assert.Equal(t, int64(0), accounts[0].Balance.Int64())
+}
@@ -3,43 +3,70 @@ package service
 import (
 	"context"
 	"log"
-	"math/big"
 	"oracle/chaincall"
 	"oracle/config"
 	"oracle/store"
+	"sync"
+	"time"
 )
 
 type Service struct {
-	ctx                  context.Context
-	Store                *store.Store
+	ctx        context.Context
+	Store      *store.Store
+	Connection *chaincall.Connection
+	// caller for the default key, used when a request doesn't name an account
 	VORCoordinatorCaller *chaincall.VORCoordinatorCaller
 	log                  *log.Logger
+	// unix nanoseconds of the event listener's last completed cycle
+	lastHeartbeat int64
+	// start of the listener's grace period for its first heartbeat
+	createdAt time.Time
+
+	keys   []*ProvingKey
+	keysMu sync.RWMutex
+	// registered provider of every key, which signs its fee changes and withdrawals. Either the
+	// keystorage.provider_account or an external signer. Each key is its own provider if nil
+	provider chaincall.Signer
+	// pay for gas and send fulfillments. The provider sends them if the pool is empty
+	senders *chaincall.SenderPool
 }
 
-func NewService(ctx context.Context, store *store.Store) (*Service, error) {
-	VORCoordinatorCaller, err := chaincall.NewVORCoordinatorCaller(
-		config.Conf.VORCoordinatorContractAddress,
-		config.Conf.BlockHashStoreContractAddress,
-		config.Conf.EthHTTPHost,
-		big.NewInt(config.Conf.NetworkID),
-		[]byte(store.Keystorage.GetSelectedPrivateKey()),
-	)
+// NewService creates the service for the connection's chain. Its store only reads and
+// writes the chain's requests.
+func NewService(ctx context.Context, store *store.Store, conn *chaincall.Connection) (*Service, error) {
+	if store.Db != nil {
+		store = store.ForChain(conn.ChainID.Int64())
+	}
+	service := &Service{ctx: ctx, Store: store, Connection: conn, createdAt: time.Now()}
+	err := service.resumeTxs()
+	if err != nil {
+		return nil, err
+	}
+	err = service.loadSenders()
+	if err != nil {
+		return nil, err
+	}
+	err = service.loadKeys()
 	if err != nil {
 		return nil, err
 	}
-	return &Service{ctx: ctx, Store: store, VORCoordinatorCaller: VORCoordinatorCaller}, err
+	return service, nil
 }
 
 func NewServiceFromPassedConfig(ctx context.Context, store *store.Store, conf *config.Config) (*Service, error) {
-	VORCoordinatorCaller, err := chaincall.NewVORCoordinatorCaller(
-		conf.VORCoordinatorContractAddress,
-		conf.BlockHashStoreContractAddress,
-		conf.EthHTTPHost,
-		big.NewInt(conf.NetworkID),
-		[]byte(store.Keystorage.GetSelectedPrivateKey()),
-	)
+	conn, err := chaincall.Dial(conf)
 	if err != nil {
 		return nil, err
 	}
-	return &Service{ctx: ctx, Store: store, VORCoordinatorCaller: VORCoordinatorCaller}, err
+	return NewService(ctx, store, conn)
+}
+
+// ChainName returns the name of the chain the service is for, or its chain ID if it isn't named
+func (d *Service) ChainName() string {
+	return d.Connection.Name()
+}
+
+// ChainID returns the ID of the chain the service is for
+func (d *Service) ChainID() int64 {
+	return d.Connection.ChainID.Int64()
 }
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"oracle/config"
+	"oracle/models/database"
+)
+
+// resumeTxs saves the chain's Txs to the ledger from now on, and rebroadcasts those which
+// were pending when the oracle stopped. It runs before any keys are loaded, so the nonce
+// managers see the rebroadcast Txs when they sync.
+func (d *Service) resumeTxs() error {
+	if d.Store.Db == nil {
+		return nil
+	}
+	_, err := d.Connection.SetTxManager(d.Store.Db).Resume(d.ctx)
+	return err
+}
+
+// Txs returns a page of the Txs the oracle has sent on the service's chain
+func (d *Service) Txs(page, limit int, state string, purpose string, fromAddress string, order string) ([]database.EthTx, int64, error) {
+	return d.Store.Db.GetPaginatedEthTxs(page, limit, state, purpose, fromAddress, order)
+}
+
+// TrackTxs updates the ledger's unconfirmed Txs from the chain, and returns those which were
+// confirmed, reverted or replaced
+func (d *Service) TrackTxs(ctx context.Context, headBlockNum uint64) ([]database.EthTx, error) {
+	txManager := d.Connection.TxManager()
+	if txManager == nil {
+		return nil, nil
+	}
+	return txManager.Track(ctx, headBlockNum, config.Conf.WaitConfirmations)
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"oracle/contracts/vor_coordinator"
+	"oracle/models/api"
+	"oracle/tools/secp256k1"
+	"oracle/tools/vor"
+	"strings"
+)
+
+// VerifyFulfillment decodes the proof sent in a fulfillRandomnessRequest Tx, looks up the
+// hash of the request's block, and verifies the proof.
+func (d *Service) VerifyFulfillment(txHashStr string) (api.ProofVerification, error) {
+	tx, _, err := d.VORCoordinatorCaller.GetTx(txHashStr)
+	if err != nil {
+		return api.ProofVerification{}, err
+	}
+
+	proof, err := DecodeFulfillmentInput(tx.Data())
+	if err != nil {
+		return api.ProofVerification{}, err
+	}
+
+	response, err := vor.UnmarshalProofResponse(proof)
+	if err != nil {
+		return api.ProofVerification{}, err
+	}
+
+	blockHash, err := d.VORCoordinatorCaller.GetBlockHash(response.BlockNum)
+	if err != nil {
+		return api.ProofVerification{}, err
+	}
+
+	verification := VerifyProof(proof, blockHash)
+	verification.FulfillTxHash = tx.Hash().Hex()
+	return verification, nil
+}
+
+// DecodeFulfillmentInput returns the _proof bytes from fulfillRandomnessRequest Tx input
+func DecodeFulfillmentInput(input []byte) (vor.MarshaledOnChainResponse, error) {
+	contractAbi, err := abi.JSON(strings.NewReader(vor_coordinator.VorCoordinatorABI))
+	if err != nil {
+		return vor.MarshaledOnChainResponse{}, err
+	}
+	method := contractAbi.Methods["fulfillRandomnessRequest"]
+	if len(input) < 4 || !bytes.Equal(input[:4], method.ID) {
+		return vor.MarshaledOnChainResponse{}, errors.New("not a fulfillRandomnessRequest tx")
+	}
+
+	args, err := method.Inputs.Unpack(input[4:])
+	if err != nil {
+		return vor.MarshaledOnChainResponse{}, err
+	}
+	return ProofFromBytes(args[0].([]byte))
+}
+
+// ProofFromBytes checks the length of raw proof bytes, as sent to the VORCoordinator
+func ProofFromBytes(b []byte) (vor.MarshaledOnChainResponse, error) {
+	var proof vor.MarshaledOnChainResponse
+	if len(b) != vor.OnChainResponseLength {
+		return proof, fmt.Errorf("wrong proof length %d, expected %d", len(b), vor.OnChainResponseLength)
+	}
+	copy(proof[:], b)
+	return proof, nil
+}
+
+// VerifyProof verifies a proof as sent to the VORCoordinator, given the hash of the
+// block the request was made in. It needs no chain access, so can be run offline.
+func VerifyProof(proof vor.MarshaledOnChainResponse, blockHash common.Hash) api.ProofVerification {
+	verification := api.ProofVerification{
+		BlockHash: blockHash.Hex(),
+	}
+
+	response, err := vor.UnmarshalProofResponse(proof)
+	if err != nil {
+		verification.Error = err.Error()
+		return verification
+	}
+
+	preSeed := vor.PreSeedData{
+		PreSeed:   response.PreSeed,
+		BlockHash: blockHash,
+		BlockNum:  response.BlockNum,
+	}
+	publicKey := secp256k1.LongMarshal(response.P.PublicKey)
+
+	verification.PublicKey = hexutil.Encode(publicKey)
+	verification.KeyHash = crypto.Keccak256Hash(publicKey).Hex()
+	verification.Seed = hexutil.EncodeBig(response.PreSeed.Big())
+	verification.BlockNumber = response.BlockNum
+	verification.FinalSeed = hexutil.EncodeBig(vor.FinalSeed(preSeed))
+	verification.Gamma = hexutil.Encode(secp256k1.LongMarshal(response.P.Gamma))
+	verification.Output = response.P.Output.String()
+
+	_, err = response.CryptoProof(preSeed)
+	if err != nil {
+		verification.Error = err.Error()
+		return verification
+	}
+	verification.Valid = true
+	return verification
+}
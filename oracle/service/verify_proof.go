@@ -0,0 +1,51 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go.dedis.ch/kyber/v3"
+	"oracle/tools/vor"
+)
+
+// ProofVerificationError is returned when a generated proof fails local verification.
+// Sending it would only revert, so the request should not be retried with the same data.
+type ProofVerificationError struct {
+	Err error
+}
+
+func (e *ProofVerificationError) Error() string {
+	return "proof verification failed: " + e.Err.Error()
+}
+
+func (e *ProofVerificationError) Unwrap() error {
+	return e.Err
+}
+
+// ErrRequestBlockNotCanonical is returned when the request's block hash isn't the canonical
+// chain's. A reorg may be in progress, or the node may be lagging, so it's retryable.
+var ErrRequestBlockNotCanonical = errors.New("request block is not canonical")
+
+// verifyProofResponse round trips the marshalled proof through the same decoding the
+// VORCoordinator does, and checks it proves the request's seed using the oracle's key.
+// The request block hash is checked against the canonical chain, since a proof using
+// the wrong block hash is valid locally but can never verify on chain. A mismatch returns
+// ErrRequestBlockNotCanonical rather than a ProofVerificationError.
+func (d *Service) verifyProofResponse(m vor.MarshaledOnChainResponse, s vor.PreSeedData, publicKey kyber.Point) error {
+	proof, err := vor.VerifyOnChainResponse(m, s)
+	if err != nil {
+		return &ProofVerificationError{Err: err}
+	}
+	if !proof.PublicKey.Equal(publicKey) {
+		return &ProofVerificationError{Err: fmt.Errorf("proof public key %s is not the oracle's key", proof.PublicKey)}
+	}
+
+	canonicalHash, err := d.VORCoordinatorCaller.GetBlockHash(s.BlockNum)
+	if err != nil {
+		return err
+	}
+	if canonicalHash != s.BlockHash {
+		return fmt.Errorf("%w: request block hash %s does not match canonical block %d hash %s",
+			ErrRequestBlockNotCanonical, s.BlockHash.Hex(), s.BlockNum, canonicalHash.Hex())
+	}
+	return nil
+}
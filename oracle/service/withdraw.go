@@ -5,7 +5,10 @@ import (
 	"math/big"
 )
 
-func (d *Service) Withdraw(address string, amount int64) (*types.Transaction, error) {
-
-	return d.VORCoordinatorCaller.Withdraw(address, big.NewInt(amount))
+func (d *Service) Withdraw(account string, address string, amount int64) (*types.Transaction, error) {
+	caller, err := d.callerFor(account)
+	if err != nil {
+		return nil, err
+	}
+	return caller.Withdraw(address, big.NewInt(amount))
 }
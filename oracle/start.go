@@ -3,14 +3,18 @@ package main
 import (
 	"context"
 	"fmt"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/sirupsen/logrus"
 	"math/big"
 	"net/http"
+	"oracle/chaincall"
 	"oracle/config"
 	controller "oracle/controller/api"
 	"oracle/controller/chainlisten"
+	"oracle/metrics"
+	"oracle/models/database"
 	"oracle/service"
 	store2 "oracle/store"
 	"oracle/store/keystorage"
@@ -65,74 +69,226 @@ func start() (err error) {
 		return err
 	}
 
-	decryptPassword := ""
-	if options.PasswordFile != "" {
-		decryptPassword = getPasswordFromFileOrFlag(options.PasswordFile)
+	err = unlock(keystore)
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"package":  "main",
+			"function": "start",
+			"action":   "check keystore token",
+		}).Error(err.Error())
+		return err
+	}
+
+	err = keystore.SelectPrivateKey(config.Conf.Keystorage.Account)
+	if err != nil {
+		return err
 	}
 
-	if decryptPassword == "" || (keystore.CheckToken(decryptPassword) != nil) {
-		err = auth(keystore)
+	var oracleServices []*service.Service
+	for i, chain := range config.Conf.GetChains() {
+		conn, err := chaincall.DialChain(chain)
+		if err != nil {
+			log.WithFields(logrus.Fields{
+				"package":  "main",
+				"function": "start",
+				"action":   "connect to eth provider",
+				"chain":    chain.Name,
+			}).Error(err.Error())
+			return err
+		}
+		defer conn.Close()
+
+		// the DB was only used for one chain before chain IDs were stored, so existing rows
+		// belong to the first
+		if i == 0 {
+			err = store.Db.BackfillChainId(conn.ChainID.Int64())
+			if err != nil {
+				log.WithFields(logrus.Fields{
+					"package":  "main",
+					"function": "start",
+					"action":   "backfill chain id",
+					"chain":    conn.Name(),
+				}).Error(err.Error())
+				return err
+			}
+
+			// older versions kept the last checked block in the keystore, so carry it over
+			// rather than rescanning from the last request
+			cursor, _ := store.Db.GetListenerCursor(conn.VORCoordinatorAddress.Hex(), conn.ChainID.Int64())
+			if blockNumber := keystore.GetLegacyBlockNumber(); blockNumber > 0 && cursor.GetBlockNumber() == 0 {
+				err = store.Db.SetListenerCursor(conn.VORCoordinatorAddress.Hex(), conn.ChainID.Int64(), uint64(blockNumber))
+				if err != nil {
+					log.WithFields(logrus.Fields{
+						"package":  "main",
+						"function": "start",
+						"action":   "seed listener cursor",
+						"chain":    conn.Name(),
+					}).Error(err.Error())
+					return err
+				}
+			}
+		}
+
+		oracleService, err := service.NewService(ctx, store, conn)
 		if err != nil {
 			log.WithFields(logrus.Fields{
 				"package":  "main",
 				"function": "start",
-				"action":   "check keystore token",
+				"action":   "init service",
+				"chain":    conn.Name(),
 			}).Error(err.Error())
 			return err
 		}
+		for _, key := range oracleService.Keys() {
+			log.WithFields(logrus.Fields{
+				"package":  "main",
+				"function": "start",
+				"action":   "serve proving key",
+				"chain":    conn.Name(),
+				"account":  key.Account,
+				"key_hash": key.KeyHashHex(),
+				"address":  key.Caller.OracleAddress(),
+			}).Info()
+		}
+		for _, address := range oracleService.SendingAddresses() {
+			log.WithFields(logrus.Fields{
+				"package":  "main",
+				"function": "start",
+				"action":   "send from account",
+				"chain":    conn.Name(),
+				"address":  address,
+			}).Info()
+		}
+		oracleServices = append(oracleServices, oracleService)
 	}
 
-	err = keystore.SelectPrivateKey(config.Conf.Keystorage.Account)
-	if err != nil {
-		return err
+	for _, oracleService := range oracleServices {
+		registerProvingKey(ctx, keystore, oracleService, fee)
 	}
 
-	oracleService, err := service.NewService(ctx, store)
-	if err != nil {
-		log.WithFields(logrus.Fields{
-			"package":  "main",
-			"function": "start",
-			"action":   "init service",
-		}).Error(err.Error())
-		return err
+	chainNames := make(map[int64]string, len(oracleServices))
+	for _, oracleService := range oracleServices {
+		chainNames[oracleService.ChainID()] = oracleService.ChainName()
 	}
-	if !keystore.IsRegisteredByPrivate(keystore.KeyStore.PrivateKey) {
-		tx, err := oracleService.VORCoordinatorCaller.RegisterProvingKey(big.NewInt(fee))
-		if tx != nil || err == nil {
-			keystore.SetRegistered(keystore.KeyStore.PrivateKey)
+	metrics.Register(store.Db, chainNames)
+
+	oracleController, err := controller.NewOracle(ctx, log, oracleServices)
+	for _, oracleService := range oracleServices {
+		oracleListener, err := chainlisten.NewVORCoordinatorListener(oracleService.Connection, oracleService, log, ctx)
+		if err != nil {
+			log.WithFields(logrus.Fields{
+				"package":  "main",
+				"function": "start",
+				"action":   "init listener",
+				"chain":    oracleService.ChainName(),
+			}).Error(err.Error())
+			return err
 		}
+		oracleListeners = append(oracleListeners, oracleListener)
+		go oracleListener.StartPoll()
 	}
 
-	oracleController, err := controller.NewOracle(ctx, log, oracleService)
-	oracleListener, err = chainlisten.NewVORCoordinatorListener(config.Conf.VORCoordinatorContractAddress, config.Conf.EthHTTPHost, oracleService, log, ctx)
-	go oracleListener.StartPoll()
-
 	// Middleware
 	e.Use(middleware.Recover())
-	e.Use(middleware.KeyAuth(func(key string, c echo.Context) (bool, error) {
-		return key == keystore.KeyStore.Token, nil
+	e.Use(middleware.KeyAuthWithConfig(middleware.KeyAuthConfig{
+		// orchestration probes can't usually send the API key
+		Skipper: func(c echo.Context) bool {
+			return c.Path() == "/healthz" || c.Path() == "/readyz"
+		},
+		Validator: oracleController.ValidateKey,
 	}))
 
-	e.POST("/withdraw", oracleController.Withdraw)
-	e.POST("/register", oracleController.Register)
-	e.POST("/changefee", oracleController.ChangeFee)
-	e.POST("/changegranularfee", oracleController.ChangeGranularFee)
+	read := controller.RequireScope(database.API_SCOPE_READ)
+	fees := controller.RequireScope(database.API_SCOPE_FEES)
+	funds := controller.RequireScope(database.API_SCOPE_FUNDS)
+	admin := controller.RequireScope(database.API_SCOPE_ADMIN)
+	// decrypting keys and rotating the token need the keystore token itself
+	keystoreOnly := controller.RequireScope(database.API_SCOPE_KEYSTORE)
+
+	e.POST("/withdraw", oracleController.Withdraw, funds)
+	e.POST("/register", oracleController.Register, admin)
+	e.POST("/addsending", oracleController.AddSending, admin)
+	e.POST("/keys/import", oracleController.ImportKey, admin)
+	e.POST("/keys/export", oracleController.ExportKey, keystoreOnly)
+	e.POST("/rotatetoken", oracleController.RotateToken, keystoreOnly)
+	e.GET("/credentials", oracleController.Credentials, admin)
+	e.POST("/credentials", oracleController.CreateCredential, admin)
+	e.POST("/credentials/revoke", oracleController.RevokeCredential, admin)
+	e.POST("/changefee", oracleController.ChangeFee, fees)
+	e.POST("/changegranularfee", oracleController.ChangeGranularFee, fees)
 	e.POST("/stop", func(c echo.Context) error {
 		err = Stop()
 		return err
-	})
-	e.POST("/queryfees", oracleController.QueryFees)
-	e.GET("/about", oracleController.About)
+	}, admin)
+	e.POST("/queryfees", oracleController.QueryFees, read)
+	e.GET("/about", oracleController.About, read)
 	e.GET("/status", func(c echo.Context) error {
 		return c.String(http.StatusOK, "alive")
-	})
-	e.GET("/querywithdrawable", oracleController.QueryWithdrawableTokens)
-	e.GET("/requests", oracleController.QueryRequests)
-	e.GET("/analytics", oracleController.Analytics)
-	e.GET("/consumers", oracleController.Consumers)
-	e.GET("/tx", oracleController.GetTxInfo)
+	}, read)
+	e.GET("/querywithdrawable", oracleController.QueryWithdrawableTokens, read)
+	e.GET("/requests", oracleController.QueryRequests, read)
+	e.GET("/txs", oracleController.QueryTxs, read)
+	e.GET("/analytics", oracleController.Analytics, read)
+	e.GET("/consumers", oracleController.Consumers, read)
+	e.GET("/tx", oracleController.GetTxInfo, read)
+	e.GET("/verify", oracleController.VerifyProof, read)
+	e.GET("/metrics", echo.WrapHandler(metrics.Handler()), read)
+	e.GET("/healthz", oracleController.Healthz)
+	e.GET("/readyz", oracleController.Readyz)
 
 	e.Logger.Fatal(e.Start(fmt.Sprintf("%s:%d", config.Conf.Serve.Host, config.Conf.Serve.Port)))
 
 	return err
 }
+
+// registerProvingKey registers the selected proving key with the service's VORCoordinator,
+// unless it already has been. Each chain is recorded in the keystore once it's registered,
+// so a chain which fails is retried on the next start.
+func registerProvingKey(ctx context.Context, keystore *keystorage.Keystorage, oracleService *service.Service, fee int64) {
+	privateKey := keystore.KeyStore.PrivateKey
+	chainId := oracleService.ChainID()
+	if keystore.IsRegisteredOnChain(privateKey, chainId) {
+		return
+	}
+
+	caller := oracleService.VORCoordinatorCaller
+	registered, err := caller.IsProvingKeyRegistered(ctx)
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"package":  "main",
+			"function": "registerProvingKey",
+			"action":   "check proving key registration",
+			"chain":    oracleService.ChainName(),
+		}).Error(err.Error())
+		return
+	}
+	if !registered {
+		tx, err := caller.RegisterProvingKey(big.NewInt(fee), common.HexToAddress(caller.OracleAddress()))
+		if err != nil {
+			log.WithFields(logrus.Fields{
+				"package":  "main",
+				"function": "registerProvingKey",
+				"action":   "register proving key",
+				"chain":    oracleService.ChainName(),
+			}).Error(err.Error())
+			return
+		}
+		log.WithFields(logrus.Fields{
+			"package":  "main",
+			"function": "registerProvingKey",
+			"action":   "register proving key",
+			"chain":    oracleService.ChainName(),
+			"tx_hash":  tx.Hash().Hex(),
+		}).Info("registration tx sent")
+	}
+
+	err = keystore.SetRegisteredOnChain(privateKey, chainId)
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"package":  "main",
+			"function": "registerProvingKey",
+			"action":   "save registration",
+			"chain":    oracleService.ChainName(),
+		}).Error(err.Error())
+	}
+}
@@ -0,0 +1,39 @@
+package db
+
+import (
+	"gorm.io/gorm"
+	"oracle/models/database"
+)
+
+// API credentials are shared by every chain, so these queries aren't scoped
+
+func (d *DB) InsertApiCredential(credential *database.ApiCredential) error {
+	return d.Create(credential).Error
+}
+
+// GetApiCredentialByHash returns the credential whose key has the hash
+func (d *DB) GetApiCredentialByHash(keyHash string) (database.ApiCredential, error) {
+	credential := database.ApiCredential{}
+	err := d.Where("key_hash = ?", keyHash).First(&credential).Error
+	return credential, err
+}
+
+// GetApiCredentials returns every credential, oldest first
+func (d *DB) GetApiCredentials() ([]database.ApiCredential, error) {
+	var credentials = []database.ApiCredential{}
+	err := d.Order("id asc").Find(&credentials).Error
+	return credentials, err
+}
+
+// DeleteApiCredential revokes a credential. Returns gorm.ErrRecordNotFound if there's no
+// credential with the name.
+func (d *DB) DeleteApiCredential(name string) error {
+	result := d.Unscoped().Where("name = ?", name).Delete(&database.ApiCredential{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 func (d *DB) InsertNewStoredBlock(blockHash string,
 	blockNumber uint64, txHash string) (err error) {
 	err = d.Create(&database.BlocksStored{
+		ChainId:     d.chainId,
 		BlockHash:   blockHash,
 		BlockNumber: blockNumber,
 		TxHash:      txHash,
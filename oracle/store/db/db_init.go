@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"gorm.io/driver/postgres"
@@ -12,6 +13,9 @@ import (
 
 type DB struct {
 	*gorm.DB
+	// chain the DB's requests, failed fulfilments and stored blocks are read from and
+	// tagged with. Zero reads all chains.
+	chainId int64
 }
 
 func NewDb() (*DB, error) {
@@ -27,8 +31,17 @@ func NewDb() (*DB, error) {
 
 func NewSqliteDb() (*DB, error) {
 	db, err := gorm.Open(sqlite.Open(config.Conf.Database.Storage), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+	// sqlite only allows one writer. Serialise access, since fulfillment jobs run concurrently
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(1)
 	return &DB{
-		db,
+		DB: db,
 	}, err
 }
 
@@ -47,10 +60,63 @@ func NewPostgresDb() (*DB, error) {
 		return nil, err
 	}
 
-	return &DB{db}, nil
+	return &DB{DB: db}, nil
+}
+
+// ForChain returns a DB sharing the connection, whose queries are limited to rows for the
+// given chain, and which tags new rows with it
+func (d *DB) ForChain(chainId int64) *DB {
+	return &DB{DB: d.DB, chainId: chainId}
+}
+
+// ChainId returns the chain the DB is limited to, or zero if it reads all chains
+func (d DB) ChainId() int64 {
+	return d.chainId
+}
+
+// scoped limits a query to the DB's chain
+func (d DB) scoped() *gorm.DB {
+	if d.chainId == 0 {
+		return d.DB
+	}
+	return d.DB.Where("chain_id = ?", d.chainId)
 }
 
 func (d DB) Migrate() (err error) {
-	err = d.AutoMigrate(&database.RandomnessRequest{}, &database.FailedFulfilment{}, &database.BlocksStored{})
+	// request IDs are only unique per chain
+	if d.Migrator().HasIndex(&database.RandomnessRequest{}, "idx_randomness_requests_request_id") {
+		err = d.Migrator().DropIndex(&database.RandomnessRequest{}, "idx_randomness_requests_request_id")
+		if err != nil {
+			return
+		}
+	}
+	err = d.AutoMigrate(&database.RandomnessRequest{}, &database.FailedFulfilment{}, &database.BlocksStored{}, &database.ListenerCursor{}, &database.EthTx{}, &database.ApiCredential{})
 	return
 }
+
+// BackfillChainId tags rows stored before chain IDs were recorded with the given chain
+func (d DB) BackfillChainId(chainId int64) error {
+	for _, model := range []interface{}{&database.RandomnessRequest{}, &database.FailedFulfilment{}, &database.BlocksStored{}} {
+		err := d.Model(model).
+			Where("chain_id = 0 OR chain_id IS NULL").
+			Update("chain_id", chainId).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Ping checks the DB connection, and that a query can be run within the context's deadline.
+// sqlite connections are serialised, so a query which times out indicates the DB is locked.
+func (d DB) Ping(ctx context.Context) error {
+	sqlDB, err := d.DB.DB()
+	if err != nil {
+		return err
+	}
+	err = sqlDB.PingContext(ctx)
+	if err != nil {
+		return err
+	}
+	return d.WithContext(ctx).Exec("SELECT 1").Error
+}
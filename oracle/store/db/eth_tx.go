@@ -0,0 +1,56 @@
+package db
+
+import (
+	"fmt"
+	"oracle/models/database"
+)
+
+// InsertEthTx adds a Tx to the ledger, tagged with the DB's chain
+func (d *DB) InsertEthTx(tx *database.EthTx) error {
+	tx.ChainId = d.chainId
+	return d.Create(tx).Error
+}
+
+func (d *DB) UpdateEthTx(tx *database.EthTx) error {
+	return d.Save(tx).Error
+}
+
+// GetEthTxByHash returns the ledger entry for a Tx hash
+func (d *DB) GetEthTxByHash(txHash string) (database.EthTx, error) {
+	tx := database.EthTx{}
+	err := d.scoped().Where("tx_hash = ?", txHash).First(&tx).Error
+	return tx, err
+}
+
+// GetPendingEthTxs returns the Txs which are waiting to be broadcast or confirmed, oldest first
+func (d *DB) GetPendingEthTxs() ([]database.EthTx, error) {
+	var txs = []database.EthTx{}
+	err := d.scoped().
+		Where("state IN ?", []string{database.ETH_TX_STATE_UNBROADCAST, database.ETH_TX_STATE_UNCONFIRMED}).
+		Order("id asc").
+		Find(&txs).Error
+	return txs, err
+}
+
+// GetPaginatedEthTxs returns a page of the Tx ledger. Empty state, purpose and from
+// addresses don't filter.
+func (d *DB) GetPaginatedEthTxs(page, limit int, state string, purpose string, fromAddress string, order string) ([]database.EthTx, int64, error) {
+	var count int64
+	var txs = []database.EthTx{}
+
+	where := map[string]interface{}{}
+	if state != "" {
+		where["state"] = state
+	}
+	if purpose != "" {
+		where["purpose"] = purpose
+	}
+	if fromAddress != "" {
+		where["from_address"] = fromAddress
+	}
+
+	d.scoped().Model(&database.EthTx{}).Where(where).Count(&count)
+	err := d.scoped().Scopes(Paginate(page, limit)).Where(where).Order(fmt.Sprintf("id %s", order)).Find(&txs).Error
+
+	return txs, count, err
+}
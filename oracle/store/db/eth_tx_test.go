@@ -0,0 +1,68 @@
+package db_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"oracle/models/database"
+	"oracle/store"
+	"oracle/store/keystorage"
+	"testing"
+	"time"
+)
+
+func TestEthTxStore_Ledger(t *testing.T) {
+	var err error
+	keystore, err = keystorage.NewKeyStorage(Log, "../../test_data/generic_keystore.json")
+	if err != nil || keystore == nil {
+		t.Error(err)
+	}
+	thestore, err := store.NewStore(context.Background(), keystore)
+	if err != nil || thestore == nil {
+		t.Fatal(err)
+	}
+	err = thestore.Db.Migrate()
+	if err != nil {
+		t.Error(err)
+	}
+
+	// a chain no other run has used, so the ledger starts empty
+	chainId := time.Now().UnixNano()
+	chainDb := thestore.Db.ForChain(chainId)
+	otherDb := thestore.Db.ForChain(chainId + 1)
+
+	from := "0xCfEB869F69431e42cdB54A4F4f105C19C080A601"
+	fulfillment := &database.EthTx{FromAddress: from, Nonce: 0, TxHash: "0x01", Raw: []byte{0x01},
+		Purpose: database.ETH_TX_PURPOSE_FULFILLMENT, State: database.ETH_TX_STATE_UNBROADCAST}
+	topUp := &database.EthTx{FromAddress: from, Nonce: 1, TxHash: "0x02", Raw: []byte{0x02},
+		Purpose: database.ETH_TX_PURPOSE_TOP_UP, State: database.ETH_TX_STATE_UNBROADCAST}
+	assert.NoError(t, chainDb.InsertEthTx(fulfillment))
+	assert.NoError(t, chainDb.InsertEthTx(topUp))
+	assert.NoError(t, otherDb.InsertEthTx(&database.EthTx{FromAddress: from, TxHash: "0x03",
+		State: database.ETH_TX_STATE_UNBROADCAST}))
+	assert.Equal(t, chainId, fulfillment.GetChainId())
+
+	fulfillment.State = database.ETH_TX_STATE_CONFIRMED
+	assert.NoError(t, chainDb.UpdateEthTx(fulfillment))
+
+	pending, err := chainDb.GetPendingEthTxs()
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, "0x02", pending[0].GetTxHash())
+	assert.Equal(t, []byte{0x02}, pending[0].GetRaw())
+
+	tx, err := chainDb.GetEthTxByHash("0x01")
+	assert.NoError(t, err)
+	assert.Equal(t, database.ETH_TX_STATE_CONFIRMED, tx.GetState())
+	_, err = chainDb.GetEthTxByHash("0x03")
+	assert.Error(t, err)
+
+	txs, count, err := chainDb.GetPaginatedEthTxs(1, 10, "", "", "", "desc")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+	assert.Equal(t, "0x02", txs[0].GetTxHash())
+
+	txs, count, err = chainDb.GetPaginatedEthTxs(1, 10, "", database.ETH_TX_PURPOSE_FULFILLMENT, from, "asc")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.Equal(t, "0x01", txs[0].GetTxHash())
+}
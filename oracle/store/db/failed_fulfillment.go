@@ -6,6 +6,7 @@ import (
 
 func (d *DB) InsertNewFailedFulfilment(requestId string, txHash string, gasUsed uint64, gasPrice uint64, reason string) (err error) {
 	err = d.Create(&database.FailedFulfilment{
+		ChainId:    d.chainId,
 		RequestId:  requestId,
 		TxHash:     txHash,
 		GasUsed: gasUsed,
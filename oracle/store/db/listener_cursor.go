@@ -0,0 +1,25 @@
+package db
+
+import (
+	"oracle/models/database"
+	"strings"
+)
+
+func (d *DB) GetListenerCursor(contractAddress string, chainId int64) (database.ListenerCursor, error) {
+	cursor := database.ListenerCursor{}
+	err := d.Where("contract_address = ? AND chain_id = ?", strings.ToLower(contractAddress), chainId).First(&cursor).Error
+	return cursor, err
+}
+
+func (d *DB) SetListenerCursor(contractAddress string, chainId int64, blockNumber uint64) error {
+	cursor := database.ListenerCursor{}
+	err := d.Where(database.ListenerCursor{
+		ContractAddress: strings.ToLower(contractAddress),
+		ChainId:         chainId,
+	}).FirstOrInit(&cursor).Error
+	if err != nil {
+		return err
+	}
+	cursor.BlockNumber = blockNumber
+	return d.Save(&cursor).Error
+}
@@ -0,0 +1,42 @@
+package db_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"oracle/store"
+	"oracle/store/keystorage"
+	"testing"
+)
+
+func TestListenerCursorStore_SetAndGet(t *testing.T) {
+	var err error
+	keystore, err = keystorage.NewKeyStorage(Log, "../../test_data/generic_keystore.json")
+	if err != nil || keystore == nil {
+		t.Error(err)
+	}
+	thestore, err := store.NewStore(context.Background(), keystore)
+	if err != nil || thestore == nil {
+		t.Fatal(err)
+	}
+	err = thestore.Db.Migrate()
+	if err != nil {
+		t.Error(err)
+	}
+
+	contractAddress := "0xCfEB869F69431e42cdB54A4F4f105C19C080A601"
+
+	err = thestore.Db.SetListenerCursor(contractAddress, 696969, 100)
+	assert.NoError(t, err)
+	err = thestore.Db.SetListenerCursor(contractAddress, 696969, 150)
+	assert.NoError(t, err)
+	err = thestore.Db.SetListenerCursor(contractAddress, 1, 20)
+	assert.NoError(t, err)
+
+	cursor, err := thestore.Db.GetListenerCursor(contractAddress, 696969)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(150), cursor.GetBlockNumber())
+
+	cursor, err = thestore.Db.GetListenerCursor(contractAddress, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(20), cursor.GetBlockNumber())
+}
@@ -8,13 +8,16 @@ import (
 
 func (d *DB) InsertNewRequest(keyHash string,
 	sender string, requestId string, status int,
-	txHash string, gasUsed uint64, gasPrice uint64,
-	fee uint64) (err error) {
+	txHash string, blockHash string, blockNumber uint64,
+	gasUsed uint64, gasPrice uint64, fee uint64) (err error) {
 	err = d.Omit("FulfilTx").Create(&database.RandomnessRequest{
+		ChainId:             d.chainId,
 		KeyHash:             keyHash,
 		Sender:              sender,
 		RequestId:           requestId,
 		RequestTxHash:       txHash,
+		RequestBlockHash:    blockHash,
+		RequestBlockNumber:  blockNumber,
 		RequestGasUsed:      gasUsed,
 		RequestGasPrice:     gasPrice,
 		Fee:                 fee,
@@ -26,7 +29,7 @@ func (d *DB) InsertNewRequest(keyHash string,
 
 func (d *DB) UpdateRequestBlockAndSeed(requestId string, blockHash string, seed string, blockNumber uint64) error {
 	req := database.RandomnessRequest{}
-	err := d.Where("request_id = ?", requestId).First(&req).Error
+	err := d.scoped().Where("request_id = ?", requestId).First(&req).Error
 	if err != nil {
 		return err
 	}
@@ -41,7 +44,7 @@ func (d *DB) UpdateRequestBlockAndSeed(requestId string, blockHash string, seed
 
 func (d *DB) UpdateRequestStatus(requestId string, status int, statusReason string) error {
 	req := database.RandomnessRequest{}
-	err := d.Where("request_id = ?", requestId).First(&req).Error
+	err := d.scoped().Where("request_id = ?", requestId).First(&req).Error
 	if err != nil {
 		return err
 	}
@@ -57,9 +60,16 @@ func (d *DB) UpdateRequestStatus(requestId string, status int, statusReason stri
 	return err
 }
 
+// UpdateStatusReason sets the reason for a request's current status without changing it
+func (d *DB) UpdateStatusReason(requestId string, statusReason string) error {
+	return d.scoped().Model(&database.RandomnessRequest{}).
+		Where("request_id = ?", requestId).
+		Update("status_reason", statusReason).Error
+}
+
 func (d *DB) UpdateFulfilmentSent(requestId string, status int, txHash string, blockNum uint64) error {
 	req := database.RandomnessRequest{}
-	err := d.Where("request_id = ?", requestId).First(&req).Error
+	err := d.scoped().Where("request_id = ?", requestId).First(&req).Error
 	if err != nil {
 		return err
 	}
@@ -67,6 +77,24 @@ func (d *DB) UpdateFulfilmentSent(requestId string, status int, txHash string, b
 	req.Status = status
 	req.FulfillTxHash = txHash
 	req.FulfillmentAttempts = req.FulfillmentAttempts + 1
+	req.GasBumps = 0
+	err = d.Save(&req).Error
+
+	return err
+}
+
+// UpdateFulfilmentReplaced records a fulfillment Tx being replaced by one with the same
+// nonce and a higher gas price. It's the same fulfillment attempt, so only the gas bumps
+// are counted.
+func (d *DB) UpdateFulfilmentReplaced(requestId string, txHash string, blockNum uint64) error {
+	req := database.RandomnessRequest{}
+	err := d.scoped().Where("request_id = ?", requestId).First(&req).Error
+	if err != nil {
+		return err
+	}
+	req.LastFulfillSentBlockNumber = blockNum
+	req.FulfillTxHash = txHash
+	req.GasBumps = req.GasBumps + 1
 	err = d.Save(&req).Error
 
 	return err
@@ -92,24 +120,29 @@ func Paginate(page, pageSize int) func(db *gorm.DB) *gorm.DB {
 
 func (d *DB) FindByRequestId(requestId string) (database.RandomnessRequest, error) {
 	result := database.RandomnessRequest{}
-	err := d.Where("request_id = ?", requestId).First(&result).Error
+	err := d.scoped().Where("request_id = ?", requestId).First(&result).Error
 	return result, err
 }
 
-func (d *DB) GetPaginatedRequests(page, limit, status int, order string) ([]database.RandomnessRequest, int64, error) {
+// GetPaginatedRequests returns a page of requests. A negative status returns all statuses, and
+// an empty keyHash returns requests for all keys.
+func (d *DB) GetPaginatedRequests(page, limit, status int, order string, keyHash string) ([]database.RandomnessRequest, int64, error) {
 	var count int64
 	var err error
 
 	var requests = []database.RandomnessRequest{}
 
+	where := map[string]interface{}{}
 	if status >= 0 {
-		d.Table("randomness_requests").Where("status = ?", status).Count(&count)
-		err = d.Scopes(Paginate(page, limit)).Where("status = ?", status).Order(fmt.Sprintf("id %s", order)).Find(&requests).Error
-	} else {
-		d.Table("randomness_requests").Count(&count)
-		err = d.Scopes(Paginate(page, limit)).Order(fmt.Sprintf("id %s", order)).Find(&requests).Error
+		where["status"] = status
+	}
+	if len(keyHash) > 0 {
+		where["key_hash"] = keyHash
 	}
 
+	d.scoped().Table("randomness_requests").Where(where).Count(&count)
+	err = d.scoped().Scopes(Paginate(page, limit)).Where(where).Order(fmt.Sprintf("id %s", order)).Find(&requests).Error
+
 	return requests, count, err
 }
 
@@ -118,7 +151,7 @@ func (d *DB) UpdateFulfillment(requestId string,
 	blockNumber uint64, txHash string, gasUsed uint64, gasPrice uint64) error {
 
 	req := database.RandomnessRequest{}
-	err := d.Where("request_id = ?", requestId).First(&req).Error
+	err := d.scoped().Where("request_id = ?", requestId).First(&req).Error
 	if err != nil {
 		return err
 	}
@@ -138,74 +171,144 @@ func (d *DB) UpdateFulfillment(requestId string,
 
 func (d DB) GetLast() (database.RandomnessRequest, error) {
 	request := database.RandomnessRequest{}
-	err := d.Where("status != ?", database.REQUEST_STATUS_SUCCESS).Last(&request).Error
+	err := d.scoped().Where("status != ?", database.REQUEST_STATUS_SUCCESS).Last(&request).Error
 	if err != nil {
-		err = d.Last(&request).Error
+		err = d.scoped().Last(&request).Error
 	}
 	return request, err
 }
 
-func (d DB) GetLastXRequests(limit int, consumer string) ([]database.RandomnessRequest, error) {
+func (d DB) GetLastXRequests(limit int, consumer string, keyHash string) ([]database.RandomnessRequest, error) {
 	var requests = []database.RandomnessRequest{}
 	var err error
 
 	where := map[string]interface{}{"status": database.REQUEST_STATUS_SUCCESS}
 	if len(consumer) > 0 {
-		where = map[string]interface{}{"status": database.REQUEST_STATUS_SUCCESS, "sender": consumer}
+		where["sender"] = consumer
+	}
+	if len(keyHash) > 0 {
+		where["key_hash"] = keyHash
 	}
 
 	if limit > 0 {
-		err = d.Where(where).Order(fmt.Sprintf("id %s", "desc")).Limit(limit).Find(&requests).Error
+		err = d.scoped().Where(where).Order(fmt.Sprintf("id %s", "desc")).Limit(limit).Find(&requests).Error
 	} else {
-		err = d.Where(where).Order(fmt.Sprintf("id %s", "desc")).Find(&requests).Error
+		err = d.scoped().Where(where).Order(fmt.Sprintf("id %s", "desc")).Find(&requests).Error
 	}
 	return requests, err
 }
 
-func (d DB) GetMostGasUsed() (database.RandomnessRequest, error) {
+func (d DB) GetMostGasUsed(keyHash string) (database.RandomnessRequest, error) {
 	request := database.RandomnessRequest{}
-	err := d.Where("status = ?", database.REQUEST_STATUS_SUCCESS).Order(fmt.Sprintf("fulfill_gas_used %s", "desc")).Limit(1).First(&request).Error
+	err := d.scoped().Where(successfulRequestsFor(keyHash)).Order(fmt.Sprintf("fulfill_gas_used %s", "desc")).Limit(1).First(&request).Error
 	return request, err
 }
 
-func (d DB) GetLeastGasUsed() (database.RandomnessRequest, error) {
+func (d DB) GetLeastGasUsed(keyHash string) (database.RandomnessRequest, error) {
 	request := database.RandomnessRequest{}
-	err := d.Where("status = ?", database.REQUEST_STATUS_SUCCESS).Order(fmt.Sprintf("fulfill_gas_used %s", "asc")).Limit(1).First(&request).Error
+	err := d.scoped().Where(successfulRequestsFor(keyHash)).Order(fmt.Sprintf("fulfill_gas_used %s", "asc")).Limit(1).First(&request).Error
 	return request, err
 }
 
+// successfulRequestsFor filters successful requests, for a single key if keyHash is set
+func successfulRequestsFor(keyHash string) map[string]interface{} {
+	where := map[string]interface{}{"status": database.REQUEST_STATUS_SUCCESS}
+	if len(keyHash) > 0 {
+		where["key_hash"] = keyHash
+	}
+	return where
+}
+
+// CountRequestsByChainAndStatus returns the number of requests in each status, by chain ID
+func (d DB) CountRequestsByChainAndStatus() (map[int64]map[int]int64, error) {
+	var rows []struct {
+		ChainId int64
+		Status  int
+		Count   int64
+	}
+	err := d.scoped().Model(&database.RandomnessRequest{}).
+		Select("chain_id, status, count(*) as count").
+		Group("chain_id, status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[int64]map[int]int64)
+	for _, row := range rows {
+		if counts[row.ChainId] == nil {
+			counts[row.ChainId] = make(map[int]int64)
+		}
+		counts[row.ChainId][row.Status] = row.Count
+	}
+	return counts, nil
+}
+
 func (d DB) GetByStatus(status int) ([]database.RandomnessRequest, error) {
 	var requests = []database.RandomnessRequest{}
-	err := d.Where("status = ?", status).Order(fmt.Sprintf("id %s", "asc")).Find(&requests).Error
+	err := d.scoped().Where("status = ?", status).Order(fmt.Sprintf("id %s", "asc")).Find(&requests).Error
 	return requests, err
 }
 
 func (d DB) GetInitialisedRequests() ([]database.RandomnessRequest, error) {
 	var requests = []database.RandomnessRequest{}
-	err := d.Where("status = ?", database.REQUEST_STATUS_INITIALISED).Order(fmt.Sprintf("id %s", "asc")).Find(&requests).Error
+	err := d.scoped().Where("status = ?", database.REQUEST_STATUS_INITIALISED).Order(fmt.Sprintf("id %s", "asc")).Find(&requests).Error
 	return requests, err
 }
 
 func (d DB) GetStuckOrFailedTx() ([]database.RandomnessRequest, error) {
 	var requests = []database.RandomnessRequest{}
-	err := d.Where("status = ? OR status = ?", database.REQUEST_STATUS_SENT, database.REQUEST_STATUS_TX_FAILED).Order(fmt.Sprintf("id %s", "asc")).Find(&requests).Error
+	err := d.scoped().Where("status = ? OR status = ?", database.REQUEST_STATUS_SENT, database.REQUEST_STATUS_TX_FAILED).Order(fmt.Sprintf("id %s", "asc")).Find(&requests).Error
 	return requests, err
 }
 
 func (d DB) GetJobs() ([]database.RandomnessRequest, error) {
 	var requests = []database.RandomnessRequest{}
-	err := d.Where("status = ? OR status = ? OR status = ?", database.REQUEST_STATUS_INITIALISED, database.REQUEST_STATUS_SENT, database.REQUEST_STATUS_TX_FAILED).Order(fmt.Sprintf("id %s", "asc")).Find(&requests).Error
+	err := d.scoped().Where("status = ? OR status = ? OR status = ? OR status = ?", database.REQUEST_STATUS_INITIALISED, database.REQUEST_STATUS_SENT, database.REQUEST_STATUS_TX_FAILED, database.REQUEST_STATUS_REORGED).Order(fmt.Sprintf("id %s", "asc")).Find(&requests).Error
 	return requests, err
 }
 
-func (d DB) GetDistinctConsumers(consumer string) ([]database.RandomnessRequest, error) {
+// RollbackReorgedRequests flags requests which relied on blocks after ancestorBlockNum - either
+// fulfilled in an orphaned block, or still being processed for a request made in an orphaned
+// block.
+func (d *DB) RollbackReorgedRequests(ancestorBlockNum uint64, statusReason string) (int64, error) {
+	updates := map[string]interface{}{"status": database.REQUEST_STATUS_REORGED, "status_reason": statusReason}
+
+	fulfilled := d.scoped().Model(&database.RandomnessRequest{}).
+		Where("status = ? AND fulfill_block_number > ?", database.REQUEST_STATUS_SUCCESS, ancestorBlockNum).
+		Updates(updates)
+	if fulfilled.Error != nil {
+		return 0, fulfilled.Error
+	}
+
+	pending := d.scoped().Model(&database.RandomnessRequest{}).
+		Where("status IN ? AND request_block_number > ?",
+			[]int{database.REQUEST_STATUS_INITIALISED, database.REQUEST_STATUS_SENT, database.REQUEST_STATUS_TX_FAILED},
+			ancestorBlockNum).
+		Updates(updates)
+	if pending.Error != nil {
+		return fulfilled.RowsAffected, pending.Error
+	}
+
+	return fulfilled.RowsAffected + pending.RowsAffected, nil
+}
+
+// MarkRequestReorged flags a sent or fulfilled request whose event was removed by a chain reorganisation
+func (d *DB) MarkRequestReorged(requestId string, statusReason string) error {
+	return d.scoped().Model(&database.RandomnessRequest{}).
+		Where("request_id = ? AND (status = ? OR status = ?)", requestId, database.REQUEST_STATUS_SENT, database.REQUEST_STATUS_SUCCESS).
+		Updates(map[string]interface{}{"status": database.REQUEST_STATUS_REORGED, "status_reason": statusReason}).Error
+}
+
+func (d DB) GetDistinctConsumers(consumer string, keyHash string) ([]database.RandomnessRequest, error) {
 	var requests = []database.RandomnessRequest{}
-	var err error
 
+	where := map[string]interface{}{}
 	if len(consumer) > 0 {
-		err = d.Distinct("sender").Where("sender = ?", consumer).Order("sender desc").Find(&requests).Error
-	} else {
-		err = d.Distinct("sender").Order("sender desc").Find(&requests).Error
+		where["sender"] = consumer
+	}
+	if len(keyHash) > 0 {
+		where["key_hash"] = keyHash
 	}
+	err := d.scoped().Distinct("sender").Where(where).Order("sender desc").Find(&requests).Error
 	return requests, err
 }
@@ -2,12 +2,16 @@ package db_test
 
 import (
 	"context"
+	"fmt"
 	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
 	"oracle/config"
+	"oracle/models/database"
 	"oracle/store"
 	"oracle/store/keystorage"
 	"runtime/debug"
 	"testing"
+	"time"
 )
 
 var keystore *keystorage.Keystorage
@@ -34,9 +38,232 @@ func TestRandomnessRequestStore_Insert(t *testing.T) {
 		"RequestId",
 		1,
 		"txHash",
+		"blockHash", 1,
 		1, 1, 1)
 	debug.PrintStack()
 	if err != nil {
 		t.Error(err)
 	}
 }
+
+func TestRandomnessRequestStore_GasBumps(t *testing.T) {
+	var err error
+	keystore, err = keystorage.NewKeyStorage(Log, "../../test_data/generic_keystore.json")
+	if err != nil || keystore == nil {
+		t.Error(err)
+	}
+	thestore, err := store.NewStore(context.Background(), keystore)
+	if err != nil || thestore == nil {
+		t.Fatal(err)
+	}
+	err = thestore.Db.Migrate()
+	if err != nil {
+		t.Error(err)
+	}
+
+	requestId := fmt.Sprintf("GasBumpRequestId%d", time.Now().UnixNano())
+	err = thestore.Db.InsertNewRequest("keyHashStore", "Sender", requestId, database.REQUEST_STATUS_INITIALISED, "txHash", "blockHash", 1, 1, 1, 1)
+	assert.NoError(t, err)
+
+	err = thestore.Db.UpdateFulfilmentSent(requestId, database.REQUEST_STATUS_SENT, "fulfillTxHash1", 10)
+	assert.NoError(t, err)
+	err = thestore.Db.UpdateFulfilmentReplaced(requestId, "fulfillTxHash2", 15)
+	assert.NoError(t, err)
+	err = thestore.Db.UpdateFulfilmentReplaced(requestId, "fulfillTxHash3", 20)
+	assert.NoError(t, err)
+
+	request, err := thestore.Db.FindByRequestId(requestId)
+	assert.NoError(t, err)
+	assert.Equal(t, "fulfillTxHash3", request.GetFulfillTxHash())
+	assert.Equal(t, uint64(20), request.GetLastFulfillSentBlockNumber())
+	assert.Equal(t, uint64(2), request.GetGasBumps())
+	// replacing a Tx isn't a new fulfillment attempt
+	assert.Equal(t, uint64(1), request.GetFulfillmentAttempts())
+
+	// a fresh fulfillment Tx starts with no bumps
+	err = thestore.Db.UpdateFulfilmentSent(requestId, database.REQUEST_STATUS_SENT, "fulfillTxHash4", 30)
+	assert.NoError(t, err)
+	request, err = thestore.Db.FindByRequestId(requestId)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), request.GetGasBumps())
+	assert.Equal(t, uint64(2), request.GetFulfillmentAttempts())
+}
+
+func TestRandomnessRequestStore_FilterByKeyHash(t *testing.T) {
+	var err error
+	keystore, err = keystorage.NewKeyStorage(Log, "../../test_data/generic_keystore.json")
+	if err != nil || keystore == nil {
+		t.Error(err)
+	}
+	thestore, err := store.NewStore(context.Background(), keystore)
+	if err != nil || thestore == nil {
+		t.Fatal(err)
+	}
+	err = thestore.Db.Migrate()
+	if err != nil {
+		t.Error(err)
+	}
+
+	suffix := time.Now().UnixNano()
+	keyHashA := fmt.Sprintf("keyHashA%d", suffix)
+	keyHashB := fmt.Sprintf("keyHashB%d", suffix)
+	for i, keyHash := range []string{keyHashA, keyHashA, keyHashB} {
+		requestId := fmt.Sprintf("FilterRequestId%d-%d", suffix, i)
+		err = thestore.Db.InsertNewRequest(keyHash, "Sender", requestId, database.REQUEST_STATUS_INITIALISED, "txHash", "blockHash", 1, 1, 1, 1)
+		assert.NoError(t, err)
+		err = thestore.Db.UpdateFulfillment(requestId, database.REQUEST_STATUS_SUCCESS, "1", "blockHash", 1, "fulfillTxHash", uint64(100*(i+1)), 1)
+		assert.NoError(t, err)
+	}
+
+	requests, count, err := thestore.Db.GetPaginatedRequests(1, 10, -1, "asc", keyHashA)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+	assert.Len(t, requests, 2)
+
+	requests, err = thestore.Db.GetLastXRequests(0, "", keyHashB)
+	assert.NoError(t, err)
+	assert.Len(t, requests, 1)
+	assert.Equal(t, keyHashB, requests[0].GetKeyHash())
+
+	mostGasUsed, err := thestore.Db.GetMostGasUsed(keyHashA)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(200), mostGasUsed.FulfillGasUsed)
+}
+
+func TestRandomnessRequestStore_ForChain(t *testing.T) {
+	var err error
+	keystore, err = keystorage.NewKeyStorage(Log, "../../test_data/generic_keystore.json")
+	if err != nil || keystore == nil {
+		t.Error(err)
+	}
+	thestore, err := store.NewStore(context.Background(), keystore)
+	if err != nil || thestore == nil {
+		t.Fatal(err)
+	}
+	err = thestore.Db.Migrate()
+	if err != nil {
+		t.Error(err)
+	}
+
+	suffix := time.Now().UnixNano()
+	keyHash := fmt.Sprintf("keyHashChain%d", suffix)
+	requestId := fmt.Sprintf("ChainRequestId%d", suffix)
+	chainA := thestore.Db.ForChain(suffix)
+	chainB := thestore.Db.ForChain(suffix + 1)
+
+	// the same request ID can be stored once per chain
+	err = chainA.InsertNewRequest(keyHash, "Sender", requestId, database.REQUEST_STATUS_INITIALISED, "txHashA", "blockHash", 1, 1, 1, 1)
+	assert.NoError(t, err)
+	err = chainB.InsertNewRequest(keyHash, "Sender", requestId, database.REQUEST_STATUS_INITIALISED, "txHashB", "blockHash", 1, 1, 1, 1)
+	assert.NoError(t, err)
+	err = chainA.InsertNewRequest(keyHash, "Sender", requestId, database.REQUEST_STATUS_INITIALISED, "txHashA", "blockHash", 1, 1, 1, 1)
+	assert.Error(t, err)
+
+	err = chainB.UpdateRequestStatus(requestId, database.REQUEST_STATUS_SENT, "")
+	assert.NoError(t, err)
+
+	request, err := chainA.FindByRequestId(requestId)
+	assert.NoError(t, err)
+	assert.Equal(t, suffix, request.GetChainId())
+	assert.Equal(t, "txHashA", request.GetRequestTxHash())
+	assert.Equal(t, database.REQUEST_STATUS_INITIALISED, request.GetStatus())
+
+	request, err = chainB.FindByRequestId(requestId)
+	assert.NoError(t, err)
+	assert.Equal(t, suffix+1, request.GetChainId())
+	assert.Equal(t, database.REQUEST_STATUS_SENT, request.GetStatus())
+
+	requests, count, err := chainA.GetPaginatedRequests(1, 10, -1, "asc", keyHash)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.Len(t, requests, 1)
+
+	requests, count, err = thestore.Db.GetPaginatedRequests(1, 10, -1, "asc", keyHash)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+	assert.Len(t, requests, 2)
+}
+
+func TestRandomnessRequestStore_RollbackReorgedRequests(t *testing.T) {
+	var err error
+	keystore, err = keystorage.NewKeyStorage(Log, "../../test_data/generic_keystore.json")
+	if err != nil || keystore == nil {
+		t.Error(err)
+	}
+	thestore, err := store.NewStore(context.Background(), keystore)
+	if err != nil || thestore == nil {
+		t.Fatal(err)
+	}
+	err = thestore.Db.Migrate()
+	if err != nil {
+		t.Error(err)
+	}
+
+	suffix := time.Now().UnixNano()
+	chain := thestore.Db.ForChain(suffix)
+
+	// requests made after the ancestor are rolled back whatever stage they're at
+	statuses := map[string]int{
+		"Initialised": database.REQUEST_STATUS_INITIALISED,
+		"Sent":        database.REQUEST_STATUS_SENT,
+		"TxFailed":    database.REQUEST_STATUS_TX_FAILED,
+		"Invalid":     database.REQUEST_STATUS_PROOF_INVALID,
+	}
+	for name, status := range statuses {
+		err = chain.InsertNewRequest("keyHashReorg", "Sender", name, status, "txHash"+name, "blockHash", 11, 1, 1, 1)
+		assert.NoError(t, err)
+	}
+	err = chain.InsertNewRequest("keyHashReorg", "Sender", "Before", database.REQUEST_STATUS_INITIALISED, "txHashBefore", "blockHash", 10, 1, 1, 1)
+	assert.NoError(t, err)
+
+	count, err := chain.RollbackReorgedRequests(10, "reorg")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	for name, status := range statuses {
+		request, err := chain.FindByRequestId(name)
+		assert.NoError(t, err)
+		if status == database.REQUEST_STATUS_PROOF_INVALID {
+			assert.Equal(t, status, request.GetStatus(), name)
+		} else {
+			assert.Equal(t, database.REQUEST_STATUS_REORGED, request.GetStatus(), name)
+		}
+	}
+	request, err := chain.FindByRequestId("Before")
+	assert.NoError(t, err)
+	assert.Equal(t, database.REQUEST_STATUS_INITIALISED, request.GetStatus())
+}
+
+func TestRandomnessRequestStore_CountRequestsByChainAndStatus(t *testing.T) {
+	var err error
+	keystore, err = keystorage.NewKeyStorage(Log, "../../test_data/generic_keystore.json")
+	if err != nil || keystore == nil {
+		t.Error(err)
+	}
+	thestore, err := store.NewStore(context.Background(), keystore)
+	if err != nil || thestore == nil {
+		t.Fatal(err)
+	}
+	err = thestore.Db.Migrate()
+	if err != nil {
+		t.Error(err)
+	}
+
+	suffix := time.Now().UnixNano()
+	chainA := thestore.Db.ForChain(suffix)
+	chainB := thestore.Db.ForChain(suffix + 1)
+	assert.NoError(t, chainA.InsertNewRequest("keyHashCount", "Sender", "CountA1", database.REQUEST_STATUS_SUCCESS, "txHash", "blockHash", 1, 1, 1, 1))
+	assert.NoError(t, chainA.InsertNewRequest("keyHashCount", "Sender", "CountA2", database.REQUEST_STATUS_SUCCESS, "txHash", "blockHash", 1, 1, 1, 1))
+	assert.NoError(t, chainA.InsertNewRequest("keyHashCount", "Sender", "CountA3", database.REQUEST_STATUS_SENT, "txHash", "blockHash", 1, 1, 1, 1))
+	assert.NoError(t, chainB.InsertNewRequest("keyHashCount", "Sender", "CountB1", database.REQUEST_STATUS_SUCCESS, "txHash", "blockHash", 1, 1, 1, 1))
+
+	counts, err := thestore.Db.CountRequestsByChainAndStatus()
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]int64{database.REQUEST_STATUS_SUCCESS: 2, database.REQUEST_STATUS_SENT: 1}, counts[suffix])
+	assert.Equal(t, map[int]int64{database.REQUEST_STATUS_SUCCESS: 1}, counts[suffix+1])
+
+	// a chain's own store only counts its requests
+	counts, err = chainB.CountRequestsByChainAndStatus()
+	assert.NoError(t, err)
+	assert.Len(t, counts, 1)
+}
@@ -0,0 +1,229 @@
+package keystorage
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	cryptoRand "crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/scrypt"
+	"io"
+	"oracle/config"
+	"oracle/models/keystorage"
+	"oracle/utils"
+)
+
+const (
+	// VersionLegacy keystores derive the AES key with a sha256 of the API token, and encrypt
+	// with unauthenticated AES-CFB
+	VersionLegacy = 0
+	// VersionScryptGCM keystores derive the AES key with scrypt and a per-file salt, and
+	// encrypt with AES-GCM
+	VersionScryptGCM = 1
+	// CurrentVersion is the format new and migrated keystores are written in
+	CurrentVersion = VersionScryptGCM
+)
+
+const (
+	kdfScrypt    = "scrypt"
+	cipherAESGCM = "aes-256-gcm"
+	scryptR      = 8
+	scryptDKLen  = 32
+	saltLength   = 32
+)
+
+// ErrLocked is returned when a keystore's keys are used before it's unlocked with its token
+var ErrLocked = errors.New("keystore is locked")
+
+// ErrHasKeys is returned when a new token is generated for a keystore which already has keys
+var ErrHasKeys = errors.New("keystore already has keys. Rotate its token instead")
+
+// newCrypto returns the encryption parameters for a keystore, with a new random salt
+func newCrypto() (*keystorage.KeyStorageCryptoModel, error) {
+	salt := make([]byte, saltLength)
+	if _, err := io.ReadFull(cryptoRand.Reader, salt); err != nil {
+		return nil, err
+	}
+	params := utils.GetScryptParams(config.Conf)
+	return &keystorage.KeyStorageCryptoModel{
+		Cipher: cipherAESGCM,
+		KDF:    kdfScrypt,
+		N:      params.N,
+		R:      scryptR,
+		P:      params.P,
+		DKLen:  scryptDKLen,
+		Salt:   hex.EncodeToString(salt),
+	}, nil
+}
+
+// deriveKey derives the AES key from the token, with the keystore's KDF parameters
+func deriveKey(params *keystorage.KeyStorageCryptoModel, token string) ([]byte, error) {
+	if params == nil {
+		return nil, errors.New("keystore has no encryption parameters")
+	}
+	if params.KDF != kdfScrypt || params.Cipher != cipherAESGCM {
+		return nil, fmt.Errorf("unsupported keystore encryption %s/%s", params.KDF, params.Cipher)
+	}
+	salt, err := hex.DecodeString(params.Salt)
+	if err != nil {
+		return nil, err
+	}
+	return scrypt.Key([]byte(token), salt, params.N, params.R, params.P, params.DKLen)
+}
+
+// encryptGCM encrypts plainText with AES-GCM. The random nonce is prepended to the output.
+func encryptGCM(key []byte, plainText string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(cryptoRand.Reader, nonce); err != nil {
+		return "", err
+	}
+	encrypted := gcm.Seal(nonce, nonce, []byte(plainText), nil)
+	return base64.URLEncoding.EncodeToString(encrypted), nil
+}
+
+// decryptGCM decrypts the output of encryptGCM. It fails if the cipher text has been
+// modified, or the key is wrong.
+func decryptGCM(key []byte, cipherText string) (string, error) {
+	encrypted, err := base64.URLEncoding.DecodeString(cipherText)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	if len(encrypted) < gcm.NonceSize() {
+		return "", fmt.Errorf("cipherText too short. It decodes to %v bytes but the minimum length is %v", len(encrypted), gcm.NonceSize())
+	}
+	nonce, data := encrypted[:gcm.NonceSize()], encrypted[gcm.NonceSize():]
+	decrypted, err := gcm.Open(nil, nonce, data, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(decrypted), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// unlock derives the AES key from the token. A keystore in an older format is re-encrypted
+// in the current one, and saved.
+func (d *Keystorage) unlock(token string) error {
+	if d.KeyStore.Version == VersionLegacy {
+		return d.migrate(token)
+	}
+	if d.cipherKey != nil && d.KeyStore.Token == token {
+		return nil
+	}
+	cipherKey, err := deriveKey(d.KeyStore.Crypto, token)
+	if err != nil {
+		return err
+	}
+	d.KeyStore.Token = token
+	d.cipherKey = cipherKey
+	return nil
+}
+
+// migrate re-encrypts a legacy keystore's keys with the current format. The keystore in
+// memory is only replaced once the migrated one has been saved, so a failure leaves it legacy.
+func (d *Keystorage) migrate(token string) error {
+	params, err := newCrypto()
+	if err != nil {
+		return err
+	}
+	cipherKey, err := deriveKey(params, token)
+	if err != nil {
+		return err
+	}
+
+	keys := d.KeyStore.GetKey()
+	migrated := *d.KeyStore
+	migrated.Version = CurrentVersion
+	migrated.Crypto = params
+	migrated.Token = token
+	migrated.Key = make([]*keystorage.KeyStorageKeyModel, len(keys))
+	for i, key := range keys {
+		private, err := Decrypt(key.CipherPrivate, token)
+		if err != nil {
+			return fmt.Errorf("decrypt %s: %w", key.Account, err)
+		}
+		migratedKey := *key
+		migratedKey.CipherPrivate, err = encryptGCM(cipherKey, private)
+		if err != nil {
+			return err
+		}
+		migrated.Key[i] = &migratedKey
+	}
+
+	data, err := json.Marshal(&migrated)
+	if err != nil {
+		return err
+	}
+	err = d.write(data)
+	if err != nil {
+		return err
+	}
+	*d.KeyStore = migrated
+	d.cipherKey = cipherKey
+
+	d.log.WithFields(logrus.Fields{
+		"package":  "keystorage",
+		"function": "migrate",
+		"action":   "migrate keystore",
+		"version":  CurrentVersion,
+		"keys":     len(keys),
+	}).Info()
+	return nil
+}
+
+// resetCrypto starts encrypting with new parameters derived from the keystore's token. Only
+// call it on a keystore with no keys, since existing keys aren't re-encrypted.
+func (d *Keystorage) resetCrypto() error {
+	params, err := newCrypto()
+	if err != nil {
+		return err
+	}
+	cipherKey, err := deriveKey(params, d.KeyStore.Token)
+	if err != nil {
+		return err
+	}
+	d.KeyStore.Version = CurrentVersion
+	d.KeyStore.Crypto = params
+	d.cipherKey = cipherKey
+	return nil
+}
+
+// encrypt encrypts a private key in the keystore's format
+func (d *Keystorage) encrypt(plainText string) (string, error) {
+	if d.KeyStore.Version == VersionLegacy {
+		return Encrypt(plainText, d.KeyStore.Token)
+	}
+	if d.cipherKey == nil {
+		return "", ErrLocked
+	}
+	return encryptGCM(d.cipherKey, plainText)
+}
+
+// decrypt decrypts a private key in the keystore's format
+func (d *Keystorage) decrypt(cipherText string) (string, error) {
+	if d.KeyStore.Version == VersionLegacy {
+		return Decrypt(cipherText, d.KeyStore.Token)
+	}
+	if d.cipherKey == nil {
+		return "", ErrLocked
+	}
+	return decryptGCM(d.cipherKey, cipherText)
+}
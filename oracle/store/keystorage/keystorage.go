@@ -5,6 +5,7 @@ import (
 	"crypto/cipher"
 	cryptoRand "crypto/rand"
 	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -12,20 +13,22 @@ import (
 	"golang.org/x/crypto/bcrypt"
 	"io"
 	"io/ioutil"
-	"math/rand"
 	"oracle/models/keystorage"
 	"oracle/utils"
 	"oracle/utils/walletworker"
 	"os"
+	"path/filepath"
 	"sync"
-	"time"
 )
 
 type Keystorage struct {
 	log      *logrus.Logger
 	File     *os.File
 	KeyStore *keystorage.KeyStorageModel
-	mu       sync.Mutex
+	// held while the keys or token are changed and saved
+	mu sync.Mutex
+	// AES key derived from the token, once the keystore is unlocked
+	cipherKey []byte
 }
 
 func NewKeyStorage(log *logrus.Logger, filePath string) (*Keystorage, error) {
@@ -92,7 +95,7 @@ func NewKeyStorage(log *logrus.Logger, filePath string) (*Keystorage, error) {
 func (d *Keystorage) GetFirst() *keystorage.KeyStorageKeyModel {
 	key := d.KeyStore.GetKey()
 	if key[0].Private == "" {
-		key[0].Private, _ = Decrypt(key[0].CipherPrivate, d.KeyStore.Token)
+		key[0].Private, _ = d.decrypt(key[0].CipherPrivate)
 	}
 	d.KeyStore.Key = key
 	return key[0]
@@ -103,7 +106,7 @@ func (d *Keystorage) GetByUsername(account string) *keystorage.KeyStorageKeyMode
 	for _, key := range keys {
 		if key.Account == account {
 			if key.Private == "" {
-				key.Private, _ = Decrypt(key.CipherPrivate, d.KeyStore.Token)
+				key.Private, _ = d.decrypt(key.CipherPrivate)
 			}
 			d.KeyStore.Key = keys
 			return key
@@ -128,7 +131,9 @@ func (d *Keystorage) GeneratePrivate(username string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	cipherPrivate, err := Encrypt(keyGeneratedString, d.KeyStore.Token)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	cipherPrivate, err := d.encrypt(keyGeneratedString)
 	if err != nil {
 		return "", err
 	}
@@ -152,7 +157,9 @@ func (d *Keystorage) AddExisting(username string, privateKey string) (err error)
 	//privkeyRaw, err := hex.DecodeString(string(privkeyHex[:len(privkeyHex)-1]))
 	//privateKeyECDSA, err := crypto.ToECDSA(privkey)
 
-	cipherPrivate, err := Encrypt(privkeyHex, d.KeyStore.Token)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	cipherPrivate, err := d.encrypt(privkeyHex)
 	if err != nil {
 		return err
 	}
@@ -171,11 +178,48 @@ func (d *Keystorage) AddExisting(username string, privateKey string) (err error)
 	return
 }
 
+// AddSending adds a sending account, which pays for gas and sends Txs but isn't served as a
+// proving key. A new key is generated if privateKey is empty. Returns the private key.
+func (d *Keystorage) AddSending(username string, privateKey string) (string, error) {
+	if privateKey == "" {
+		_, keyGeneratedString, err := walletworker.GeneratePrivate()
+		if err != nil {
+			return "", err
+		}
+		privateKey = keyGeneratedString
+	}
+	privkeyHex := utils.AddHexPrefix(privateKey)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	cipherPrivate, err := d.encrypt(privkeyHex)
+	if err != nil {
+		return "", err
+	}
+	d.KeyStore.Key = append(d.KeyStore.Key, &keystorage.KeyStorageKeyModel{
+		Account:       username,
+		CipherPrivate: cipherPrivate,
+		Private:       privkeyHex,
+		Sending:       true,
+	})
+	return privkeyHex, d.save()
+}
+
+// GetSending returns the keystore's sending accounts, with their private keys decrypted
+func (d *Keystorage) GetSending() []*keystorage.KeyStorageKeyModel {
+	var sending []*keystorage.KeyStorageKeyModel
+	for _, key := range d.GetAll() {
+		if key.GetSending() {
+			sending = append(sending, key)
+		}
+	}
+	return sending
+}
+
 func (d Keystorage) GetByAccount(account string) (*keystorage.KeyStorageKeyModel, error) {
 	var keys = d.KeyStore.GetKey()
 	for _, key := range keys {
 		if key.Account == account {
-			key.Private, _ = Decrypt(key.CipherPrivate, d.KeyStore.Token)
+			key.Private, _ = d.decrypt(key.CipherPrivate)
 			return key, nil
 		}
 	}
@@ -183,10 +227,12 @@ func (d Keystorage) GetByAccount(account string) (*keystorage.KeyStorageKeyModel
 }
 
 func (d *Keystorage) SetRegistered(privateKey string) (err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
 	keys := d.KeyStore.GetKey()
 
 	for index, key := range keys {
-		if decryptedPrivate, _ := Decrypt(key.CipherPrivate, d.KeyStore.Token); decryptedPrivate == privateKey {
+		if decryptedPrivate, _ := d.decrypt(key.CipherPrivate); decryptedPrivate == privateKey {
 			d.KeyStore.Key[index].Registered = true
 			err = d.save()
 			return
@@ -195,12 +241,18 @@ func (d *Keystorage) SetRegistered(privateKey string) (err error) {
 	return
 }
 
-func (d *Keystorage) SetBlockNumber(blockNumber int64) (err error) {
+// SetRegisteredOnChain records that the key has been registered with the chain's VORCoordinator
+func (d *Keystorage) SetRegisteredOnChain(privateKey string, chainId int64) (err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
 	keys := d.KeyStore.GetKey()
 
 	for index, key := range keys {
-		if decryptedPrivate, _ := Decrypt(key.CipherPrivate, d.KeyStore.Token); decryptedPrivate == d.KeyStore.PrivateKey {
-			d.KeyStore.Key[index].BlockNumber = blockNumber
+		if decryptedPrivate, _ := d.decrypt(key.CipherPrivate); decryptedPrivate == privateKey {
+			if registeredOnChain(key, chainId) {
+				return
+			}
+			d.KeyStore.Key[index].RegisteredChains = append(d.KeyStore.Key[index].RegisteredChains, chainId)
 			err = d.save()
 			return
 		}
@@ -208,22 +260,33 @@ func (d *Keystorage) SetBlockNumber(blockNumber int64) (err error) {
 	return
 }
 
-func (d *Keystorage) GetBlockNumber() (blockNumber int64, err error) {
+// IsRegisteredOnChain returns true if the key has been registered with the chain's VORCoordinator
+func (d *Keystorage) IsRegisteredOnChain(privateKey string, chainId int64) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
 	keys := d.KeyStore.GetKey()
+	for _, key := range keys {
+		if decryptedPrivate, _ := d.decrypt(key.CipherPrivate); decryptedPrivate == privateKey {
+			return registeredOnChain(key, chainId)
+		}
+	}
 
-	for index, key := range keys {
-		if decryptedPrivate, _ := Decrypt(key.CipherPrivate, d.KeyStore.Token); decryptedPrivate == d.KeyStore.PrivateKey {
-			blockNumber = d.KeyStore.Key[index].BlockNumber
-			return
+	return false
+}
+
+func registeredOnChain(key *keystorage.KeyStorageKeyModel, chainId int64) bool {
+	for _, registeredChainId := range key.GetRegisteredChains() {
+		if registeredChainId == chainId {
+			return true
 		}
 	}
-	return
+	return false
 }
 
 func (d *Keystorage) IsRegisteredByPrivate(privateKey string) (registered bool) {
 	keys := d.KeyStore.GetKey()
 	for _, key := range keys {
-		if decryptedPrivate, _ := Decrypt(key.CipherPrivate, d.KeyStore.Token); decryptedPrivate == privateKey {
+		if decryptedPrivate, _ := d.decrypt(key.CipherPrivate); decryptedPrivate == privateKey {
 			return key.GetRegistered()
 		}
 	}
@@ -239,30 +302,89 @@ func (d Keystorage) Exists() bool {
 	return false
 }
 
+// save writes the keystore to its file. The caller must hold d.mu, for the whole change
+// being saved, so a concurrent change such as a token rotation can't overwrite it.
 func (d *Keystorage) save() error {
-	d.mu.Lock()
-	defer d.mu.Unlock()
 	jsonByte, err := json.Marshal(d.KeyStore)
 	if err != nil {
 		return err
 	}
-	_, err = d.File.WriteAt(jsonByte, 0)
-	return err
+	return d.write(jsonByte)
+}
+
+// write replaces the keystore file with data. It's written to a temporary file in the same
+// directory, synced and renamed over the keystore, so a crash leaves either the old file or
+// the new one, never a mix of the two.
+func (d *Keystorage) write(data []byte) error {
+	path := d.File.Name()
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	// a no-op once renamed
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR, 0600)
+	if err != nil {
+		return err
+	}
+	d.File.Close()
+	d.File = file
+	return nil
 }
 
+// GenerateToken sets a new API token on a keystore with no keys. The keys of a keystore which
+// has them are encrypted under its current token, so use RotateToken instead.
 func (d *Keystorage) GenerateToken() (string, error) {
-	rand.Seed(time.Now().Unix())
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.KeyStore.GetKey()) > 0 {
+		return "", ErrHasKeys
+	}
 	d.KeyStore.Token = GenerateRandomBytes(32)
-	err := d.tokenEncryptAndSave()
+	err := d.resetCrypto()
+	if err != nil {
+		return "", err
+	}
+	err = d.tokenEncryptAndSave()
 	return d.KeyStore.Token, err
 }
 
+// CheckToken unlocks the keystore if token is its API token. A keystore in an older format
+// is migrated to the current one.
 func (d *Keystorage) CheckToken(token string) (err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
 	err = bcrypt.CompareHashAndPassword([]byte(d.KeyStore.Hash), []byte(token))
-	if err == nil {
-		d.KeyStore.Token = token
+	if err != nil {
+		return
 	}
-	return
+	return d.unlock(token)
+}
+
+// IsToken returns true if key is the unlocked keystore's API token
+func (d *Keystorage) IsToken(key string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.cipherKey == nil || d.KeyStore.Token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(key), []byte(d.KeyStore.Token)) == 1
 }
 
 func (d *Keystorage) SelectPrivateKey(account string) (err error) {
@@ -274,20 +396,52 @@ func (d *Keystorage) SelectPrivateKey(account string) (err error) {
 	return err
 }
 
+// GetAll returns every key in the keystore, with its private key decrypted
+func (d *Keystorage) GetAll() []*keystorage.KeyStorageKeyModel {
+	keys := d.KeyStore.GetKey()
+	for _, key := range keys {
+		if key.Private == "" {
+			key.Private, _ = d.decrypt(key.CipherPrivate)
+		}
+	}
+	return keys
+}
+
 func (d *Keystorage) GetSelectedPrivateKey() string {
 	return d.KeyStore.GetPrivateKey()
 }
 
+// GetLegacyBlockNumber returns the last checked block number that older versions saved
+// against the selected key, or 0 if there isn't one
+func (d *Keystorage) GetLegacyBlockNumber() int64 {
+	for _, key := range d.GetAll() {
+		if key.GetPrivate() == d.KeyStore.GetPrivateKey() {
+			return key.GetBlockNumber()
+		}
+	}
+	return 0
+}
+
 func (d *Keystorage) tokenEncryptAndSave() (err error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(d.KeyStore.Token), 8)
+	hash, err := hashToken(d.KeyStore.Token)
 	if err != nil {
 		return
 	}
-	d.KeyStore.Hash = string(hash)
+	d.KeyStore.Hash = hash
 	err = d.save()
 	return
 }
 
+// hashToken returns the bcrypt hash the token is checked against
+func hashToken(token string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(token), 8)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Decrypt decrypts a key in the legacy keystore format.
 // Takes two strings, cryptoText and keyString.
 // cryptoText is the text to be decrypted and the keyString is the key to use for the decryption.
 // The function will output the resulting plain text string with an error variable.
@@ -324,6 +478,7 @@ func decryptAES(key, data []byte) ([]byte, error) {
 	return data, nil
 }
 
+// Encrypt encrypts a key in the legacy keystore format.
 // Takes two string, plainText and keyString.
 // plainText is the text that needs to be encrypted by keyString.
 // The function will output the resulting crypto text and an error variable.
@@ -379,7 +534,7 @@ func GenerateRandomBytes(strSize int) string {
 	var dictionary = "0123456789abcdefghijklmnopqrstuvwxyz"
 
 	var bytes = make([]byte, strSize)
-	rand.Read(bytes)
+	cryptoRand.Read(bytes)
 	for k, v := range bytes {
 		bytes[k] = dictionary[v%byte(len(dictionary))]
 	}
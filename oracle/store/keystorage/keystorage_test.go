@@ -1,19 +1,43 @@
 package keystorage_test
 
 import (
+	"encoding/json"
+	"fmt"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"oracle/config"
 	"oracle/store/keystorage"
+	"oracle/utils"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 )
 
 var Log = logrus.New()
 
+func init() {
+	config.Conf.Keystorage.InsecureFastScrypt = true
+}
+
+// copyFixture copies a test_data keystore to a temporary dir, so tests can't modify it
+func copyFixture(t *testing.T, name string) string {
+	data, err := ioutil.ReadFile(filepath.Join("..", "..", "test_data", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keystoragePath := filepath.Join(t.TempDir(), name)
+	err = ioutil.WriteFile(keystoragePath, data, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keystoragePath
+}
+
 func TestKeystorage_NewKeyStorage(t *testing.T) {
-	dir, _ := os.Getwd()
-	keystoragePath := filepath.Join(dir, "..", "..", "test_data", "keystore_test_keystore.json")
+	keystoragePath := copyFixture(t, "keystore_test_keystore.json")
 
 	keystore, err := keystorage.NewKeyStorage(Log, keystoragePath)
 	if err != nil {
@@ -23,8 +47,7 @@ func TestKeystorage_NewKeyStorage(t *testing.T) {
 }
 
 func TestKeystorage_Exists(t *testing.T) {
-	dir, _ := os.Getwd()
-	keystoragePath := filepath.Join(dir, "..", "..", "test_data", "keystore_test_keystore.json")
+	keystoragePath := copyFixture(t, "keystore_test_keystore.json")
 
 	keystore, err := keystorage.NewKeyStorage(Log, keystoragePath)
 	if err != nil {
@@ -34,8 +57,7 @@ func TestKeystorage_Exists(t *testing.T) {
 }
 
 func TestKeystorage_AddGenerated(t *testing.T) {
-	dir, _ := os.Getwd()
-	keystoragePath := filepath.Join(dir, "..", "..", "test_data", "keystore_test_keystore.json")
+	keystoragePath := copyFixture(t, "keystore_test_keystore.json")
 
 	keystore, err := keystorage.NewKeyStorage(Log, keystoragePath)
 	if err != nil {
@@ -50,8 +72,7 @@ func TestKeystorage_AddGenerated(t *testing.T) {
 
 // rod0gbc3mhyxdiah2vwialx1q3osk5cw
 func TestKeystorage_Add(t *testing.T) {
-	dir, _ := os.Getwd()
-	keystoragePath := filepath.Join(dir, "..", "..", "test_data", "keystore_test_keystore.json")
+	keystoragePath := copyFixture(t, "keystore_test_keystore.json")
 
 	keystore, err := keystorage.NewKeyStorage(Log, keystoragePath)
 	if err != nil {
@@ -68,8 +89,7 @@ func TestKeystorage_Add(t *testing.T) {
 }
 
 func TestKeystorage_GenerateAndCheckToken(t *testing.T) {
-	dir, _ := os.Getwd()
-	keystoragePath := filepath.Join(dir, "..", "..", "test_data", "keystore_test_new_token_keystore.json")
+	keystoragePath := filepath.Join(t.TempDir(), "keystore.json")
 
 	assert := assert.New(t)
 	keystore, err := keystorage.NewKeyStorage(Log, keystoragePath)
@@ -84,6 +104,13 @@ func TestKeystorage_GenerateAndCheckToken(t *testing.T) {
 		t.Error(err)
 	}
 	assert.Equal(token, keystore.KeyStore.Token)
+
+	// the keys are encrypted under the token, so it can only be rotated from now on
+	_, err = keystore.GeneratePrivate("oracle")
+	assert.NoError(err)
+	_, err = keystore.GenerateToken()
+	assert.ErrorIs(err, keystorage.ErrHasKeys)
+	assert.Equal(token, keystore.KeyStore.Token)
 }
 
 func TestKeystorage_EncryptDecrypt(t *testing.T) {
@@ -124,8 +151,7 @@ func TestKeystorage_Decrypt(t *testing.T) {
 }
 
 func TestKeystorage_GetByAccount(t *testing.T) {
-	dir, _ := os.Getwd()
-	keystoragePath := filepath.Join(dir, "..", "..", "test_data", "keystore_test_keystore.json")
+	keystoragePath := copyFixture(t, "keystore_test_keystore.json")
 
 	assert := assert.New(t)
 	privateKeyExpected := "0x6cbed15c793ce57650b9877cf6fa156fbef513c4e6134f022a85b1ffdd59b2a1"
@@ -144,8 +170,7 @@ func TestKeystorage_GetByAccount(t *testing.T) {
 }
 
 func TestKeystorage_SetRegistered(t *testing.T) {
-	dir, _ := os.Getwd()
-	keystoragePath := filepath.Join(dir, "..", "..", "test_data", "keystore_test_keystore.json")
+	keystoragePath := copyFixture(t, "keystore_test_keystore.json")
 
 	assert := assert.New(t)
 
@@ -181,3 +206,333 @@ func TestKeystorage_SetRegistered(t *testing.T) {
 
 	assert.Equal(true, keyModel.Registered)
 }
+
+func TestKeystorage_SetRegisteredOnChain(t *testing.T) {
+	keystoragePath := copyFixture(t, "keystore_test_keystore.json")
+
+	assert := assert.New(t)
+
+	keystore, err := keystorage.NewKeyStorage(Log, keystoragePath)
+	if err != nil {
+		t.Error(err)
+	}
+	err = keystore.CheckToken("dwkxnzn3kl1dlndvtdtvqko9gpaay5vj")
+	if err != nil {
+		t.Error(err)
+	}
+	keyModel := keystore.GetByUsername("test")
+
+	assert.False(keystore.IsRegisteredOnChain(keyModel.Private, 1))
+	assert.NoError(keystore.SetRegisteredOnChain(keyModel.Private, 1))
+	assert.NoError(keystore.SetRegisteredOnChain(keyModel.Private, 1))
+	assert.True(keystore.IsRegisteredOnChain(keyModel.Private, 1))
+	assert.False(keystore.IsRegisteredOnChain(keyModel.Private, 2))
+
+	// saved to the keystore file
+	keystore2, err := keystorage.NewKeyStorage(Log, keystoragePath)
+	if err != nil {
+		t.Error(err)
+	}
+	err = keystore2.CheckToken("dwkxnzn3kl1dlndvtdtvqko9gpaay5vj")
+	if err != nil {
+		t.Error(err)
+	}
+	assert.True(keystore2.IsRegisteredOnChain(keyModel.Private, 1))
+	assert.Equal([]int64{1}, keystore2.GetByUsername("test").RegisteredChains)
+}
+
+func TestKeystorage_AddSending(t *testing.T) {
+	keystoragePath := filepath.Join(t.TempDir(), "keystore.json")
+
+	keystore, err := keystorage.NewKeyStorage(Log, keystoragePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = keystore.GenerateToken()
+	assert.NoError(t, err)
+	_, err = keystore.GeneratePrivate("oracle")
+	assert.NoError(t, err)
+
+	generated, err := keystore.AddSending("gas1", "")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, generated)
+	_, err = keystore.AddSending("gas2", "ba37bd76fa2efb78d29cc55c026786c368e34cd97e64aebe4184f4e822079c74")
+	assert.NoError(t, err)
+
+	// reopen, to check the flag is saved
+	token := keystore.KeyStore.Token
+	keystore, err = keystorage.NewKeyStorage(Log, keystoragePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, keystore.CheckToken(token))
+
+	sending := keystore.GetSending()
+	assert.Len(t, sending, 2)
+	assert.Equal(t, "gas1", sending[0].GetAccount())
+	assert.Equal(t, generated, sending[0].GetPrivate())
+	assert.Equal(t, "0xba37bd76fa2efb78d29cc55c026786c368e34cd97e64aebe4184f4e822079c74", sending[1].GetPrivate())
+	assert.False(t, keystore.GetByUsername("oracle").GetSending())
+}
+
+func TestKeystorage_MigrateLegacy(t *testing.T) {
+	keystoragePath := copyFixture(t, "keystore_test_keystore.json")
+
+	keystore, err := keystorage.NewKeyStorage(Log, keystoragePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, keystorage.VersionLegacy, keystore.KeyStore.GetVersion())
+	assert.Error(t, keystore.CheckToken("wrongtoken"))
+	assert.Equal(t, keystorage.VersionLegacy, keystore.KeyStore.GetVersion())
+
+	// unlocking migrates the file
+	assert.NoError(t, keystore.CheckToken("dwkxnzn3kl1dlndvtdtvqko9gpaay5vj"))
+	assert.Equal(t, keystorage.CurrentVersion, keystore.KeyStore.GetVersion())
+
+	data, err := ioutil.ReadFile(keystoragePath)
+	assert.NoError(t, err)
+	saved := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(data, &saved))
+	assert.Equal(t, float64(keystorage.CurrentVersion), saved["version"])
+	crypto := saved["crypto"].(map[string]interface{})
+	assert.Equal(t, "scrypt", crypto["kdf"])
+	assert.Equal(t, "aes-256-gcm", crypto["cipher"])
+	assert.Equal(t, float64(utils.FastScryptParams.N), crypto["n"])
+	assert.Len(t, crypto["salt"], 64)
+
+	// the legacy scheme can't read the keys any more, but the new one can
+	keystore, err = keystorage.NewKeyStorage(Log, keystoragePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cipherPrivate := keystore.KeyStore.GetKey()[0].GetCipherPrivate()
+	legacyPrivate, _ := keystorage.Decrypt(cipherPrivate, "dwkxnzn3kl1dlndvtdtvqko9gpaay5vj")
+	assert.NotEqual(t, "0x6cbed15c793ce57650b9877cf6fa156fbef513c4e6134f022a85b1ffdd59b2a1", legacyPrivate)
+	assert.NoError(t, keystore.CheckToken("dwkxnzn3kl1dlndvtdtvqko9gpaay5vj"))
+	assert.Equal(t, "0x6cbed15c793ce57650b9877cf6fa156fbef513c4e6134f022a85b1ffdd59b2a1", keystore.GetByUsername("test").GetPrivate())
+}
+
+func TestKeystorage_MigrateLegacySaveFails(t *testing.T) {
+	keystoragePath := copyFixture(t, "keystore_test_keystore.json")
+	keystore, err := keystorage.NewKeyStorage(Log, keystoragePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cipherPrivate := keystore.KeyStore.GetKey()[0].GetCipherPrivate()
+
+	// the migrated file can't be written
+	assert.NoError(t, os.RemoveAll(filepath.Dir(keystoragePath)))
+	assert.Error(t, keystore.CheckToken("dwkxnzn3kl1dlndvtdtvqko9gpaay5vj"))
+
+	// so the keystore in memory is still the legacy one
+	assert.Equal(t, keystorage.VersionLegacy, keystore.KeyStore.GetVersion())
+	assert.Nil(t, keystore.KeyStore.GetCrypto())
+	assert.Equal(t, cipherPrivate, keystore.KeyStore.GetKey()[0].GetCipherPrivate())
+	legacyPrivate, err := keystorage.Decrypt(cipherPrivate, "dwkxnzn3kl1dlndvtdtvqko9gpaay5vj")
+	assert.NoError(t, err)
+	assert.Equal(t, "0x6cbed15c793ce57650b9877cf6fa156fbef513c4e6134f022a85b1ffdd59b2a1", legacyPrivate)
+}
+
+func TestKeystorage_GetLegacyBlockNumber(t *testing.T) {
+	keystoragePath := copyFixture(t, "keystore_test_keystore.json")
+	data, err := ioutil.ReadFile(keystoragePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = []byte(strings.Replace(string(data), `"block_number":0`, `"block_number":12345`, 1))
+	if err = ioutil.WriteFile(keystoragePath, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	keystore, err := keystorage.NewKeyStorage(Log, keystoragePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, keystore.CheckToken("dwkxnzn3kl1dlndvtdtvqko9gpaay5vj"))
+	assert.NoError(t, keystore.SelectPrivateKey("test"))
+	assert.Equal(t, int64(12345), keystore.GetLegacyBlockNumber())
+
+	// kept through the migration, in case the cursor couldn't be seeded yet
+	keystore, err = keystorage.NewKeyStorage(Log, keystoragePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, keystore.CheckToken("dwkxnzn3kl1dlndvtdtvqko9gpaay5vj"))
+	assert.NoError(t, keystore.SelectPrivateKey("test"))
+	assert.Equal(t, int64(12345), keystore.GetLegacyBlockNumber())
+
+	_, err = keystore.AddSending("sending", "")
+	assert.NoError(t, err)
+	assert.NoError(t, keystore.SelectPrivateKey("sending"))
+	assert.Equal(t, int64(0), keystore.GetLegacyBlockNumber())
+}
+
+func TestKeystorage_Authenticated(t *testing.T) {
+	newKeystore := func() *keystorage.Keystorage {
+		keystore, err := keystorage.NewKeyStorage(Log, filepath.Join(t.TempDir(), "keystore.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = keystore.GenerateToken()
+		assert.NoError(t, err)
+		return keystore
+	}
+
+	// each keystore has its own salt
+	keystore := newKeystore()
+	assert.NotEqual(t, keystore.KeyStore.GetCrypto().Salt, newKeystore().KeyStore.GetCrypto().Salt)
+
+	private, err := keystore.GeneratePrivate("oracle")
+	assert.NoError(t, err)
+	key, err := keystore.GetByAccount("oracle")
+	assert.NoError(t, err)
+	assert.Equal(t, private, key.GetPrivate())
+
+	// a modified cipher text doesn't decrypt
+	cipherPrivate := []byte(key.GetCipherPrivate())
+	if cipherPrivate[20] == 'A' {
+		cipherPrivate[20] = 'B'
+	} else {
+		cipherPrivate[20] = 'A'
+	}
+	key.CipherPrivate = string(cipherPrivate)
+	key, err = keystore.GetByAccount("oracle")
+	assert.NoError(t, err)
+	assert.Empty(t, key.GetPrivate())
+}
+
+func TestKeystorage_V3ImportExport(t *testing.T) {
+	newKeystore := func() *keystorage.Keystorage {
+		keystore, err := keystorage.NewKeyStorage(Log, filepath.Join(t.TempDir(), "keystore.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = keystore.GenerateToken()
+		assert.NoError(t, err)
+		return keystore
+	}
+
+	source := newKeystore()
+	private, err := source.GeneratePrivate("oracle")
+	assert.NoError(t, err)
+	_, err = source.ExportV3("oracle", "")
+	assert.Error(t, err)
+	_, err = source.ExportV3("missing", "passphrase")
+	assert.Error(t, err)
+	keyJSON, err := source.ExportV3("oracle", "passphrase")
+	assert.NoError(t, err)
+
+	exported := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(keyJSON, &exported))
+	assert.Equal(t, float64(3), exported["version"])
+	assert.NotContains(t, string(keyJSON), utils.RemoveHexPrefix(private))
+
+	keystore := newKeystore()
+	_, err = keystore.ImportV3("imported", keyJSON, "wrong", false)
+	assert.Error(t, err)
+	assert.False(t, keystore.ExistsByUsername("imported"))
+
+	address, err := keystore.ImportV3("imported", keyJSON, "passphrase", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x"+exported["address"].(string), strings.ToLower(address))
+	assert.Equal(t, private, keystore.GetByUsername("imported").GetPrivate())
+	assert.False(t, keystore.GetByUsername("imported").GetSending())
+	_, err = keystore.ImportV3("imported", keyJSON, "passphrase", false)
+	assert.Error(t, err)
+
+	_, err = keystore.ImportV3("gas", keyJSON, "passphrase", true)
+	assert.NoError(t, err)
+	assert.Len(t, keystore.GetSending(), 1)
+}
+
+func TestKeystorage_RotateToken(t *testing.T) {
+	dir := t.TempDir()
+	keystoragePath := filepath.Join(dir, "keystore.json")
+	keystore, err := keystorage.NewKeyStorage(Log, keystoragePath)
+	assert.NoError(t, err)
+	oldToken, err := keystore.GenerateToken()
+	assert.NoError(t, err)
+	private, err := keystore.GeneratePrivate("oracle")
+	assert.NoError(t, err)
+	sending, err := keystore.AddSending("gas", "")
+	assert.NoError(t, err)
+	oldSalt := keystore.KeyStore.GetCrypto().Salt
+
+	token, err := keystore.RotateToken()
+	assert.NoError(t, err)
+	assert.NotEqual(t, oldToken, token)
+	assert.Equal(t, token, keystore.KeyStore.Token)
+	assert.NotEqual(t, oldSalt, keystore.KeyStore.GetCrypto().Salt)
+	assert.Equal(t, private, keystore.GetByUsername("oracle").GetPrivate())
+
+	// nothing is left behind by the atomic write
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	keystore, err = keystorage.NewKeyStorage(Log, keystoragePath)
+	assert.NoError(t, err)
+	assert.Error(t, keystore.CheckToken(oldToken))
+	assert.NoError(t, keystore.CheckToken(token))
+	assert.Equal(t, private, keystore.GetByUsername("oracle").GetPrivate())
+	assert.Equal(t, sending, keystore.GetByUsername("gas").GetPrivate())
+	assert.True(t, keystore.GetByUsername("gas").GetSending())
+
+	// a keystore which can't be written is left as it was
+	assert.NoError(t, os.RemoveAll(dir))
+	_, err = keystore.RotateToken()
+	assert.Error(t, err)
+	assert.Equal(t, token, keystore.KeyStore.Token)
+	assert.NoError(t, keystore.CheckToken(token))
+	assert.Equal(t, private, keystore.GetByUsername("oracle").GetPrivate())
+}
+
+func TestKeystorage_RotateTokenConcurrentAdd(t *testing.T) {
+	keystoragePath := filepath.Join(t.TempDir(), "keystore.json")
+	keystore, err := keystorage.NewKeyStorage(Log, keystoragePath)
+	assert.NoError(t, err)
+	_, err = keystore.GenerateToken()
+	assert.NoError(t, err)
+	_, err = keystore.GeneratePrivate("oracle")
+	assert.NoError(t, err)
+
+	// keys added while the token is rotated aren't lost when the rotated keys are swapped in
+	var wg sync.WaitGroup
+	var token string
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		token, err = keystore.RotateToken()
+	}()
+	for i := 0; i < 5; i++ {
+		_, addErr := keystore.AddSending(fmt.Sprintf("gas%d", i), "")
+		assert.NoError(t, addErr)
+	}
+	wg.Wait()
+	assert.NoError(t, err)
+	assert.Len(t, keystore.KeyStore.GetKey(), 6)
+
+	keystore, err = keystorage.NewKeyStorage(Log, keystoragePath)
+	assert.NoError(t, err)
+	assert.NoError(t, keystore.CheckToken(token))
+	assert.Len(t, keystore.GetSending(), 5)
+	for _, key := range keystore.GetAll() {
+		assert.NotEmpty(t, key.GetPrivate(), key.GetAccount())
+	}
+}
+
+func TestKeystorage_RotateTokenLocked(t *testing.T) {
+	keystoragePath := filepath.Join(t.TempDir(), "keystore.json")
+	keystore, err := keystorage.NewKeyStorage(Log, keystoragePath)
+	assert.NoError(t, err)
+	_, err = keystore.GenerateToken()
+	assert.NoError(t, err)
+	_, err = keystore.GeneratePrivate("oracle")
+	assert.NoError(t, err)
+
+	keystore, err = keystorage.NewKeyStorage(Log, keystoragePath)
+	assert.NoError(t, err)
+	_, err = keystore.RotateToken()
+	assert.ErrorIs(t, err, keystorage.ErrLocked)
+}
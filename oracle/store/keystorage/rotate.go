@@ -0,0 +1,70 @@
+package keystorage
+
+import (
+	"encoding/json"
+	"fmt"
+	"oracle/models/keystorage"
+)
+
+// RotateToken replaces the API token with a new one, and re-encrypts every key under it with
+// a new salt. The keystore must be unlocked. Nothing changes, in memory or on disk, unless
+// every key is re-encrypted and the new file is written. Returns the new token.
+func (d *Keystorage) RotateToken() (string, error) {
+	// held until the new keys are swapped in, so keys added meanwhile aren't lost
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.cipherKey == nil {
+		return "", ErrLocked
+	}
+
+	keys := d.KeyStore.GetKey()
+	privates := make([]string, len(keys))
+	for i, key := range keys {
+		private, err := d.decrypt(key.CipherPrivate)
+		if err != nil {
+			return "", fmt.Errorf("decrypt %s: %w", key.Account, err)
+		}
+		privates[i] = private
+	}
+
+	token := GenerateRandomBytes(32)
+	params, err := newCrypto()
+	if err != nil {
+		return "", err
+	}
+	cipherKey, err := deriveKey(params, token)
+	if err != nil {
+		return "", err
+	}
+	hash, err := hashToken(token)
+	if err != nil {
+		return "", err
+	}
+
+	rotated := *d.KeyStore
+	rotated.Version = CurrentVersion
+	rotated.Crypto = params
+	rotated.Hash = hash
+	rotated.Token = token
+	rotated.Key = make([]*keystorage.KeyStorageKeyModel, len(keys))
+	for i, key := range keys {
+		rotatedKey := *key
+		rotatedKey.CipherPrivate, err = encryptGCM(cipherKey, privates[i])
+		if err != nil {
+			return "", err
+		}
+		rotated.Key[i] = &rotatedKey
+	}
+
+	data, err := json.Marshal(&rotated)
+	if err != nil {
+		return "", err
+	}
+	err = d.write(data)
+	if err != nil {
+		return "", err
+	}
+	*d.KeyStore = rotated
+	d.cipherKey = cipherKey
+	return token, nil
+}
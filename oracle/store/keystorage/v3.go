@@ -0,0 +1,67 @@
+package keystorage
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"oracle/config"
+	"oracle/utils"
+)
+
+// ImportV3 adds the key in a Web3 Secret Storage (V3) keystore file, as written by geth and
+// most wallets, decrypting it with the file's passphrase. The key is added as a sending
+// account if sending is true, otherwise as a proving key. Returns the key's address.
+func (d *Keystorage) ImportV3(username string, keyJSON []byte, passphrase string, sending bool) (string, error) {
+	if username == "" {
+		return "", errors.New("account name is required")
+	}
+	if d.ExistsByUsername(username) {
+		return "", fmt.Errorf("This account name is already used")
+	}
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return "", err
+	}
+	privateKey := hexutil.Encode(crypto.FromECDSA(key.PrivateKey))
+	if sending {
+		_, err = d.AddSending(username, privateKey)
+	} else {
+		err = d.AddExisting(username, privateKey)
+	}
+	if err != nil {
+		return "", err
+	}
+	return key.Address.Hex(), nil
+}
+
+// ExportV3 returns an account's key as a V3 keystore file, encrypted with passphrase, for use
+// with other wallets
+func (d *Keystorage) ExportV3(username string, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, errors.New("passphrase is required")
+	}
+	key, err := d.GetByAccount(username)
+	if err != nil {
+		return nil, err
+	}
+	if key.GetPrivate() == "" {
+		return nil, ErrLocked
+	}
+	privateKey, err := crypto.HexToECDSA(utils.RemoveHexPrefix(key.GetPrivate()))
+	if err != nil {
+		return nil, err
+	}
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+	params := utils.GetScryptParams(config.Conf)
+	return keystore.EncryptKey(&keystore.Key{
+		Id:         id,
+		Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		PrivateKey: privateKey,
+	}, passphrase, params.N, params.P)
+}
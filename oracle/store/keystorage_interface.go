@@ -1,11 +1,18 @@
 package store
 
+import "oracle/models/keystorage"
+
 type IKeystorageStore interface {
 	ExistsByUsername(account string) bool
 	GeneratePrivate(username string) (string, error)
 	AddExisting(username string, privateKey string) (err error)
 	SelectPrivateKey(account string) (err error)
 	GetSelectedPrivateKey() string
-	SetBlockNumber(blockNumber int64) (err error)
-	GetBlockNumber() (blockNumber int64, err error)
+	GetAll() []*keystorage.KeyStorageKeyModel
+	AddSending(username string, privateKey string) (string, error)
+	GetSending() []*keystorage.KeyStorageKeyModel
+	ImportV3(username string, keyJSON []byte, passphrase string, sending bool) (string, error)
+	ExportV3(username string, passphrase string) ([]byte, error)
+	RotateToken() (string, error)
+	IsToken(key string) bool
 }
@@ -22,3 +22,12 @@ func NewStore(ctx context.Context, keystorage IKeystorageStore) (*Store, error)
 	}
 	return &store, err
 }
+
+// ForChain returns a store sharing the DB connection and keystore, whose DB queries are
+// limited to the given chain
+func (s *Store) ForChain(chainId int64) *Store {
+	return &Store{
+		Db:         s.Db.ForChain(chainId),
+		Keystorage: s.Keystorage,
+	}
+}
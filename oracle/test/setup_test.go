@@ -2,6 +2,7 @@ package test
 
 import (
 	"context"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/sirupsen/logrus"
 	"math/big"
 	"oracle/chaincall"
@@ -77,13 +78,13 @@ func TestStartEverything(t *testing.T) {
 		t.Error(err)
 	}
 
-	oracleVORCoordinatorListener, err := chainlisten.NewVORCoordinatorListener(Config.VORCoordinatorContractAddress, Config.EthHTTPHost, oracleService, Log, context.Background())
+	oracleVORCoordinatorListener, err := chainlisten.NewVORCoordinatorListener(oracleService.Connection, oracleService, Log, context.Background())
 	if err != nil || oracleVORCoordinatorListener == nil {
 		t.Error(err)
 	}
 
 	//	register proving key
-	tx, err := oracleService.VORCoordinatorCaller.RegisterProvingKey(big.NewInt(100))
+	tx, err := oracleService.VORCoordinatorCaller.RegisterProvingKey(big.NewInt(100), common.HexToAddress(oracleService.VORCoordinatorCaller.OracleAddress()))
 	if err != nil {
 		t.Error(err)
 	}
@@ -100,12 +101,12 @@ func TestStartEverything(t *testing.T) {
 	oracleKeyHash, err := oracleService.VORCoordinatorCaller.HashOfKey()
 
 	//	create requestRandomness
-	rootVORD20Owner, err := chaincall.NewVORD20Caller(Config.ContractCallerAddress, Config.EthHTTPHost, big.NewInt(Config.NetworkID), []byte(Keystore.GetByUsername("owner").Private))
+	rootVORD20Owner, err := chaincall.NewVORD20Caller(oracleService.Connection, Config.ContractCallerAddress, []byte(Keystore.GetByUsername("owner").Private))
 	if err != nil || rootVORD20Owner == nil {
 		t.Error(err)
 	}
 
-	rootMockERC20, err := chaincall.NewMockERC20Caller(Config.MockContractAddress, Config.EthHTTPHost, big.NewInt(Config.NetworkID), []byte(Keystore.GetByUsername("owner").Private))
+	rootMockERC20, err := chaincall.NewMockERC20Caller(oracleService.Connection, Config.MockContractAddress, []byte(Keystore.GetByUsername("owner").Private))
 	if err != nil {
 		t.Error(err)
 	}
@@ -85,6 +85,27 @@ func (p ProofResponse) CryptoProof(s PreSeedData) (Proof, error) {
 	return proof, nil
 }
 
+// VerifyOnChainResponse checks the flat bytes which will be sent to the
+// VORCoordinator, by unmarshaling them and verifying the implied proof against
+// the seed data of the request being fulfilled. It returns the verified proof.
+func VerifyOnChainResponse(m MarshaledOnChainResponse, s PreSeedData) (Proof, error) {
+	p, err := UnmarshalProofResponse(m)
+	if err != nil {
+		return Proof{}, err
+	}
+	if p.PreSeed != s.PreSeed {
+		return Proof{}, errors.Errorf(
+			"on-chain response pre-seed %x does not match request seed %x",
+			p.PreSeed, s.PreSeed)
+	}
+	if p.BlockNum != s.BlockNum {
+		return Proof{}, errors.Errorf(
+			"on-chain response block number %d does not match request block %d",
+			p.BlockNum, s.BlockNum)
+	}
+	return p.CryptoProof(s)
+}
+
 // GenerateProofResponse returns the marshaled proof of the VOR output given the
 // secretKey and the seed computed from the s.PreSeed and the s.BlockHash
 func GenerateProofResponse(secretKey common.Hash, s PreSeedData) (
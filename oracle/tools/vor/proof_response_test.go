@@ -0,0 +1,57 @@
+package vor_test
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"oracle/tools/secp256k1"
+	"oracle/tools/vor"
+	"testing"
+)
+
+func testPreSeedData(t *testing.T) vor.PreSeedData {
+	seed, err := vor.BigToSeed(big.NewInt(123456789))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return vor.PreSeedData{
+		PreSeed:   seed,
+		BlockHash: common.HexToHash("0x6e2dbb0b8a4f4c5b2bc3e6f4f3c1c0a8e2c5b0f6e7d8c9b0a1f2e3d4c5b6a798"),
+		BlockNum:  1234,
+	}
+}
+
+func TestVerifyOnChainResponse(t *testing.T) {
+	secretKey := secp256k1.ScalarToHash(secp256k1.IntToScalar(big.NewInt(42)))
+	s := testPreSeedData(t)
+
+	response, err := vor.GenerateProofResponse(secretKey, s)
+	assert.NoError(t, err)
+
+	proof, err := vor.VerifyOnChainResponse(response, s)
+	assert.NoError(t, err)
+	assert.Equal(t, vor.FinalSeed(s), proof.Seed)
+}
+
+func TestVerifyOnChainResponse_WrongRequestData(t *testing.T) {
+	secretKey := secp256k1.ScalarToHash(secp256k1.IntToScalar(big.NewInt(42)))
+	s := testPreSeedData(t)
+
+	response, err := vor.GenerateProofResponse(secretKey, s)
+	assert.NoError(t, err)
+
+	wrongBlockHash := s
+	wrongBlockHash.BlockHash = common.HexToHash("0x01")
+	_, err = vor.VerifyOnChainResponse(response, wrongBlockHash)
+	assert.Error(t, err)
+
+	wrongBlockNum := s
+	wrongBlockNum.BlockNum = 1235
+	_, err = vor.VerifyOnChainResponse(response, wrongBlockNum)
+	assert.Error(t, err)
+
+	wrongSeed := s
+	wrongSeed.PreSeed, _ = vor.BigToSeed(big.NewInt(987654321))
+	_, err = vor.VerifyOnChainResponse(response, wrongSeed)
+	assert.Error(t, err)
+}
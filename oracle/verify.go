@@ -0,0 +1,43 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"oracle/service"
+)
+
+// verify checks a raw fulfillment proof offline - no config, chain or running daemon needed.
+// Proofs can be taken from the input of a fulfillRandomnessRequest Tx on any block explorer.
+func verify() error {
+	if options.Proof == "" || options.BlockHash == "" {
+		return errors.New("verify requires --proof and --block-hash")
+	}
+	proofBytes, err := hexutil.Decode(options.Proof)
+	if err != nil {
+		return err
+	}
+	proof, err := service.ProofFromBytes(proofBytes)
+	if err != nil {
+		return err
+	}
+
+	v := service.VerifyProof(proof, common.HexToHash(options.BlockHash))
+
+	fmt.Println("public key: ", v.PublicKey)
+	fmt.Println("key hash:   ", v.KeyHash)
+	fmt.Println("seed:       ", v.Seed)
+	fmt.Println("block num:  ", v.BlockNumber)
+	fmt.Println("block hash: ", v.BlockHash)
+	fmt.Println("final seed: ", v.FinalSeed)
+	fmt.Println("gamma:      ", v.Gamma)
+	fmt.Println("output:     ", v.Output)
+	if !v.Valid {
+		fmt.Println("error:      ", v.Error)
+		fmt.Println("result:      FAIL")
+		return nil
+	}
+	fmt.Println("result:      PASS")
+	return nil
+}